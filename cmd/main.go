@@ -1,7 +1,92 @@
 package main
 
-import "github.com/ShekleinAleksey/top-places/internal/app"
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ShekleinAleksey/top-places/internal/app"
+	"github.com/ShekleinAleksey/top-places/internal/config"
+)
+
+const usage = `usage: top-places [--config FILE] [command]
+
+commands:
+  serve          start the HTTP server (default)
+  config print   print the effective configuration with secrets redacted
+
+flags:
+  --config FILE  YAML config file (default config/config.yaml, or $TOP_PLACES_CONFIG)
+
+Settings can be overridden with TOP_PLACES_<SECTION>_<KEY> environment
+variables, e.g. TOP_PLACES_HTTP_ADDR=:9090. Secrets can be read from files:
+DB_PASSWORD_FILE, TOP_PLACES_DB_DSN_FILE, ADMIN_TOKEN_FILE.
+`
 
 func main() {
-	app.Run()
+	configPath := os.Getenv(config.EnvPrefix + "_CONFIG")
+
+	// --config можно указать и до команды, и после нее
+	global := newFlagSet("top-places", &configPath)
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	args := global.Args()
+
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		parse(command, args, &configPath)
+		if err := app.Run(loadConfig(configPath)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "config":
+		if len(args) == 0 || args[0] != "print" {
+			fmt.Fprintf(os.Stderr, "usage: top-places config print [--config FILE]\n")
+			os.Exit(2)
+		}
+		parse("config print", args[1:], &configPath)
+		if err := loadConfig(configPath).Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+func newFlagSet(name string, configPath *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.StringVar(configPath, "config", *configPath, "YAML config file")
+	return fs
+}
+
+// parse разбирает флаги команды; лишние аргументы - ошибка
+func parse(command string, args []string, configPath *string) {
+	fs := newFlagSet(command, configPath)
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments for %s: %v\n\n%s", command, fs.Args(), usage)
+		os.Exit(2)
+	}
+}
+
+func loadConfig(path string) *config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return cfg
 }
@@ -0,0 +1,34 @@
+// Package geodata встраивает в бинарник границы стран для проверки
+// координат мест и обратного геокодирования. Файл boundaries/countries.geojson.gz
+// - упрощенные границы Natural Earth admin-0 (1:50m), сжатые gzip; он
+// обновляется командой make geodata.
+package geodata
+
+import (
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io"
+)
+
+//go:embed boundaries
+var boundaries embed.FS
+
+const countriesFile = "boundaries/countries.geojson.gz"
+
+// Countries возвращает встроенные границы стран в GeoJSON. Если бинарник
+// собран без них, ошибка удовлетворяет errors.Is(err, fs.ErrNotExist).
+func Countries() ([]byte, error) {
+	f, err := boundaries.Open(countriesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress boundaries: %w", err)
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
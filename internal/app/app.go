@@ -1,16 +1,30 @@
 package app
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/ShekleinAleksey/top-places/geodata"
+	"github.com/ShekleinAleksey/top-places/internal/config"
+	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/ShekleinAleksey/top-places/internal/handler"
+	"github.com/ShekleinAleksey/top-places/internal/metrics"
 	"github.com/ShekleinAleksey/top-places/internal/repository"
 	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/ShekleinAleksey/top-places/migrations"
+	"github.com/ShekleinAleksey/top-places/pkg/geo"
 	"github.com/ShekleinAleksey/top-places/pkg/postgres"
-	"github.com/joho/godotenv"
+	"github.com/ShekleinAleksey/top-places/pkg/sqlite"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+	"github.com/ShekleinAleksey/top-places/pkg/wikidata"
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
 // @title BestPlace Service
@@ -18,80 +32,266 @@ import (
 // @description API Service for BestPlace App
 // @host best-place.online:8080
 // @BasePath /
-func Run() {
+//
+// Run запускает сервис с настройками cfg и работает до SIGINT/SIGTERM.
+// Ошибка возвращается, если сервис не удалось запустить или сервер
+// остановился сам (например, занят порт); открытые к этому моменту БД и
+// трассировка закрываются.
+func Run(cfg *config.Config) error {
 	logrus.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
 	logrus.SetOutput(os.Stdout)
+	logrus.AddHook(tracing.LogHook{})
 
-	if err := initConfig(); err != nil {
-		logrus.Fatalf("error initializing config: %s", err.Error())
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "top-places",
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("init tracing: %w", err)
 	}
+	defer shutdownTracing(context.Background())
+
+	logrus.Info("Initializing db...")
 
-	if err := godotenv.Load(); err != nil {
-		logrus.Fatalf("error loading env variables: %s", err.Error())
+	db, err := openDB(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("init db: %w", err)
 	}
+	defer db.Close()
 
-	logrus.Info("Initializing db...")
+	replicas, err := openReplicas(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("init db replicas: %w", err)
+	}
+	for _, replica := range replicas {
+		defer replica.Close()
+	}
+
+	m := metrics.New()
+	m.RegisterDBStats(db.DB, cfg.DB.Driver)
+	for i, replica := range replicas {
+		m.RegisterDBStats(replica.DB, fmt.Sprintf("%s-replica-%d", cfg.DB.Driver, i))
+	}
 
-	db, err := postgres.NewDB(postgres.Config{
-		Host:     viper.GetString("db.host"),
-		Port:     viper.GetString("db.port"),
-		Username: viper.GetString("db.username"),
-		DBName:   viper.GetString("db.dbname"),
-		SSLMode:  viper.GetString("db.sslmode"),
-		Password: os.Getenv("DB_PASSWORD"),
+	logrus.Info("Initializing repository...")
+	repos := repository.NewRepository(db, repository.Options{
+		Observer: m,
+		Replicas: replicas,
+		Retry: repository.RetryPolicy{
+			MaxAttempts: cfg.DB.Retry.MaxAttempts,
+			BaseDelay:   cfg.DB.Retry.BaseDelay,
+			MaxDelay:    cfg.DB.Retry.MaxDelay,
+		},
+		Breaker: newBreaker(cfg.DB.Breaker),
 	})
+	schemaVersion, err := migrations.Latest(schemaMigrations(cfg.DB.Driver))
+	if err != nil {
+		return fmt.Errorf("read migrations: %w", err)
+	}
 
+	boundaries, err := loadBoundaries(cfg.Geo.BoundariesPath)
 	if err != nil {
-		log.Fatalf("error initializing db: %s", err.Error())
+		return fmt.Errorf("load country boundaries: %w", err)
 	}
-	defer db.Close()
 
-	logrus.Info("Initializing repository...")
-	repos := repository.NewRepository(db)
 	logrus.Info("Initializing service...")
-	services := service.NewService(repos)
+	services := service.NewService(repos, service.Options{
+		WikiSource:          newWikidataSource(cfg.Enrichment),
+		WikiLang:            cfg.Enrichment.Language,
+		GeoIndex:            boundaries,
+		BorderToleranceKm:   cfg.Geo.BorderToleranceKm,
+		CoordinatesRequired: cfg.Places.CoordinatesRequired,
+		TrashRetention:      cfg.Trash.Retention,
+		IdempotencyTTL:      cfg.Idempotency.TTL,
+		SchemaVersion:       schemaVersion,
+	})
 	logrus.Info("Initializing handler...")
-	handlers := handler.NewHandler(services)
+	handlers := handler.NewHandler(services, cfg.Admin.Token.Value(), handler.Timeouts{
+		Default: cfg.HTTP.RequestTimeout,
+		Routes:  cfg.HTTP.RouteTimeouts,
+	}, m)
+
+	m.RegisterStats(services.StatsService.Get)
 
 	router := handlers.InitRoutes()
+	servers, err := newServers(cfg, router)
+	if err != nil {
+		return fmt.Errorf("init server: %w", err)
+	}
+
+	workers := newWorkerGroup()
+	workers.Go(func(ctx context.Context) {
+		purgeTrash(ctx, services.TrashService, cfg.Trash.PurgeInterval)
+	})
+	workers.Go(func(ctx context.Context) {
+		purgeIdempotencyKeys(ctx, services.IdempotencyService, cfg.Idempotency.TTL)
+	})
 
-	// certManager := &autocert.Manager{
-	// 	Prompt:     autocert.AcceptTOS,
-	// 	HostPolicy: autocert.HostWhitelist("best-place.online"),
-	// 	Cache:      autocert.DirCache("/var/www/.cache"), // Папка для хранения сертификатов
-	// }
-
-	// // Настройка TLS
-	// tlsConfig := &tls.Config{
-	// 	GetCertificate: certManager.GetCertificate,
-	// 	MinVersion:     tls.VersionTLS12, // Современные безопасные настройки
-	// }
-
-	// // Создание HTTP-сервера с поддержкой HTTPS
-	// srv := &http.Server{
-	// 	Addr:      ":443",
-	// 	Handler:   router,
-	// 	TLSConfig: tlsConfig,
-	// }
-
-	// // Перенаправление HTTP -> HTTPS
-	// go func() {
-	// 	http.ListenAndServe(":80", certManager.HTTPHandler(nil))
-	// }()
-
-	// logrus.Info("Starting HTTPS server...")
-	// if err := srv.ListenAndServeTLS("", ""); err != nil { // Пустые строки, так как сертификаты управляются autocert
-	// 	logrus.Fatalf("Failed to start server: %v", err)
-	// }
-
-	logrus.Info("Starting server...")
-	router.Run(":8080")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return serve(ctx, servers, workers, cfg.HTTP.ShutdownTimeout)
+}
+
+// openDB подключается к БД, выбранной в db.driver. БД SQLite создается при
+// первом запуске, ее миграции применяются автоматически.
+func openDB(cfg config.DB) (*sqlx.DB, error) {
+	switch cfg.Driver {
+	case repository.DriverPostgres:
+		return postgres.NewDB(postgres.Config{
+			DSN:      cfg.DSN.Value(),
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			Username: cfg.Username,
+			DBName:   cfg.DBName,
+			SSLMode:  cfg.SSLMode,
+			Password: cfg.Password.Value(),
+			Pool:     postgresPool(cfg.Pool),
+		})
+	case repository.DriverSQLite:
+		db, err := sqlite.NewDB(sqlite.Config{Path: cfg.Path})
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlite.Migrate(context.Background(), db, migrations.SQLite()); err != nil {
+			db.Close()
+			return nil, err
+		}
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unknown db driver %q", cfg.Driver)
+	}
+}
+
+// openReplicas подключается к репликам из db.replicas
+func openReplicas(cfg config.DB) ([]*sqlx.DB, error) {
+	replicas := make([]*sqlx.DB, 0, len(cfg.Replicas))
+	for i, dsn := range cfg.Replicas {
+		db, err := postgres.NewDB(postgres.Config{DSN: dsn.Value(), Pool: postgresPool(cfg.Pool)})
+		if err != nil {
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		replicas = append(replicas, db)
+	}
+	return replicas, nil
 }
 
-func initConfig() error {
-	viper.AddConfigPath("config")
-	viper.SetConfigName("config")
-	return viper.ReadInConfig()
+// newBreaker создает выключатель БД; nil, если он отключен
+func newBreaker(cfg config.Breaker) *repository.Breaker {
+	if cfg.FailureThreshold == 0 {
+		return nil
+	}
+	return repository.NewBreaker(cfg.FailureThreshold, cfg.Cooldown, func(state string) {
+		if state == entity.BreakerOpen {
+			logrus.Warnf("database circuit breaker is open, requests fail fast for %s", cfg.Cooldown)
+		} else {
+			logrus.Infof("database circuit breaker is %s", state)
+		}
+	})
+}
+
+func postgresPool(cfg config.Pool) postgres.Pool {
+	return postgres.Pool{
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+	}
+}
+
+// schemaMigrations возвращает миграции БД, выбранной в db.driver; по
+// последней из них проверяется готовность схемы
+func schemaMigrations(driver string) fs.FS {
+	if driver == repository.DriverSQLite {
+		return migrations.SQLite()
+	}
+	return migrations.Postgres()
+}
+
+// newWikidataSource выбирает источник обогащения: локальный дамп имеет приоритет над HTTP API
+func newWikidataSource(cfg config.Enrichment) wikidata.Source {
+	if cfg.DumpPath != "" {
+		return wikidata.NewDumpSource(cfg.DumpPath, cfg.Language)
+	}
+	if cfg.Endpoint != "" {
+		return wikidata.NewClient(cfg.Endpoint, cfg.Language)
+	}
+	return nil
+}
+
+// loadBoundaries загружает границы стран из path или, если он пуст,
+// встроенные в бинарник. Без границ проверка координат и /geo/reverse
+// отключены.
+func loadBoundaries(path string) (*geo.Index, error) {
+	if path != "" {
+		return geo.LoadFile(path)
+	}
+
+	data, err := geodata.Countries()
+	if errors.Is(err, fs.ErrNotExist) {
+		logrus.Warn("binary is built without country boundaries (make geodata), coordinate validation is disabled")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return geo.Load(data)
+}
+
+// purgeTrash периодически удаляет из корзины записи с истекшим сроком
+// хранения, пока не отменен ctx. Начатая очистка при остановке сервиса
+// завершается, а не откатывается.
+func purgeTrash(ctx context.Context, trash *service.TrashService, interval time.Duration) {
+	if trash.Retention() <= 0 || interval <= 0 {
+		logrus.Info("trash purge is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := trash.Purge(context.WithoutCancel(ctx))
+		if err != nil {
+			logrus.Errorf("failed to purge trash: %s", err.Error())
+		} else if report.Countries > 0 || report.Places > 0 {
+			logrus.WithFields(logrus.Fields{
+				"countries": report.Countries,
+				"places":    report.Places,
+			}).Info("trash purged")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeIdempotencyKeys удаляет ключи идемпотентности с истекшим сроком
+// действия; просроченный ключ и так считается свободным, очистка только
+// не дает таблице расти
+func purgeIdempotencyKeys(ctx context.Context, idem *service.IdempotencyService, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(ttl)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := idem.Purge(context.WithoutCancel(ctx)); err != nil {
+			logrus.Errorf("failed to purge idempotency keys: %s", err.Error())
+		}
+	}
 }
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// newServer создает HTTP-сервер с настройками из секции http
+func newServer(cfg config.HTTP, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		ErrorLog:          serverErrorLog(),
+	}
+}
+
+// serverErrorLog направляет ошибки соединений (например, TLS handshake) в
+// общий журнал, чтобы они тоже выходили в JSON
+func serverErrorLog() *log.Logger {
+	return log.New(logrus.StandardLogger().WriterLevel(logrus.WarnLevel), "", 0)
+}
+
+// server - HTTP-сервер и файлы сертификата, если он принимает HTTPS
+type server struct {
+	*http.Server
+	certFile, keyFile string
+}
+
+func (s *server) listen() error {
+	if s.TLSConfig != nil {
+		return s.ListenAndServeTLS(s.certFile, s.keyFile)
+	}
+	return s.ListenAndServe()
+}
+
+// serve обслуживает запросы, пока не отменен ctx (SIGINT/SIGTERM) или один
+// из серверов не остановился с ошибкой. Затем серверы перестают принимать
+// соединения и дожидаются завершения начатых запросов, а фоновые задачи
+// workers - завершения текущей итерации; на все это отводится
+// shutdownTimeout.
+func serve(ctx context.Context, servers []*server, workers *workerGroup, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			logrus.Infof("Starting server on %s...", srv.Addr)
+			if err := srv.listen(); !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("server on %s stopped: %w", srv.Addr, err)
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		logrus.Info("Shutting down server...")
+	case err = <-serveErr:
+		logrus.Error(err.Error())
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logrus.Errorf("failed to drain requests on %s: %s", srv.Addr, err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logrus.Errorf("failed to stop background workers: %s", err.Error())
+	}
+	return err
+}
+
+// workerGroup запускает фоновые задачи сервиса и при остановке дожидается
+// их завершения, чтобы БД закрывалась после них
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkerGroup() *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{ctx: ctx, cancel: cancel}
+}
+
+// Go запускает fn; fn должна вернуться после отмены переданного ей контекста
+func (g *workerGroup) Go(fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Stop отменяет контекст задач и ждет их завершения, но не дольше ctx
+func (g *workerGroup) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
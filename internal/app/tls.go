@@ -0,0 +1,161 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/config"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newServers создает основной сервер и, если включен TLS и задан
+// tls.redirect_addr, HTTP-сервер, перенаправляющий на HTTPS. В режиме
+// autocert HTTP-сервер также отвечает на проверки ACME HTTP-01.
+func newServers(cfg *config.Config, handler http.Handler) ([]*server, error) {
+	if cfg.TLS.Mode == config.TLSOff {
+		return []*server{{Server: newServer(cfg.HTTP, handler)}}, nil
+	}
+
+	if cfg.TLS.HSTS.MaxAge > 0 {
+		handler = hsts(handler, hstsValue(cfg.TLS.HSTS))
+	}
+	main := &server{Server: newServer(cfg.HTTP, handler)}
+	main.Protocols = new(http.Protocols)
+	main.Protocols.SetHTTP1(true)
+	main.Protocols.SetHTTP2(cfg.TLS.HTTP2)
+
+	// redirect по умолчанию только перенаправляет; autocert оборачивает его
+	// обработчиком проверок ACME
+	var redirect http.Handler = httpsRedirect(main.Addr)
+
+	switch cfg.TLS.Mode {
+	case config.TLSFiles:
+		main.certFile = cfg.TLS.CertFile
+		main.keyFile = cfg.TLS.KeyFile
+		main.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	case config.TLSAutocert:
+		manager, err := newCertManager(cfg.TLS.Autocert)
+		if err != nil {
+			return nil, err
+		}
+		main.TLSConfig = manager.TLSConfig()
+		main.TLSConfig.MinVersion = tls.VersionTLS12
+		redirect = manager.HTTPHandler(redirect)
+	default:
+		return nil, fmt.Errorf("unknown tls.mode %q", cfg.TLS.Mode)
+	}
+	if !cfg.TLS.HTTP2 {
+		main.TLSConfig.NextProtos = slices.DeleteFunc(main.TLSConfig.NextProtos, func(proto string) bool {
+			return proto == "h2"
+		})
+	}
+
+	servers := []*server{main}
+	if cfg.TLS.RedirectAddr != "" {
+		servers = append(servers, &server{Server: &http.Server{
+			Addr:              cfg.TLS.RedirectAddr,
+			Handler:           redirect,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+			ErrorLog:          serverErrorLog(),
+		}})
+	}
+	return servers, nil
+}
+
+// newCertManager настраивает получение сертификатов по ACME для хостов из
+// tls.autocert.hosts. По умолчанию используется Let's Encrypt; для проверки
+// на тестовом ACME-сервере (Pebble) задаются directory_url и ca_file.
+func newCertManager(cfg config.Autocert) (*autocert.Manager, error) {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.Hosts...),
+		Email:      cfg.Email,
+	}
+	// Без кэша сертификаты запрашиваются заново при каждом запуске и быстро
+	// упираются в ограничения Let's Encrypt
+	if cfg.CacheDir != "" {
+		manager.Cache = autocert.DirCache(cfg.CacheDir)
+	} else {
+		logrus.Warn("tls.autocert.cache_dir is not set, certificates are not cached between restarts")
+	}
+
+	if cfg.DirectoryURL != "" {
+		client := &acme.Client{DirectoryURL: cfg.DirectoryURL}
+		if cfg.CAFile != "" {
+			httpClient, err := acmeHTTPClient(cfg.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			client.HTTPClient = httpClient
+		}
+		manager.Client = client
+	}
+	return manager, nil
+}
+
+// acmeHTTPClient создает клиент, доверяющий корневому сертификату ACME-сервера
+// из caFile
+func acmeHTTPClient(caFile string) (*http.Client, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls.autocert.ca_file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("tls.autocert.ca_file %s contains no certificates", caFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return &http.Client{Transport: transport, Timeout: time.Minute}, nil
+}
+
+// httpsRedirect перенаправляет запросы на тот же адрес по HTTPS на порт
+// основного сервера (mainAddr)
+func httpsRedirect(mainAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(mainAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// hsts добавляет заголовок Strict-Transport-Security к ответам по HTTPS
+func hsts(next http.Handler, value string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			w.Header().Set("Strict-Transport-Security", value)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hstsValue(cfg config.HSTS) string {
+	parts := []string{"max-age=" + strconv.FormatInt(int64(cfg.MaxAge.Seconds()), 10)}
+	if cfg.IncludeSubdomains {
+		parts = append(parts, "includeSubDomains")
+	}
+	if cfg.Preload {
+		parts = append(parts, "preload")
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,36 @@
+// Package buildinfo хранит версию сборки. Значения задаются при сборке:
+//
+//	go build -ldflags "-X github.com/ShekleinAleksey/top-places/internal/buildinfo.Version=v1.2.0
+//	  -X github.com/ShekleinAleksey/top-places/internal/buildinfo.Commit=abc1234"
+//
+// (см. Makefile). Без -ldflags коммит берется из данных VCS, которые go build
+// встраивает в бинарник.
+package buildinfo
+
+import "runtime/debug"
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+func init() {
+	if Commit != "" {
+		return
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			Commit = setting.Value
+		case "vcs.time":
+			if BuildTime == "" {
+				BuildTime = setting.Value
+			}
+		}
+	}
+}
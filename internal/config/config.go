@@ -0,0 +1,414 @@
+// Package config собирает настройки сервиса из нескольких источников. По
+// возрастанию приоритета: значения по умолчанию, YAML-файл (config/config.yaml
+// или путь из --config), переменные окружения TOP_PLACES_<СЕКЦИЯ>_<КЛЮЧ>
+// (например, TOP_PLACES_HTTP_ADDR). Переменные можно задать и в файле .env.
+//
+// Секреты (пароль БД, токен администратора) задаются в окружении или
+// читаются из файлов *_FILE, например DB_PASSWORD_FILE=/run/secrets/db.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/joho/godotenv"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix - префикс переменных окружения, переопределяющих настройки
+const EnvPrefix = "TOP_PLACES"
+
+type Config struct {
+	DB          DB          `mapstructure:"db"`
+	HTTP        HTTP        `mapstructure:"http"`
+	TLS         TLS         `mapstructure:"tls"`
+	Admin       Admin       `mapstructure:"admin"`
+	Enrichment  Enrichment  `mapstructure:"enrichment"`
+	Geo         Geo         `mapstructure:"geo"`
+	Places      Places      `mapstructure:"places"`
+	Trash       Trash       `mapstructure:"trash"`
+	Idempotency Idempotency `mapstructure:"idempotency"`
+	Tracing     Tracing     `mapstructure:"tracing"`
+}
+
+type DB struct {
+	// Driver - postgres или sqlite
+	Driver string `mapstructure:"driver"`
+	// Path - файл БД SQLite
+	Path     string `mapstructure:"path"`
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	DBName   string `mapstructure:"dbname"`
+	SSLMode  string `mapstructure:"sslmode"`
+	Password Secret `mapstructure:"password"`
+	// PasswordFile - файл с паролем; взаимоисключающий с Password
+	PasswordFile string `mapstructure:"password_file"`
+	// DSN - строка подключения PostgreSQL целиком; если задана, host, port,
+	// username, dbname, sslmode и password не используются
+	DSN     Secret `mapstructure:"dsn"`
+	DSNFile string `mapstructure:"dsn_file"`
+	// Replicas - строки подключения реплик PostgreSQL для запросов на чтение
+	Replicas []Secret `mapstructure:"replicas"`
+	Pool     Pool     `mapstructure:"pool"`
+	Retry    Retry    `mapstructure:"retry"`
+	Breaker  Breaker  `mapstructure:"breaker"`
+}
+
+// Retry - повтор чтений и транзакций после временных сбоев БД
+type Retry struct {
+	// MaxAttempts - число попыток вместе с первой; 1 - без повторов
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	BaseDelay   time.Duration `mapstructure:"base_delay"`
+	MaxDelay    time.Duration `mapstructure:"max_delay"`
+}
+
+// Breaker - выключатель БД
+type Breaker struct {
+	// FailureThreshold - сбоев соединения подряд до размыкания; 0 - выключатель не используется
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
+}
+
+// Pool - настройки пула соединений PostgreSQL, общие для основной БД и реплик
+type Pool struct {
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+}
+
+type HTTP struct {
+	Addr              string        `mapstructure:"addr"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	RequestTimeout    time.Duration `mapstructure:"request_timeout"`
+	// RouteTimeouts - ограничения отдельных маршрутов: "МЕТОД шаблон маршрута": время
+	RouteTimeouts map[string]time.Duration `mapstructure:"route_timeouts"`
+}
+
+// Режимы TLS.Mode
+const (
+	TLSOff      = "off"
+	TLSFiles    = "files"
+	TLSAutocert = "autocert"
+)
+
+type TLS struct {
+	Mode         string   `mapstructure:"mode"`
+	CertFile     string   `mapstructure:"cert_file"`
+	KeyFile      string   `mapstructure:"key_file"`
+	RedirectAddr string   `mapstructure:"redirect_addr"`
+	HTTP2        bool     `mapstructure:"http2"`
+	Autocert     Autocert `mapstructure:"autocert"`
+	HSTS         HSTS     `mapstructure:"hsts"`
+}
+
+type Autocert struct {
+	Hosts        []string `mapstructure:"hosts"`
+	CacheDir     string   `mapstructure:"cache_dir"`
+	Email        string   `mapstructure:"email"`
+	DirectoryURL string   `mapstructure:"directory_url"`
+	CAFile       string   `mapstructure:"ca_file"`
+}
+
+type HSTS struct {
+	MaxAge            time.Duration `mapstructure:"max_age"`
+	IncludeSubdomains bool          `mapstructure:"include_subdomains"`
+	Preload           bool          `mapstructure:"preload"`
+}
+
+type Admin struct {
+	// Token - токен административных маршрутов; пустой - маршруты отключены
+	Token     Secret `mapstructure:"token"`
+	TokenFile string `mapstructure:"token_file"`
+}
+
+type Enrichment struct {
+	Language string `mapstructure:"language"`
+	DumpPath string `mapstructure:"dump_path"`
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+type Geo struct {
+	BoundariesPath    string  `mapstructure:"boundaries_path"`
+	BorderToleranceKm float64 `mapstructure:"border_tolerance_km"`
+}
+
+type Places struct {
+	CoordinatesRequired bool `mapstructure:"coordinates_required"`
+}
+
+type Trash struct {
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+type Idempotency struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+type Tracing struct {
+	Endpoint    string  `mapstructure:"endpoint"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// Secret - строка, которая не попадает в журналы и вывод config print
+type Secret string
+
+const redacted = "[redacted]"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// Value возвращает сам секрет
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// defaults - значения всех настроек по умолчанию. Ключ должен быть здесь,
+// даже если по умолчанию он пуст: иначе viper не переопределит его из
+// окружения.
+var defaults = map[string]any{
+	"db.driver":                    repository.DriverPostgres,
+	"db.path":                      "top-places.db",
+	"db.host":                      "localhost",
+	"db.port":                      "5432",
+	"db.username":                  "postgres",
+	"db.dbname":                    "top_place",
+	"db.sslmode":                   "disable",
+	"db.password":                  "",
+	"db.password_file":             "",
+	"db.dsn":                       "",
+	"db.dsn_file":                  "",
+	"db.replicas":                  []string{},
+	"db.pool.max_open_conns":       25,
+	"db.pool.max_idle_conns":       25,
+	"db.pool.conn_max_lifetime":    "30m",
+	"db.pool.conn_max_idle_time":   "5m",
+	"db.retry.max_attempts":        3,
+	"db.retry.base_delay":          "50ms",
+	"db.retry.max_delay":           "1s",
+	"db.breaker.failure_threshold": 5,
+	"db.breaker.cooldown":          "10s",
+	"http.addr":                    ":8080",
+	"http.read_timeout":            "30s",
+	"http.read_header_timeout":     "10s",
+	"http.write_timeout":           "0s",
+	"http.idle_timeout":            "120s",
+	"http.max_header_bytes":        1 << 20,
+	"http.shutdown_timeout":        "30s",
+	"http.request_timeout":         "30s",
+	"http.route_timeouts":          map[string]any{},
+	"tls.mode":                     TLSOff,
+	"tls.cert_file":                "",
+	"tls.key_file":                 "",
+	"tls.redirect_addr":            "",
+	"tls.http2":                    true,
+	"tls.autocert.hosts":           []string{},
+	"tls.autocert.cache_dir":       "",
+	"tls.autocert.email":           "",
+	"tls.autocert.directory_url":   "",
+	"tls.autocert.ca_file":         "",
+	"tls.hsts.max_age":             "0s",
+	"tls.hsts.include_subdomains":  false,
+	"tls.hsts.preload":             false,
+	"admin.token":                  "",
+	"admin.token_file":             "",
+	"enrichment.language":          "en",
+	"enrichment.dump_path":         "",
+	"enrichment.endpoint":          "",
+	"geo.boundaries_path":          "",
+	"geo.border_tolerance_km":      10,
+	"places.coordinates_required":  false,
+	"trash.retention":              "720h",
+	"trash.purge_interval":         "1h",
+	"idempotency.ttl":              "24h",
+	"tracing.endpoint":             "",
+	"tracing.sample_ratio":         1,
+}
+
+// legacyEnv - переменные окружения, которые сервис читал до появления
+// TOP_PLACES_*; они по-прежнему работают, но с меньшим приоритетом
+var legacyEnv = map[string]string{
+	"db.password":      "DB_PASSWORD",
+	"db.password_file": "DB_PASSWORD_FILE",
+	"admin.token":      "ADMIN_TOKEN",
+	"admin.token_file": "ADMIN_TOKEN_FILE",
+}
+
+// Load читает настройки. path - YAML-файл; если он пуст, используется
+// config/config.yaml, а при его отсутствии - только значения по умолчанию и
+// окружение. Загруженные настройки проверяются.
+func Load(path string) (*Config, error) {
+	// .env нужен только для локального запуска
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for key, env := range legacyEnv {
+		prefixed := EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if err := v.BindEnv(key, prefixed, env); err != nil {
+			return nil, err
+		}
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+	} else {
+		v.AddConfigPath("config")
+		v.SetConfigName("config")
+		if err := v.ReadInConfig(); err != nil && !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+	}
+
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.readSecrets(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// readSecrets подставляет секреты из файлов *_file
+func (c *Config) readSecrets() error {
+	secrets := []struct {
+		key   string
+		value *Secret
+		file  string
+	}{
+		{"db.password", &c.DB.Password, c.DB.PasswordFile},
+		{"db.dsn", &c.DB.DSN, c.DB.DSNFile},
+		{"admin.token", &c.Admin.Token, c.Admin.TokenFile},
+	}
+	for _, s := range secrets {
+		if s.file == "" {
+			continue
+		}
+		if *s.value != "" {
+			return fmt.Errorf("invalid config: %s and %s_file are both set", s.key, s.key)
+		}
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_file: %w", s.key, err)
+		}
+		*s.value = Secret(strings.TrimRight(string(data), "\r\n"))
+	}
+	return nil
+}
+
+// Validate проверяет настройки и возвращает все найденные ошибки сразу, по
+// одной на строку
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	switch c.DB.Driver {
+	case repository.DriverPostgres:
+		if c.DB.DSN == "" {
+			check(c.DB.Host != "", "db.host", "is required for postgres")
+			check(c.DB.Port != "", "db.port", "is required for postgres")
+			check(c.DB.Username != "", "db.username", "is required for postgres")
+			check(c.DB.DBName != "", "db.dbname", "is required for postgres")
+		}
+		for i, dsn := range c.DB.Replicas {
+			check(dsn != "", "db.replicas", "entry %d is empty", i)
+		}
+	case repository.DriverSQLite:
+		check(c.DB.Path != "", "db.path", "is required for sqlite")
+		check(c.DB.DSN == "", "db.dsn", "is not supported for sqlite")
+		check(len(c.DB.Replicas) == 0, "db.replicas", "are not supported for sqlite")
+	default:
+		check(false, "db.driver", "must be postgres or sqlite, got %q", c.DB.Driver)
+	}
+
+	check(c.DB.Pool.MaxOpenConns >= 0, "db.pool.max_open_conns", "must not be negative")
+	check(c.DB.Pool.MaxIdleConns >= 0, "db.pool.max_idle_conns", "must not be negative")
+	check(c.DB.Retry.MaxAttempts >= 1, "db.retry.max_attempts", "must be at least 1")
+	check(c.DB.Retry.BaseDelay <= c.DB.Retry.MaxDelay, "db.retry.base_delay", "must not exceed db.retry.max_delay")
+	check(c.DB.Breaker.FailureThreshold >= 0, "db.breaker.failure_threshold", "must not be negative")
+	check(c.DB.Breaker.FailureThreshold == 0 || c.DB.Breaker.Cooldown > 0, "db.breaker.cooldown", "must be positive when the breaker is enabled")
+
+	check(c.HTTP.Addr != "", "http.addr", "is required")
+	durations := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"db.pool.conn_max_lifetime", c.DB.Pool.ConnMaxLifetime},
+		{"db.pool.conn_max_idle_time", c.DB.Pool.ConnMaxIdleTime},
+		{"db.retry.base_delay", c.DB.Retry.BaseDelay},
+		{"db.retry.max_delay", c.DB.Retry.MaxDelay},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+		{"http.request_timeout", c.HTTP.RequestTimeout},
+		{"tls.hsts.max_age", c.TLS.HSTS.MaxAge},
+		{"trash.retention", c.Trash.Retention},
+		{"trash.purge_interval", c.Trash.PurgeInterval},
+		{"idempotency.ttl", c.Idempotency.TTL},
+	}
+	for _, d := range durations {
+		check(d.value >= 0, d.key, "must not be negative")
+	}
+	check(c.HTTP.MaxHeaderBytes >= 0, "http.max_header_bytes", "must not be negative")
+	for _, route := range slices.Sorted(maps.Keys(c.HTTP.RouteTimeouts)) {
+		d := c.HTTP.RouteTimeouts[route]
+		method, path, _ := strings.Cut(strings.TrimSpace(route), " ")
+		check(method != "" && strings.HasPrefix(strings.TrimSpace(path), "/"), "http.route_timeouts", "key %q must look like \"METHOD /route\"", route)
+		check(d >= 0, "http.route_timeouts", "timeout of %q must not be negative", route)
+	}
+
+	switch c.TLS.Mode {
+	case TLSOff:
+	case TLSFiles:
+		check(c.TLS.CertFile != "", "tls.cert_file", "is required in %s mode", TLSFiles)
+		check(c.TLS.KeyFile != "", "tls.key_file", "is required in %s mode", TLSFiles)
+	case TLSAutocert:
+		check(len(c.TLS.Autocert.Hosts) > 0, "tls.autocert.hosts", "is required in %s mode", TLSAutocert)
+	default:
+		check(false, "tls.mode", "must be %s, %s or %s, got %q", TLSOff, TLSFiles, TLSAutocert, c.TLS.Mode)
+	}
+
+	check(c.Enrichment.Language != "", "enrichment.language", "is required")
+	check(c.Geo.BorderToleranceKm >= 0, "geo.border_tolerance_km", "must not be negative")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Print выводит настройки в YAML в формате config.yaml: длительности -
+// строками ("30s"), секреты скрыты. Вывод можно использовать как файл
+// настроек.
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(4)
+	if err := enc.Encode(node(reflect.ValueOf(*c))); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func node(v reflect.Value) *yaml.Node {
+	switch value := v.Interface().(type) {
+	case Secret:
+		return scalar(value.String())
+	case time.Duration:
+		return scalar(value.String())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for i := range v.NumField() {
+			n.Content = append(n.Content, plain(v.Type().Field(i).Tag.Get("mapstructure")), node(v.Field(i)))
+		}
+		return n
+	case reflect.Map:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, k := range keys {
+			n.Content = append(n.Content, scalar(k.String()), node(v.MapIndex(k)))
+		}
+		return n
+	case reflect.Slice:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := range v.Len() {
+			n.Content = append(n.Content, node(v.Index(i)))
+		}
+		return n
+	case reflect.String:
+		return scalar(v.String())
+	case reflect.Bool:
+		return plain(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int64:
+		return plain(strconv.FormatInt(v.Int(), 10))
+	case reflect.Float64:
+		return plain(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	default:
+		return scalar(fmt.Sprint(v.Interface()))
+	}
+}
+
+// plain - значение без кавычек: ключ, число или логическое значение
+func plain(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: s}
+}
+
+// scalar - строка в двойных кавычках, как в config.yaml
+func scalar(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s, Style: yaml.DoubleQuotedStyle}
+}
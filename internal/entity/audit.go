@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы сущностей, изменения которых видны только в журнале аудита (без истории ревизий)
+const (
+	EntityRegion = "region"
+	EntityCity   = "city"
+)
+
+// Результат запроса в журнале аудита
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEntry - запись журнала аудита об одном изменяющем запросе. Если запрос
+// затронул несколько сущностей (страну вместе с местами), на каждую
+// пишется отдельная запись с тем же request_id.
+type AuditEntry struct {
+	ID        int64     `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Actor     string    `json:"actor" db:"actor"`
+	// ClaimedActor - X-User запроса без токена администратора; не проверяется
+	ClaimedActor string          `json:"claimed_actor,omitempty" db:"claimed_actor"`
+	IP           string          `json:"ip" db:"ip"`
+	RequestID    string          `json:"request_id" db:"request_id"`
+	Method       string          `json:"method" db:"method"`
+	Route        string          `json:"route" db:"route"`
+	Path         string          `json:"path" db:"path"`
+	Status       int             `json:"status" db:"status"`
+	Outcome      string          `json:"outcome" db:"outcome"`
+	ErrorCode    string          `json:"error_code,omitempty" db:"error_code"`
+	EntityType   string          `json:"entity_type,omitempty" db:"entity_type"`
+	EntityID     *int            `json:"entity_id,omitempty" db:"entity_id"`
+	Before       json.RawMessage `json:"before,omitempty" db:"before"`
+	After        json.RawMessage `json:"after,omitempty" db:"after"`
+	DurationMs   int             `json:"duration_ms" db:"duration_ms"`
+}
+
+// AuditChange - состояние сущности до и после изменения (null - сущности не было)
+type AuditChange struct {
+	EntityType string
+	EntityID   int
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+// AuditFilter - фильтр журнала аудита; нулевые значения не ограничивают выборку
+type AuditFilter struct {
+	Actor      string
+	EntityType string
+	EntityID   int
+	Outcome    string
+	RequestID  string
+	From       time.Time
+	To         time.Time
+	// BeforeID - курсор постраничного чтения: журнал отдается от новых записей
+	// к старым, следующая страница - записи с id меньше последнего полученного
+	BeforeID int64
+	Limit    int
+}
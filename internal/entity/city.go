@@ -0,0 +1,15 @@
+package entity
+
+type City struct {
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name" binding:"required"`
+	Description string `json:"description" db:"description"`
+	CountryID   int    `json:"country_id" db:"country_id" binding:"required"`
+	RegionID    *int   `json:"region_id,omitempty" db:"region_id"`
+}
+
+// CityFilter - фильтр списка городов; нулевые значения не ограничивают выборку
+type CityFilter struct {
+	CountryID int
+	RegionID  int
+}
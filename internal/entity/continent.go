@@ -0,0 +1,6 @@
+package entity
+
+type Continent struct {
+	Code string `json:"code" db:"code"`
+	Name string `json:"name" db:"name"`
+}
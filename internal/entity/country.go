@@ -1,11 +1,27 @@
 package entity
 
+import "time"
+
 type Country struct {
-	ID          int    `json:"id" db:"id"`
-	Name        string `json:"name" db:"name" binding:"required"`
-	Capital     string `json:"capital" db:"capital" binding:"required"`
-	Language    string `json:"language" db:"language"`
-	Currency    string `json:"currency" db:"currency"`
-	Description string `json:"description" db:"description"`
-	PhotoURL    string `json:"url" db:"photo_url"`
+	ID            int      `json:"id" db:"id"`
+	Name          string   `json:"name" db:"name" binding:"required"`
+	Capital       string   `json:"capital" db:"capital" binding:"required"`
+	Language      string   `json:"language" db:"language"`
+	Currency      string   `json:"currency" db:"currency"`
+	Description   string   `json:"description" db:"description"`
+	PhotoURL      string   `json:"url" db:"photo_url"`
+	WikidataID    string   `json:"wikidata_id" db:"wikidata_id"`
+	Population    *int64   `json:"population,omitempty" db:"population"`
+	Area          *float64 `json:"area,omitempty" db:"area"`
+	FlagURL       string   `json:"flag_url" db:"flag_url"`
+	ISO2          string   `json:"iso2" db:"iso2"`
+	ISO3          string   `json:"iso3" db:"iso3"`
+	ContinentCode string   `json:"continent_code" db:"continent_code"`
+	Languages     []string `json:"languages" db:"-"`
+	Currencies    []string `json:"currencies" db:"-"`
+	// Version увеличивается при каждом изменении и служит ETag страны
+	Version   int64     `json:"version" db:"version"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// DeletedAt задан у записей в корзине
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
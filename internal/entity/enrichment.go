@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Статусы предложений по обогащению
+const (
+	EnrichmentPending  = "pending"
+	EnrichmentApplied  = "applied"
+	EnrichmentRejected = "rejected"
+)
+
+// Поля страны, которые может заполнять обогащение
+const (
+	CountryFieldCapital     = "capital"
+	CountryFieldLanguage    = "language"
+	CountryFieldCurrency    = "currency"
+	CountryFieldDescription = "description"
+	CountryFieldPopulation  = "population"
+	CountryFieldArea        = "area"
+	CountryFieldFlagURL     = "flag_url"
+	CountryFieldWikidataID  = "wikidata_id"
+)
+
+// CountryEnrichment - предложенное (или уже примененное) значение поля страны
+// вместе с источником, откуда оно получено
+type CountryEnrichment struct {
+	ID            int        `json:"id" db:"id"`
+	CountryID     int        `json:"country_id" db:"country_id"`
+	Field         string     `json:"field" db:"field"`
+	CurrentValue  string     `json:"current_value" db:"current_value"`
+	ProposedValue string     `json:"proposed_value" db:"proposed_value"`
+	Source        string     `json:"source" db:"source"`
+	SourceRef     string     `json:"source_ref" db:"source_ref"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+// EnrichmentReport - итог запуска обогащения
+type EnrichmentReport struct {
+	Countries int      `json:"countries"`
+	Matched   int      `json:"matched"`
+	Applied   int      `json:"applied"`
+	Proposed  int      `json:"proposed"`
+	Unmatched []string `json:"unmatched"`
+}
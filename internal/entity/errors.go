@@ -0,0 +1,11 @@
+package entity
+
+// FieldError - ошибка в одном поле входных данных
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
@@ -0,0 +1,12 @@
+package entity
+
+// ReverseGeocode - страна, в границах которой лежит точка. Country заполнена,
+// если страна с таким кодом ISO есть в базе.
+type ReverseGeocode struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	ISO2      string   `json:"iso2"`
+	ISO3      string   `json:"iso3"`
+	Name      string   `json:"name"`
+	Country   *Country `json:"country,omitempty"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// Состояния выключателя БД
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// Health - результат проверки готовности сервиса. Status - HealthOK, если
+// все проверки прошли.
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+	// Breaker - состояние выключателя БД, если он включен
+	Breaker *BreakerStatus `json:"breaker,omitempty"`
+}
+
+// BreakerStatus - состояние выключателя БД. Failures - сбоев соединения
+// подряд; RetryAfterSeconds - через сколько выключатель пропустит пробный
+// запрос, если он разомкнут.
+type BreakerStatus struct {
+	State             string `json:"state"`
+	Failures          int    `json:"failures"`
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty"`
+}
+
+type HealthCheck struct {
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// ServiceStatus - подробное состояние сервиса для администратора
+type ServiceStatus struct {
+	Version       string        `json:"version"`
+	Commit        string        `json:"commit,omitempty"`
+	BuildTime     string        `json:"build_time,omitempty"`
+	GoVersion     string        `json:"go_version"`
+	StartedAt     time.Time     `json:"started_at"`
+	UptimeSeconds int64         `json:"uptime_seconds"`
+	Health        Health        `json:"health"`
+	Config        ServiceConfig `json:"config"`
+}
+
+// ServiceConfig - основные настройки, с которыми запущен сервис
+type ServiceConfig struct {
+	DBDriver            string `json:"db_driver"`
+	SchemaVersion       int    `json:"schema_version"`
+	EnrichmentEnabled   bool   `json:"enrichment_enabled"`
+	EnrichmentLanguage  string `json:"enrichment_language"`
+	GeoValidation       bool   `json:"geo_validation"`
+	CoordinatesRequired bool   `json:"coordinates_required"`
+	TrashRetention      string `json:"trash_retention"`
+	IdempotencyTTL      string `json:"idempotency_ttl"`
+}
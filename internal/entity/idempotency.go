@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord - сохраненный результат запроса с заголовком
+// Idempotency-Key. Status равен 0, пока первый запрос еще выполняется.
+type IdempotencyRecord struct {
+	Actor       string            `db:"actor"`
+	Key         string            `db:"key"`
+	RequestHash string            `db:"request_hash"`
+	Status      int               `db:"status"`
+	Headers     map[string]string `db:"-"`
+	Body        []byte            `db:"body"`
+	CreatedAt   time.Time         `db:"created_at"`
+}
+
+// Completed - получен ли уже ответ на запрос
+func (r *IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
@@ -1,14 +1,40 @@
 package entity
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/pkg/geo"
+)
+
+// Place - достопримечательность. Координаты необязательны (nil - не заданы).
 type Place struct {
 	ID          int      `json:"id" db:"id"`
 	Name        string   `json:"name" db:"name" binding:"required"`
 	Description string   `json:"description" db:"description"`
-	Longitude   float64  `json:"longitude" db:"longitude"`
-	Latitude    float64  `json:"latitude" db:"latitude"`
-	CountryID   int      `json:"-" db:"country_id"`
-	Country     Country  `json:"country" db:"-"`
+	Longitude   *float64 `json:"longitude" db:"longitude"`
+	Latitude    *float64 `json:"latitude" db:"latitude"`
+	CountryID   int      `json:"country_id" db:"country_id"`
+	RegionID    *int     `json:"region_id,omitempty" db:"region_id"`
+	CityID      *int     `json:"city_id,omitempty" db:"city_id"`
+	Country     Country  `json:"country" db:"-" binding:"-"`
 	PhotoURLs   []string `json:"url" db:"-"`
+	// Version увеличивается при каждом изменении места или его фото
+	Version   int64     `json:"version" db:"version"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// DeletedAt задан у записей в корзине
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// PlaceFilter - фильтр списка мест по уровням географической иерархии;
+// нулевые значения не ограничивают выборку
+type PlaceFilter struct {
+	ContinentCode string
+	CountryID     int
+	RegionID      int
+	CityID        int
 }
 
 type PlacePhoto struct {
@@ -16,3 +42,74 @@ type PlacePhoto struct {
 	PlaceID int    `json:"place_id" db:"place_id"`
 	URL     string `json:"url" db:"url"`
 }
+
+// UnmarshalJSON принимает координаты числами, строками с десятичными
+// градусами или в формате DMS ("41°43′N"), а также парой в поле coordinates
+// ("41°43′N 44°47′E").
+func (p *Place) UnmarshalJSON(data []byte) error {
+	type plain Place
+	aux := struct {
+		*plain
+		Latitude    json.RawMessage `json:"latitude"`
+		Longitude   json.RawMessage `json:"longitude"`
+		Coordinates *string         `json:"coordinates"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	lat, err := parseCoordinateJSON("latitude", aux.Latitude)
+	if err != nil {
+		return err
+	}
+	lon, err := parseCoordinateJSON("longitude", aux.Longitude)
+	if err != nil {
+		return err
+	}
+
+	if aux.Coordinates != nil && *aux.Coordinates != "" {
+		if lat != nil || lon != nil {
+			return &FieldError{Field: "coordinates", Message: "use either coordinates or latitude/longitude, not both"}
+		}
+		pairLat, pairLon, err := geo.ParsePair(*aux.Coordinates)
+		if err != nil {
+			return &FieldError{Field: "coordinates", Message: err.Error()}
+		}
+		lat, lon = &pairLat, &pairLon
+	}
+
+	p.Latitude, p.Longitude = lat, lon
+	return nil
+}
+
+func parseCoordinateJSON(field string, raw json.RawMessage) (*float64, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var number float64
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return &number, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return nil, &FieldError{Field: field, Message: "must be a number or a coordinate string"}
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	value, hemisphere, err := geo.ParseCoordinate(text)
+	if err != nil {
+		return nil, &FieldError{Field: field, Message: err.Error()}
+	}
+	if field == "latitude" && hemisphere.IsLongitude() || field == "longitude" && hemisphere.IsLatitude() {
+		return nil, &FieldError{
+			Field:   field,
+			Message: fmt.Sprintf("hemisphere %c does not belong to %s; latitude and longitude appear to be swapped", hemisphere, field),
+		}
+	}
+	return &value, nil
+}
@@ -0,0 +1,10 @@
+package entity
+
+// Region - административный регион страны (область, штат, край)
+type Region struct {
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name" binding:"required"`
+	Code        string `json:"code" db:"code"`
+	Description string `json:"description" db:"description"`
+	CountryID   int    `json:"country_id" db:"country_id" binding:"required"`
+}
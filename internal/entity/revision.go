@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы сущностей, для которых ведется история изменений
+const (
+	EntityCountry = "country"
+	EntityPlace   = "place"
+)
+
+// Действия, создающие ревизию
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// Revision - полный снимок сущности после изменения (для delete - последнее
+// состояние перед удалением). Номера ревизий идут подряд для каждой сущности.
+type Revision struct {
+	ID         int64           `json:"-" db:"id"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   int             `json:"entity_id" db:"entity_id"`
+	Revision   int             `json:"revision" db:"revision"`
+	Action     string          `json:"action" db:"action"`
+	Actor      string          `json:"actor" db:"actor"`
+	Snapshot   json.RawMessage `json:"snapshot" db:"snapshot"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// RevisionDiff - поля, отличающиеся между двумя ревизиями
+type RevisionDiff struct {
+	EntityType string        `json:"entity_type"`
+	EntityID   int           `json:"entity_id"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// FieldChange - значение поля в ревизиях from и to (null - поля нет)
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// Stats - число записей, не находящихся в корзине
+type Stats struct {
+	Countries int64 `json:"countries" db:"countries"`
+	Places    int64 `json:"places" db:"places"`
+	// Photos - фото мест, не находящихся в корзине
+	Photos int64 `json:"photos" db:"photos"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// TrashItem - удаленная страна или место, которые еще можно восстановить
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CountryID int       `json:"country_id,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt - когда запись будет удалена окончательно
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// CountryDeletePreview - записи, которые удалятся вместе со страной. Места
+// попадают в корзину вместе с ней; регионы и города остаются в базе и
+// удаляются только при окончательной очистке страны.
+type CountryDeletePreview struct {
+	CountryID int        `json:"country_id"`
+	Places    []PlaceRef `json:"places"`
+	Photos    int        `json:"photos"`
+	Regions   int        `json:"regions"`
+	Cities    int        `json:"cities"`
+}
+
+type PlaceRef struct {
+	ID   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
+
+// PurgeReport - сколько записей окончательно удалено очисткой корзины
+type PurgeReport struct {
+	Countries int64 `json:"countries"`
+	Places    int64 `json:"places"`
+}
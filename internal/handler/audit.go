@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// auditMiddleware записывает в журнал аудита каждый изменяющий запрос: кто,
+// откуда и с каким результатом его выполнил, а также состояние затронутых
+// сущностей до и после изменения, которое собирают сервисы. Журнал пишется
+// после ответа; ошибка записи только логируется.
+func auditMiddleware(audit *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		ctx, trail := service.WithAuditTrail(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		start := time.Now()
+
+		c.Next()
+
+		base := entity.AuditEntry{
+			Actor:        service.ActorFrom(c.Request.Context()),
+			ClaimedActor: service.ClaimedActorFrom(c.Request.Context()),
+			IP:           c.ClientIP(),
+			RequestID:    c.GetString(requestIDKey),
+			Method:       c.Request.Method,
+			Route:        c.FullPath(),
+			Path:         c.Request.URL.Path,
+			Status:       c.Writer.Status(),
+			Outcome:      entity.AuditSuccess,
+			ErrorCode:    c.GetString(problemCodeKey),
+			DurationMs:   int(time.Since(start).Milliseconds()),
+		}
+		if base.Status >= http.StatusBadRequest {
+			base.Outcome = entity.AuditFailure
+		}
+
+		entries := []entity.AuditEntry{}
+		for _, change := range trail.Changes() {
+			e := base
+			id := change.EntityID
+			e.EntityType, e.EntityID = change.EntityType, &id
+			e.Before, e.After = change.Before, change.After
+			entries = append(entries, e)
+		}
+		// Неудачные запросы и запросы без изменений сущностей пишутся одной
+		// записью; сущность берется из маршрута
+		if len(entries) == 0 {
+			e := base
+			e.EntityType = routeEntity(c)
+			if id, err := strconv.Atoi(c.Param("id")); err == nil && e.EntityType != "" {
+				e.EntityID = &id
+			}
+			entries = append(entries, e)
+		}
+
+		// Запись журнала не должна срываться из-за отключения клиента или
+		// истекшего времени запроса
+		ctx = context.WithoutCancel(c.Request.Context())
+		for i := range entries {
+			if err := audit.Record(ctx, &entries[i]); err != nil {
+				requestLogger(c).WithError(err).Error("failed to write audit entry")
+			}
+		}
+	}
+}
+
+// routeEntity определяет тип сущности по маршруту запроса
+func routeEntity(c *gin.Context) string {
+	route := c.FullPath()
+	switch {
+	case strings.HasPrefix(route, "/countries/"):
+		return entity.EntityCountry
+	case strings.HasPrefix(route, "/places/"):
+		return entity.EntityPlace
+	case strings.HasPrefix(route, "/regions/"):
+		return entity.EntityRegion
+	case strings.HasPrefix(route, "/cities/"):
+		return entity.EntityCity
+	case strings.HasPrefix(route, "/admin/trash/:type/"):
+		return c.Param("type")
+	}
+	return ""
+}
+
+type AuditHandler struct {
+	service *service.AuditService
+}
+
+func NewAuditHandler(service *service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListAudit godoc
+// @Summary Audit log
+// @Tags Admin
+// @Description Mutating API calls, newest first. Page with before_id set to the last received id. With format=ndjson (or Accept: application/x-ndjson) all matching entries are streamed oldest first, one JSON object per line, and limit is ignored.
+// @ID list-audit
+// @Produce  json
+// @Produce  application/x-ndjson
+// @Security AdminToken
+// @Param actor query string false "Actor (X-User header of admin-token requests, admin or anonymous)"
+// @Param entity_type query string false "country, place, region or city"
+// @Param entity_id query int false "Entity ID"
+// @Param outcome query string false "success or failure"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Start time, RFC 3339"
+// @Param to query string false "End time (exclusive), RFC 3339"
+// @Param before_id query int false "Return entries with a smaller id"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param format query string false "json or ndjson"
+// @Success 200 {array} entity.AuditEntry
+// @Failure 400,401 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/audit [get]
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("format") == "ndjson" || strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		h.exportAudit(c, filter)
+		return
+	}
+
+	entries, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// exportAudit отдает журнал построчно по мере чтения из базы. Заголовки
+// пишутся с первой записью: до нее ошибку еще можно вернуть обычным ответом.
+func (h *AuditHandler) exportAudit(c *gin.Context, filter entity.AuditFilter) {
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			c.Header("Content-Type", ndjsonContentType)
+			c.Status(http.StatusOK)
+		}
+	}
+
+	enc := json.NewEncoder(c.Writer)
+	err := h.service.Export(c.Request.Context(), filter, func(e *entity.AuditEntry) error {
+		start()
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		_ = c.Error(err)
+		return
+	}
+	if err != nil {
+		requestLogger(c).WithError(err).Error("audit export interrupted")
+		return
+	}
+	start()
+}
+
+func auditFilter(c *gin.Context) (entity.AuditFilter, bool) {
+	filter := entity.AuditFilter{
+		Actor:      c.Query("actor"),
+		EntityType: c.Query("entity_type"),
+		Outcome:    c.Query("outcome"),
+		RequestID:  c.Query("request_id"),
+	}
+
+	var ok bool
+	if filter.EntityID, ok = queryInt(c, "entity_id"); !ok {
+		return filter, false
+	}
+	if filter.Limit, ok = queryInt(c, "limit"); !ok {
+		return filter, false
+	}
+	beforeID, ok := queryInt(c, "before_id")
+	if !ok {
+		return filter, false
+	}
+	filter.BeforeID = int64(beforeID)
+	if filter.From, ok = queryTime(c, "from"); !ok {
+		return filter, false
+	}
+	if filter.To, ok = queryTime(c, "to"); !ok {
+		return filter, false
+	}
+	return filter, true
+}
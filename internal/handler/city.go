@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CityHandler struct {
+	service *service.CityService
+}
+
+func NewCityHandler(service *service.CityService) *CityHandler {
+	return &CityHandler{service: service}
+}
+
+// @Summary Get cities
+// @Tags Cities
+// @Description List cities, optionally filtered by country and region
+// @ID get-cities
+// @Produce  json
+// @Param country_id query int false "Country ID"
+// @Param region_id query int false "Region ID"
+// @Success 200 {array} entity.City
+// @Failure 400 {object} problem
+// @Failure 500 {object} problem
+// @Router /cities/ [get]
+func (h *CityHandler) GetCities(c *gin.Context) {
+	countryID, ok := queryInt(c, "country_id")
+	if !ok {
+		return
+	}
+	regionID, ok := queryInt(c, "region_id")
+	if !ok {
+		return
+	}
+
+	cities, err := h.service.GetAll(c.Request.Context(), entity.CityFilter{CountryID: countryID, RegionID: regionID})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// @Summary Get city by ID
+// @Tags Cities
+// @Description Get city by ID
+// @ID get-city-by-id
+// @Produce  json
+// @Param id path int true "City ID"
+// @Success 200 {object} entity.City
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /cities/{id} [get]
+func (h *CityHandler) GetCity(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	city, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+// @Summary Get cities of country
+// @Tags Cities
+// @Description List cities of the country
+// @ID get-country-cities
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Success 200 {array} entity.City
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/cities [get]
+func (h *CityHandler) GetCitiesByCountry(c *gin.Context) {
+	countryID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	cities, err := h.service.GetByCountry(c.Request.Context(), countryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// @Summary Get cities of region
+// @Tags Cities
+// @Description List cities of the administrative region
+// @ID get-region-cities
+// @Produce  json
+// @Param id path int true "Region ID"
+// @Success 200 {array} entity.City
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/{id}/cities [get]
+func (h *CityHandler) GetCitiesByRegion(c *gin.Context) {
+	regionID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	cities, err := h.service.GetByRegion(c.Request.Context(), regionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// @Summary Create city
+// @Tags Cities
+// @Description Add a city to a country and, optionally, to one of its regions
+// @ID create-city
+// @Accept  json
+// @Produce  json
+// @Param city body entity.City true "City"
+// @Param Idempotency-Key header string false "Retries with the same key from the same client return the stored response; use a UUID"
+// @Success 201 {object} entity.City
+// @Failure 400,409,422 {object} problem
+// @Failure 500 {object} problem
+// @Router /cities/ [post]
+func (h *CityHandler) CreateCity(c *gin.Context) {
+	var city entity.City
+	if !bindJSON(c, &city) {
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), &city)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary Update city
+// @Tags Cities
+// @Description Update city by ID
+// @ID update-city
+// @Accept  json
+// @Produce  json
+// @Param id path int true "City ID"
+// @Param city body entity.City true "City"
+// @Success 200 {object} entity.City
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /cities/{id} [put]
+func (h *CityHandler) UpdateCity(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	var city entity.City
+	if !bindJSON(c, &city) {
+		return
+	}
+	city.ID = id
+
+	updated, err := h.service.Update(c.Request.Context(), &city)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete city
+// @Tags Cities
+// @Description Delete city by ID; its places stay and lose the city link
+// @ID delete-city
+// @Param id path int true "City ID"
+// @Success 204
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /cities/{id} [delete]
+func (h *CityHandler) DeleteCity(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
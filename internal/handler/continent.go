@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ContinentHandler struct {
+	service *service.ContinentService
+}
+
+func NewContinentHandler(service *service.ContinentService) *ContinentHandler {
+	return &ContinentHandler{service: service}
+}
+
+// @Summary Get all continents
+// @Tags Continents
+// @Description Retrieve a list of all continents
+// @ID get-continents
+// @Produce  json
+// @Success 200 {array} entity.Continent
+// @Failure 500 {object} problem
+// @Router /continents/ [get]
+func (h *ContinentHandler) GetContinents(c *gin.Context) {
+	continents, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, continents)
+}
+
+// @Summary Get continent by code
+// @Tags Continents
+// @Description Get continent by its two-letter code (AF, AN, AS, EU, NA, OC, SA)
+// @ID get-continent-by-code
+// @Produce  json
+// @Param code path string true "Continent code"
+// @Success 200 {object} entity.Continent
+// @Failure 404 {object} problem
+// @Failure 500 {object} problem
+// @Router /continents/{code} [get]
+func (h *ContinentHandler) GetContinent(c *gin.Context) {
+	continent, err := h.service.GetByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, continent)
+}
+
+// @Summary Get countries of continent
+// @Tags Continents
+// @Description List countries that belong to the continent
+// @ID get-continent-countries
+// @Produce  json
+// @Param code path string true "Continent code"
+// @Success 200 {array} entity.Country
+// @Failure 404 {object} problem
+// @Failure 500 {object} problem
+// @Router /continents/{code}/countries [get]
+func (h *ContinentHandler) GetCountries(c *gin.Context) {
+	countries, err := h.service.GetCountries(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, countries)
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/ShekleinAleksey/top-places/internal/service"
@@ -25,14 +24,14 @@ func NewCountryHandler(service *service.CountryService) *CountryHandler {
 // @Accept  json
 // @Produce  json
 // @Success 200 {array} entity.Country
-// @Failure 400,404 {object} errorResponse
-// @Failure 500 {object} errorResponse
-// @Failure default {object} errorResponse
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Failure default {object} problem
 // @Router /countries/ [get]
 func (h *CountryHandler) GetCountry(c *gin.Context) {
-	country, err := h.service.GetCountries()
+	country, err := h.service.GetCountries(c.Request.Context())
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -46,25 +45,26 @@ func (h *CountryHandler) GetCountry(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Country ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} entity.Country
-// @Failure 400 {object} errorResponse
-// @Failure 404 {object} errorResponse
-// @Failure 500 {object} errorResponse
+// @Success 304 "Not modified"
+// @Header 200 {string} ETag "Country version"
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Failure 500 {object} problem
 // @Router /countries/{id} [get]
 func (h *CountryHandler) GetCountryByID(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id parameter")
+	id, ok := pathInt(c, "id")
+	if !ok {
 		return
 	}
 
-	country, err := h.service.GetCountryByID(id)
+	country, err := h.service.GetCountryByID(c.Request.Context(), id)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			newErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		_ = c.Error(err)
+		return
+	}
+	if notModified(c, countryETag(&country)) {
 		return
 	}
 
@@ -77,21 +77,23 @@ func (h *CountryHandler) GetCountryByID(c *gin.Context) {
 // @ID add-country
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "Retries with the same key from the same client return the stored response; use a UUID"
 // @Success 200 {array} entity.Country
-// @Failure 500 {object} errorResponse
-// @Failure default {object} errorResponse
+// @Failure 409 {object} problem "Request with this key is still in progress"
+// @Failure 422 {object} problem "Key was used with a different request"
+// @Failure 500 {object} problem
+// @Failure default {object} problem
 // @Router /countries/ [post]
 func (h *CountryHandler) AddCountry(c *gin.Context) {
 	var country entity.Country
 
-	if err := c.BindJSON(&country); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	if !bindJSON(c, &country) {
 		return
 	}
 
-	id, err := h.service.AddCountry(&country)
+	id, err := h.service.AddCountry(c.Request.Context(), &country)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -107,75 +109,221 @@ func (h *CountryHandler) AddCountry(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID страны"
+// @Param If-Match header string true "ETag страны из GET или *"
 // @Param country body entity.Country true "Данные для обновления"
 // @Success 200 {object} entity.Country
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Header 200 {string} ETag "Новая версия страны"
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Failure 412 {object} problem "Страна изменилась после чтения"
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem
 // @Router /countries/{id} [put]
 func (h *CountryHandler) UpdateCountry(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid country ID"})
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	var country entity.Country
-	if err := c.ShouldBindJSON(&country); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+	if !bindJSON(c, &country) {
 		return
 	}
 	country.ID = id
+	country.Version = version
 
-	updatedCountry, err := h.service.UpdateCountry(&country)
+	updatedCountry, err := h.service.UpdateCountry(c.Request.Context(), &country)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "country not found" {
-			status = http.StatusNotFound
-		} else if strings.Contains(err.Error(), "is required") {
-			status = http.StatusBadRequest
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
+	c.Header("ETag", countryETag(updatedCountry))
 	c.JSON(http.StatusOK, updatedCountry)
 }
 
+// PatchCountry godoc
+// @Summary Частично обновить страну
+// @Description Применяет JSON Merge Patch (RFC 7396): переданные поля заменяются, null очищает поле, остальные не меняются
+// @Tags Countries
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID страны"
+// @Param If-Match header string true "ETag страны из GET или *"
+// @Param patch body object true "Изменяемые поля страны"
+// @Success 200 {object} entity.Country
+// @Header 200 {string} ETag "Новая версия страны"
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Failure 412 {object} problem "Страна изменилась после чтения"
+// @Failure 415 {object} problem
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem
+// @Router /countries/{id} [patch]
+func (h *CountryHandler) PatchCountry(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	country, err := h.service.PatchCountry(c.Request.Context(), id, version, patch)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", countryETag(country))
+	c.JSON(http.StatusOK, country)
+}
+
+// @Summary Restore country revision
+// @Tags Revisions
+// @Description Return the country to the state stored in the revision; the restore is saved as a new revision
+// @ID restore-country-revision
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Param rev path int true "Revision number"
+// @Param If-Match header string true "Country ETag from GET, or *"
+// @Success 200 {object} entity.Country
+// @Header 200 {string} ETag "New country version"
+// @Failure 400,404 {object} problem
+// @Failure 412 {object} problem "Country was modified since it was read"
+// @Failure 428 {object} problem "If-Match header is missing"
+// @Failure 500 {object} problem
+// @Router /countries/{id}/revisions/{rev}/restore [post]
+func (h *CountryHandler) RestoreCountryRevision(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathInt(c, "rev")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	country, err := h.service.RestoreRevision(c.Request.Context(), id, rev, version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", countryETag(country))
+	c.JSON(http.StatusOK, country)
+}
+
+// @Summary Get country by ISO code
+// @Tags Countries
+// @Description Get country by ISO 3166-1 alpha-2 or alpha-3 code
+// @ID get-country-by-code
+// @Accept  json
+// @Produce  json
+// @Param code path string true "ISO 3166-1 code, e.g. GE or GEO"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} entity.Country
+// @Success 304 "Not modified"
+// @Header 200 {string} ETag "Country version"
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/by-code/{code} [get]
+func (h *CountryHandler) GetCountryByCode(c *gin.Context) {
+	country, err := h.service.GetCountryByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if notModified(c, countryETag(&country)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, country)
+}
+
 // @Summary Delete country
 // @Tags Countries
-// @Description delete country by ID
+// @Description Move the country to the trash. A country with places is deleted only with cascade=true, which moves its places to the trash as well; see /countries/{id}/delete-preview.
 // @ID delete-country
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Country ID"
-// @Success 200 {object} map[string]interface{} "{"status": "success", "deleted_id": id}"
-// @Failure 404 {object} errorResponse
-// @Failure 500 {object} errorResponse
+// @Param If-Match header string true "Country ETag from GET, or *"
+// @Param cascade query bool false "Also delete the country's places"
+// @Success 200 {object} map[string]interface{} "{"status": "success", "deleted_id": id, "deleted_places": [ids]}"
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Failure 409 {object} problem "Country has places and cascade is not set"
+// @Failure 412 {object} problem "Country was modified since it was read"
+// @Failure 428 {object} problem "If-Match header is missing"
+// @Failure 500 {object} problem
 // @Router /countries/{id} [delete]
 func (h *CountryHandler) DeleteCountry(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id parameter")
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	cascade, ok := queryBool(c, "cascade")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	deletedID, err := h.service.DeleteCountry(id)
+	placeIDs, err := h.service.DeleteCountry(c.Request.Context(), id, version, cascade)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			newErrorResponse(c, http.StatusNotFound, err.Error())
-		} else {
-			newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, map[string]interface{}{
-		"status":     "success",
-		"deleted_id": deletedID,
+		"status":         "success",
+		"deleted_id":     id,
+		"deleted_places": placeIDs,
 	})
 }
 
+// @Summary Preview country deletion
+// @Tags Countries
+// @Description Places and photos that DELETE with cascade=true moves to the trash, and regions and cities removed when the country is purged
+// @ID preview-country-deletion
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Success 200 {object} entity.CountryDeletePreview
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/delete-preview [get]
+func (h *CountryHandler) GetDeletePreview(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	preview, err := h.service.DeletePreview(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
 // SearchCountries godoc
 // @Summary Search countries
 // @Description Search countries by name with optional limit
@@ -185,8 +333,8 @@ func (h *CountryHandler) DeleteCountry(c *gin.Context) {
 // @Param q query string true "Search query (minimum 2 characters)"
 // @Param limit query int false "Maximum number of results (default: 10)"
 // @Success 200 {array} entity.Country "List of matching countries"
-// @Failure 400 {object} map[string]string "Invalid query parameters"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} problem "Invalid query parameters"
+// @Failure 500 {object} problem "Internal server error"
 // @Router /countries/search [get]
 func (h *CountryHandler) SearchCountries(c *gin.Context) {
 	query := c.Query("q")
@@ -202,9 +350,9 @@ func (h *CountryHandler) SearchCountries(c *gin.Context) {
 		}
 	}
 
-	countries, err := h.service.SearchCountries(query, limit)
+	countries, err := h.service.SearchCountries(c.Request.Context(), query, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EnrichmentHandler struct {
+	service *service.EnrichmentService
+}
+
+func NewEnrichmentHandler(service *service.EnrichmentService) *EnrichmentHandler {
+	return &EnrichmentHandler{service: service}
+}
+
+// RunEnrichment godoc
+// @Summary Run country enrichment
+// @Tags Admin
+// @Description Fill empty country fields from Wikidata and propose updates for edited ones
+// @ID run-enrichment
+// @Produce  json
+// @Security AdminToken
+// @Success 200 {object} entity.EnrichmentReport
+// @Failure 401 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/enrichment/run [post]
+func (h *EnrichmentHandler) RunEnrichment(c *gin.Context) {
+	report, err := h.service.Run(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListProposals godoc
+// @Summary List enrichment proposals
+// @Tags Admin
+// @Description List proposed and applied country values with their source
+// @ID list-enrichment-proposals
+// @Produce  json
+// @Security AdminToken
+// @Param status query string false "pending, applied or rejected"
+// @Param country_id query int false "Country ID"
+// @Success 200 {array} entity.CountryEnrichment
+// @Failure 400 {object} problem
+// @Failure 401 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/enrichment/proposals [get]
+func (h *EnrichmentHandler) ListProposals(c *gin.Context) {
+	countryID, ok := queryInt(c, "country_id")
+	if !ok {
+		return
+	}
+
+	proposals, err := h.service.ListProposals(c.Request.Context(), c.Query("status"), countryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, proposals)
+}
+
+// ApproveProposal godoc
+// @Summary Approve enrichment proposal
+// @Tags Admin
+// @Description Overwrite the country field with the proposed value
+// @ID approve-enrichment-proposal
+// @Produce  json
+// @Security AdminToken
+// @Param id path int true "Proposal ID"
+// @Success 200 {object} entity.CountryEnrichment
+// @Failure 400,404,409 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/enrichment/proposals/{id}/approve [post]
+func (h *EnrichmentHandler) ApproveProposal(c *gin.Context) {
+	h.review(c, h.service.Approve)
+}
+
+// RejectProposal godoc
+// @Summary Reject enrichment proposal
+// @Tags Admin
+// @Description Keep the current value; the same proposal will not be suggested again
+// @ID reject-enrichment-proposal
+// @Produce  json
+// @Security AdminToken
+// @Param id path int true "Proposal ID"
+// @Success 200 {object} entity.CountryEnrichment
+// @Failure 400,404,409 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/enrichment/proposals/{id}/reject [post]
+func (h *EnrichmentHandler) RejectProposal(c *gin.Context) {
+	h.review(c, h.service.Reject)
+}
+
+func (h *EnrichmentHandler) review(c *gin.Context, fn func(ctx context.Context, id int) (*entity.CountryEnrichment, error)) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	proposal, err := fn(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, proposal)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	codePreconditionRequired = "precondition_required"
+	codePreconditionFailed   = "precondition_failed"
+)
+
+func countryETag(country *entity.Country) string {
+	return fmt.Sprintf(`"%d"`, country.Version)
+}
+
+// placeETag включает версию страны: она отдается вложенным объектом, и ее
+// изменение тоже должно сбрасывать кэш клиента
+func placeETag(place *entity.Place) string {
+	return fmt.Sprintf(`"%d.%d"`, place.Version, place.Country.Version)
+}
+
+// notModified выставляет ETag и, если он совпал с If-None-Match (слабое
+// сравнение, RFC 9110), отвечает 304 без тела
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion читает обязательный заголовок If-Match и возвращает версию,
+// которую клиент ожидает изменить (0 для "*" - любая). Без заголовка отвечает
+// 428, на ETag не нашего формата - 412.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		newErrorResponse(c, http.StatusPreconditionRequired, codePreconditionRequired,
+			"If-Match header is required; send the ETag returned by GET")
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+
+	version, ok := parseETagVersion(header)
+	if !ok {
+		newErrorResponse(c, http.StatusPreconditionFailed, codePreconditionFailed,
+			"If-Match must be a single strong ETag returned by GET")
+		return 0, false
+	}
+	return version, true
+}
+
+// parseETagVersion извлекает версию записи из "3" или "3.7" (ETag места)
+func parseETagVersion(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	v, _, _ := strings.Cut(tag[1:len(tag)-1], ".")
+	version, err := strconv.ParseInt(v, 10, 64)
+	return version, err == nil && version > 0
+}
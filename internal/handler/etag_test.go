@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newETagContext возвращает контекст GET-запроса с заголовком name: value
+func newETagContext(name, value string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/countries/1", nil)
+	if value != "" {
+		c.Request.Header.Set(name, value)
+	}
+	return c, w
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`"3"`, true},
+		{`"4"`, false},
+		{`W/"3"`, true},
+		{`"1", "3"`, true},
+		{`"1",W/"3"`, true},
+		{`"1", "2"`, false},
+		{`*`, true},
+		{`3`, false},
+	}
+	for _, tt := range tests {
+		c, w := newETagContext("If-None-Match", tt.ifNoneMatch)
+		got := notModified(c, `"3"`)
+		c.Writer.WriteHeaderNow()
+		if got != tt.want {
+			t.Errorf("If-None-Match %s: notModified = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+		if got && w.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %s: status = %d, want 304", tt.ifNoneMatch, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"3"` {
+			t.Errorf("If-None-Match %s: ETag = %s, want \"3\"", tt.ifNoneMatch, etag)
+		}
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		ifMatch string
+		version int64
+		status  int // 0 - заголовок принят
+	}{
+		{`"3"`, 3, 0},
+		{`"3.7"`, 3, 0},
+		{` "12" `, 12, 0},
+		{`*`, 0, 0},
+		{``, 0, http.StatusPreconditionRequired},
+		{`W/"3"`, 0, http.StatusPreconditionFailed},
+		{`"1", "3"`, 0, http.StatusPreconditionFailed},
+		{`3`, 0, http.StatusPreconditionFailed},
+		{`"0"`, 0, http.StatusPreconditionFailed},
+		{`"-1"`, 0, http.StatusPreconditionFailed},
+		{`"abc"`, 0, http.StatusPreconditionFailed},
+		{`""`, 0, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		c, w := newETagContext("If-Match", tt.ifMatch)
+		version, ok := ifMatchVersion(c)
+		if tt.status == 0 {
+			if !ok || version != tt.version {
+				t.Errorf("If-Match %s: ifMatchVersion = %d, %v; want %d, true", tt.ifMatch, version, ok, tt.version)
+			}
+			continue
+		}
+		if ok {
+			t.Errorf("If-Match %s: accepted with version %d, want %d", tt.ifMatch, version, tt.status)
+			continue
+		}
+		if w.Code != tt.status || w.Header().Get("Content-Type") != problemContentType {
+			t.Errorf("If-Match %s: response %d %s, want %d problem+json", tt.ifMatch, w.Code, w.Header().Get("Content-Type"), tt.status)
+		}
+	}
+}
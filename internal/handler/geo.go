@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GeoHandler struct {
+	service *service.GeoService
+}
+
+func NewGeoHandler(service *service.GeoService) *GeoHandler {
+	return &GeoHandler{service: service}
+}
+
+// @Summary Reverse geocode
+// @Tags Geo
+// @Description Find the country whose boundaries contain the point
+// @ID geo-reverse
+// @Produce  json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} entity.ReverseGeocode
+// @Failure 400,404 {object} problem
+// @Failure 503 {object} problem
+// @Router /geo/reverse [get]
+func (h *GeoHandler) Reverse(c *gin.Context) {
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil {
+		_ = c.Error(service.Invalid("lat", "must be a number"))
+		return
+	}
+	lon, err := strconv.ParseFloat(c.Query("lon"), 64)
+	if err != nil {
+		_ = c.Error(service.Invalid("lon", "must be a number"))
+		return
+	}
+
+	result, err := h.service.Reverse(c.Request.Context(), lat, lon)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -4,31 +4,95 @@ import (
 	"net/http"
 
 	_ "github.com/ShekleinAleksey/top-places/docs"
+	"github.com/ShekleinAleksey/top-places/internal/metrics"
 	"github.com/ShekleinAleksey/top-places/internal/service"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
+// serviceName - имя сервиса в трассах
+const serviceName = "top-places"
+
+// probeRoutes - маршруты, которые опрашивают Prometheus и оркестратор. Они
+// не трассируются, а успешные ответы на них пишутся в журнал только на
+// уровне debug.
+var probeRoutes = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
 type Handler struct {
-	countryHandler *CountryHandler
-	placeHandler   *PlaceHandler
+	countryHandler     *CountryHandler
+	placeHandler       *PlaceHandler
+	enrichmentHandler  *EnrichmentHandler
+	referenceHandler   *ReferenceHandler
+	continentHandler   *ContinentHandler
+	regionHandler      *RegionHandler
+	cityHandler        *CityHandler
+	geoHandler         *GeoHandler
+	revisionHandler    *RevisionHandler
+	trashHandler       *TrashHandler
+	auditHandler       *AuditHandler
+	healthHandler      *HealthHandler
+	auditService       *service.AuditService
+	idempotencyService *service.IdempotencyService
+	adminToken         string
+	timeouts           Timeouts
+	metrics            *metrics.Metrics
 }
 
-func NewHandler(services *service.Service) *Handler {
+func NewHandler(services *service.Service, adminToken string, timeouts Timeouts, metrics *metrics.Metrics) *Handler {
 	return &Handler{
-		countryHandler: NewCountryHandler(services.CountryService),
-		placeHandler:   NewPlaceHandler(services.PlaceService),
+		countryHandler:     NewCountryHandler(services.CountryService),
+		placeHandler:       NewPlaceHandler(services.PlaceService),
+		enrichmentHandler:  NewEnrichmentHandler(services.EnrichmentService),
+		referenceHandler:   NewReferenceHandler(),
+		continentHandler:   NewContinentHandler(services.ContinentService),
+		regionHandler:      NewRegionHandler(services.RegionService),
+		cityHandler:        NewCityHandler(services.CityService),
+		geoHandler:         NewGeoHandler(services.GeoService),
+		revisionHandler:    NewRevisionHandler(services.RevisionService),
+		trashHandler:       NewTrashHandler(services.TrashService),
+		auditHandler:       NewAuditHandler(services.AuditService),
+		healthHandler:      NewHealthHandler(services.HealthService),
+		auditService:       services.AuditService,
+		idempotencyService: services.IdempotencyService,
+		adminToken:         adminToken,
+		timeouts:           timeouts,
+		metrics:            metrics,
 	}
 }
 
 func (h *Handler) InitRoutes() *gin.Engine {
-	router := gin.Default()
+	useJSONFieldNames()
+
+	router := gin.New()
+	// Метрики подключаются первыми, чтобы учитывать время всех обработчиков
+	// и итоговый статус ответа
+	router.Use(metricsMiddleware(h.metrics))
+	// Спан запроса продолжает трассу из заголовка traceparent; опросы
+	// метрик и проверки состояния не трассируются
+	router.Use(otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !probeRoutes[r.URL.Path]
+	})))
+	router.Use(requestIDMiddleware())
+	router.Use(actorMiddleware(h.adminToken))
+	router.Use(readOnlyMiddleware())
+	router.Use(accessLogMiddleware())
+	router.Use(recoveryMiddleware())
+	// Аудит подключается до errorHandler, чтобы видеть итоговый статус ответа
+	router.Use(auditMiddleware(h.auditService))
+	router.Use(errorHandler())
+	router.Use(timeoutMiddleware(h.timeouts))
 
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, X-User, X-Request-ID, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, X-Request-ID, Idempotent-Replayed")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
 			return
@@ -36,29 +100,102 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		c.Next()
 	})
 
+	// Idempotency-Key поддерживают все маршруты создания записей
+	idem := idempotency(h.idempotencyService)
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", gin.WrapH(h.metrics.Handler()))
+	router.GET("/healthz", h.healthHandler.Live)
+	router.GET("/readyz", h.healthHandler.Ready)
 
 	country := router.Group("/countries")
 	{
 		country.GET("/", h.countryHandler.GetCountry)
-		country.GET("/:id", h.countryHandler.GetCountryByID)
-		country.POST("/", h.countryHandler.AddCountry)
+		country.GET("/:id", primaryReads, h.countryHandler.GetCountryByID)
+		country.POST("/", idem, h.countryHandler.AddCountry)
 		country.PUT("/:id", h.countryHandler.UpdateCountry)
+		country.PATCH("/:id", h.countryHandler.PatchCountry)
 		country.DELETE("/:id", h.countryHandler.DeleteCountry)
+		country.GET("/:id/delete-preview", h.countryHandler.GetDeletePreview)
 
 		country.GET("/search", h.countryHandler.SearchCountries)
+		country.GET("/by-code/:code", primaryReads, h.countryHandler.GetCountryByCode)
 
 		country.GET("/:id/places", h.placeHandler.GetPlacesByCountryHandler)
+		country.GET("/:id/regions", h.regionHandler.GetRegionsByCountry)
+		country.GET("/:id/cities", h.cityHandler.GetCitiesByCountry)
+
+		country.GET("/:id/revisions", h.revisionHandler.GetCountryRevisions)
+		country.GET("/:id/revisions/diff", h.revisionHandler.DiffCountryRevisions)
+		country.GET("/:id/revisions/:rev", h.revisionHandler.GetCountryRevision)
+		country.POST("/:id/revisions/:rev/restore", h.countryHandler.RestoreCountryRevision)
+	}
+	continents := router.Group("/continents")
+	{
+		continents.GET("/", h.continentHandler.GetContinents)
+		continents.GET("/:code", h.continentHandler.GetContinent)
+		continents.GET("/:code/countries", h.continentHandler.GetCountries)
+		continents.GET("/:code/places", h.placeHandler.GetPlacesByContinentHandler)
+	}
+	regions := router.Group("/regions")
+	{
+		regions.GET("/", h.regionHandler.GetRegions)
+		regions.GET("/:id", h.regionHandler.GetRegion)
+		regions.POST("/", idem, h.regionHandler.CreateRegion)
+		regions.PUT("/:id", h.regionHandler.UpdateRegion)
+		regions.DELETE("/:id", h.regionHandler.DeleteRegion)
+
+		regions.GET("/:id/cities", h.cityHandler.GetCitiesByRegion)
+		regions.GET("/:id/places", h.placeHandler.GetPlacesByRegionHandler)
+	}
+	cities := router.Group("/cities")
+	{
+		cities.GET("/", h.cityHandler.GetCities)
+		cities.GET("/:id", h.cityHandler.GetCity)
+		cities.POST("/", idem, h.cityHandler.CreateCity)
+		cities.PUT("/:id", h.cityHandler.UpdateCity)
+		cities.DELETE("/:id", h.cityHandler.DeleteCity)
+
+		cities.GET("/:id/places", h.placeHandler.GetPlacesByCityHandler)
 	}
 	places := router.Group("/places")
 	{
-		places.POST("/", h.placeHandler.CreatePlace)
+		places.POST("/", idem, h.placeHandler.CreatePlace)
 		places.GET("/", h.placeHandler.GetAllPlaces)
-		places.GET("/:id", h.placeHandler.GetPlace)
+		places.GET("/:id", primaryReads, h.placeHandler.GetPlace)
 		places.PUT("/:id", h.placeHandler.UpdatePlace)
+		places.PATCH("/:id", h.placeHandler.PatchPlace)
 		places.DELETE("/:id", h.placeHandler.DeletePlace)
 
 		places.GET("/search", h.placeHandler.SearchPlaces)
+
+		places.GET("/:id/revisions", h.revisionHandler.GetPlaceRevisions)
+		places.GET("/:id/revisions/diff", h.revisionHandler.DiffPlaceRevisions)
+		places.GET("/:id/revisions/:rev", h.revisionHandler.GetPlaceRevision)
+		places.POST("/:id/revisions/:rev/restore", h.placeHandler.RestorePlaceRevision)
+	}
+	router.GET("/geo/reverse", h.geoHandler.Reverse)
+
+	reference := router.Group("/reference")
+	{
+		reference.GET("/countries", h.referenceHandler.ListCountries)
+		reference.GET("/languages", h.referenceHandler.ListLanguages)
+		reference.GET("/currencies", h.referenceHandler.ListCurrencies)
+	}
+	admin := router.Group("/admin", adminAuth(h.adminToken))
+	{
+		admin.POST("/enrichment/run", h.enrichmentHandler.RunEnrichment)
+		admin.GET("/enrichment/proposals", h.enrichmentHandler.ListProposals)
+		admin.POST("/enrichment/proposals/:id/approve", h.enrichmentHandler.ApproveProposal)
+		admin.POST("/enrichment/proposals/:id/reject", h.enrichmentHandler.RejectProposal)
+
+		admin.GET("/trash", h.trashHandler.ListTrash)
+		admin.POST("/trash/:type/:id/restore", h.trashHandler.RestoreFromTrash)
+		admin.POST("/trash/purge", h.trashHandler.PurgeTrash)
+
+		admin.GET("/audit", h.auditHandler.ListAudit)
+
+		admin.GET("/status", h.healthHandler.Status)
 	}
 
 	return router
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	service *service.HealthService
+}
+
+func NewHealthHandler(service *service.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Tags Health
+// @Description The process is up and serving HTTP. Does not touch the database.
+// @ID healthz
+// @Produce  json
+// @Success 200 {object} statusResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, statusResponse{Status: entity.HealthOK})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Tags Health
+// @Description The database answers, the schema is migrated to the version the service expects and the data tables are readable
+// @ID readyz
+// @Produce  json
+// @Success 200 {object} entity.Health
+// @Failure 503 {object} entity.Health
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	health := h.service.Ready(c.Request.Context())
+	c.JSON(healthStatusCode(health), health)
+}
+
+// Status godoc
+// @Summary Service status
+// @Tags Admin
+// @Description Build version and commit, uptime, main settings and readiness checks
+// @ID service-status
+// @Produce  json
+// @Security AdminToken
+// @Success 200 {object} entity.ServiceStatus
+// @Failure 401 {object} problem
+// @Router /admin/status [get]
+func (h *HealthHandler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Status(c.Request.Context()))
+}
+
+func healthStatusCode(health *entity.Health) int {
+	if health.Status != entity.HealthOK {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader отмечает ответ, взятый из сохраненных
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replayedHeaders - заголовки ответа, которые сохраняются вместе с телом
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// idempotency делает POST-запрос с заголовком Idempotency-Key идемпотентным:
+// первый ответ сохраняется и отдается на повторы с тем же ключом и телом.
+// Ответы 5xx и запросы, клиент которых отключился (499), не сохраняются -
+// после них запрос можно повторить. Без заголовка запрос обрабатывается как обычно.
+// Ключи действуют в пространстве клиента (idempotencyScope); чтобы ключи
+// клиентов не совпадали, в качестве ключа стоит передавать UUID.
+func idempotency(idem *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.Error(service.InvalidJSON(err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		actor := idempotencyScope(c)
+		hash := requestHash(c, body)
+		stored, err := idem.Begin(c.Request.Context(), actor, key, hash)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			replay(c, stored)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		renderError(c)
+
+		// Ключ освобождается или сохраняется, даже если клиент уже отключился.
+		// Ответ отключившемуся клиенту не сохраняется: он его не получил, и
+		// повтор с тем же ключом должен выполнить запрос, а не вернуть пустой 499.
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == statusClientClosedRequest || c.Request.Context().Err() != nil {
+			if err := idem.Release(ctx, actor, key); err != nil {
+				requestLogger(c).WithError(err).Error("failed to release idempotency key")
+			}
+			return
+		}
+
+		record := &entity.IdempotencyRecord{
+			Actor:   actor,
+			Key:     key,
+			Status:  status,
+			Headers: make(map[string]string),
+			Body:    recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if v := recorder.Header().Get(name); v != "" {
+				record.Headers[name] = v
+			}
+		}
+		if err := idem.Complete(ctx, record); err != nil {
+			requestLogger(c).WithError(err).Error("failed to save idempotent response")
+		}
+	}
+}
+
+// idempotencyScope возвращает пространство ключей идемпотентности клиента.
+// Запросы с токеном администратора различаются по actor. Анонимные клиенты
+// различаются еще и по IP: иначе у них были бы общие ключи, и клиент,
+// угадавший чужой ключ, получил бы чужой ответ. Поэтому повтор анонимного
+// запроса с другого IP выполняется заново.
+func idempotencyScope(c *gin.Context) string {
+	actor := service.ActorFrom(c.Request.Context())
+	if actor != service.AnonymousActor {
+		return actor
+	}
+	ip := sha256.Sum256([]byte(c.ClientIP()))
+	return actor + ":" + hex.EncodeToString(ip[:8])
+}
+
+// requestHash отличает повтор запроса от другого запроса с тем же ключом
+func requestHash(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(c *gin.Context, record *entity.IdempotencyRecord) {
+	for name, value := range record.Headers {
+		c.Header(name, value)
+	}
+	c.Header(idempotentReplayedHeader, "true")
+	c.Abort()
+	c.Status(record.Status)
+	if len(record.Body) > 0 {
+		_, _ = c.Writer.Write(record.Body)
+	}
+}
+
+// responseRecorder копирует тело ответа, чтобы его можно было сохранить
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scope := func(remoteAddr, actor string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/places/", nil)
+		c.Request.RemoteAddr = remoteAddr
+		if actor != "" {
+			c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), actor))
+		}
+		return idempotencyScope(c)
+	}
+
+	if a, b := scope("192.0.2.1:1000", ""), scope("192.0.2.2:1000", ""); a == b {
+		t.Errorf("anonymous clients with different IPs share scope %q", a)
+	}
+	if a, b := scope("192.0.2.1:1000", ""), scope("192.0.2.1:2000", ""); a != b {
+		t.Errorf("anonymous client scope changed with port: %q, %q", a, b)
+	}
+	if got := scope("192.0.2.1:1000", "alice"); got != "alice" {
+		t.Errorf("admin scope = %q, want alice", got)
+	}
+}
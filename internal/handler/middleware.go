@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/metrics"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// adminAuth пропускает только запросы с заголовком "Authorization: Bearer <token>".
+// Если токен не задан, административные маршруты отключены.
+func adminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			newErrorResponse(c, http.StatusForbidden, codeForbidden, "admin API is disabled")
+			return
+		}
+
+		if !isAdmin(c, token) {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			newErrorResponse(c, http.StatusUnauthorized, codeUnauthorized, "invalid admin token")
+			return
+		}
+		c.Next()
+	}
+}
+
+// isAdmin проверяет токен администратора в заголовке Authorization
+func isAdmin(c *gin.Context, token string) bool {
+	provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+const (
+	actorHeader = "X-User"
+	adminActor  = "admin"
+	// maxActorLength - размер колонки revisions.actor
+	maxActorLength = 255
+)
+
+// actorMiddleware определяет, от чьего имени идут изменения. Заголовку X-User
+// верим только в запросах с токеном администратора (его передает фронтенд
+// или прокси перед API); без X-User такие запросы идут от имени admin.
+// Остальные запросы выполняются от имени anonymous, а X-User сохраняется в
+// журнале аудита как непроверенное claimed_actor.
+func actorMiddleware(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := strings.TrimSpace(c.GetHeader(actorHeader))
+		if r := []rune(actor); len(r) > maxActorLength {
+			actor = string(r[:maxActorLength])
+		}
+
+		ctx := c.Request.Context()
+		switch {
+		case isAdmin(c, adminToken) && actor != "":
+			ctx = service.WithActor(ctx, actor)
+		case isAdmin(c, adminToken):
+			ctx = service.WithActor(ctx, adminActor)
+		case actor != "":
+			ctx = service.WithClaimedActor(ctx, actor)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// readOnlyMiddleware разрешает запросам GET и HEAD читать с реплик БД.
+// Остальные запросы пишут и читают только основную БД, поэтому сразу видят
+// свои изменения; так же читают GET с ETag (primaryReads).
+func readOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Request = c.Request.WithContext(service.ReadOnly(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// primaryReads направляет чтения маршрута в основную БД. Его ставят на GET,
+// которые отдают ETag: клиент передает его в If-Match сразу после своей
+// записи, а ETag с отстающей реплики привел бы к ложному 412.
+func primaryReads(c *gin.Context) {
+	c.Request = c.Request.WithContext(service.ReadPrimary(c.Request.Context()))
+	c.Next()
+}
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+	// maxRequestIDLength ограничивает ID, пришедший от клиента или прокси
+	maxRequestIDLength = 128
+)
+
+// requestIDMiddleware берет ID запроса из X-Request-ID или генерирует новый
+// и возвращает его в ответе, чтобы запрос можно было найти в журналах
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength || strings.ContainsFunc(id, isControl) {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// Timeouts - ограничения времени обработки запросов
+type Timeouts struct {
+	// Default действует для маршрутов без собственного ограничения; 0 - без ограничения
+	Default time.Duration
+	// Routes - ограничения отдельных маршрутов по ключу "МЕТОД шаблон",
+	// как маршрут записан в роутере: "POST /admin/enrichment/run"
+	Routes map[string]time.Duration
+}
+
+// For возвращает ограничение времени для маршрута
+func (t Timeouts) For(method, route string) time.Duration {
+	for key, timeout := range t.Routes {
+		keyMethod, keyRoute, _ := strings.Cut(strings.TrimSpace(key), " ")
+		if strings.EqualFold(keyMethod, method) && strings.TrimSpace(keyRoute) == route {
+			return timeout
+		}
+	}
+	return t.Default
+}
+
+// timeoutMiddleware ограничивает время обработки запроса: по истечении времени
+// маршрута контекст запроса отменяется, запросы к БД прерываются, а клиент
+// получает 504
+func timeoutMiddleware(timeouts Timeouts) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeouts.For(c.Request.Method, c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// Ответ формируется до отмены контекста, пока по нему видно, что
+		// истекло именно время запроса
+		renderError(c)
+	}
+}
+
+// metricsMiddleware учитывает запрос в метриках. Маршрут берется из шаблона
+// (/countries/:id), чтобы число рядов метрик не зависело от ID в пути.
+func metricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// loggerKey - ключ gin.Context с журналом запроса
+const loggerKey = "logger"
+
+// accessLogMiddleware пишет по одной JSON-записи на запрос и сохраняет в
+// контексте журнал с полями запроса: через requestLogger ими пользуются
+// сообщения обработчиков, чтобы их можно было связать с записью доступа.
+// Подключается после requestIDMiddleware и actorMiddleware.
+func accessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		entry := logrus.WithContext(c.Request.Context()).WithFields(logrus.Fields{
+			"request_id": c.GetString(requestIDKey),
+			"method":     c.Request.Method,
+			"route":      route,
+			"user":       service.ActorFrom(c.Request.Context()),
+		})
+		c.Set(loggerKey, entry)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		entry = entry.WithFields(logrus.Fields{
+			"path":       c.Request.URL.Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      max(c.Writer.Size(), 0),
+			"client_ip":  c.ClientIP(),
+		})
+		if code := c.GetString(problemCodeKey); code != "" {
+			entry = entry.WithField("code", code)
+		}
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request completed")
+		case probeRoutes[route]:
+			entry.Debug("request completed")
+		default:
+			entry.Info("request completed")
+		}
+	}
+}
+
+// requestLogger возвращает журнал запроса; вне accessLogMiddleware - общий
+// журнал с контекстом запроса
+func requestLogger(c *gin.Context) *logrus.Entry {
+	if entry, ok := c.Value(loggerKey).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.WithContext(c.Request.Context())
+}
+
+// recoveryMiddleware отвечает 500 на панику в обработчике и пишет ее в
+// журнал запроса вместе со стеком
+func recoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		requestLogger(c).WithField("stack", string(debug.Stack())).Errorf("panic: %v", err)
+		newErrorResponse(c, http.StatusInternalServerError, codeInternalError, "internal server error")
+	})
+}
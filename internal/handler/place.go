@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/ShekleinAleksey/top-places/internal/service"
@@ -24,20 +25,23 @@ func NewPlaceHandler(service *service.PlaceService) *PlaceHandler {
 // @Accept json
 // @Produce json
 // @Param place body entity.Place true "Данные места"
+// @Param Idempotency-Key header string false "Повтор с тем же ключом от того же клиента вернет сохраненный ответ; ключ - UUID"
 // @Success 201 {object} entity.Place "Созданное место"
-// @Failure 400 {object} map[string]string "Неверный формат данных"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Header 201 {string} Idempotent-Replayed "true, если ответ взят из сохраненных"
+// @Failure 400 {object} problem "Неверный формат данных; fields - ошибки по полям"
+// @Failure 409 {object} problem "Запрос с этим ключом еще выполняется"
+// @Failure 422 {object} problem "Ключ уже использован с другим запросом"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/ [post]
 func (h *PlaceHandler) CreatePlace(c *gin.Context) {
 	var place entity.Place
-	if err := c.ShouldBindJSON(&place); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &place) {
 		return
 	}
 
-	createdPlace, err := h.service.Create(&place)
+	createdPlace, err := h.service.Create(c.Request.Context(), &place)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -51,25 +55,26 @@ func (h *PlaceHandler) CreatePlace(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID места"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
 // @Success 200 {object} entity.Place "Запрошенное место"
-// @Failure 400 {object} map[string]string "Неверный формат ID"
-// @Failure 404 {object} map[string]string "Место не найдено"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Success 304 "Место не изменилось"
+// @Header 200 {string} ETag "Версия места и его страны"
+// @Failure 400 {object} problem "Неверный формат ID"
+// @Failure 404 {object} problem "Место не найдено"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/{id} [get]
 func (h *PlaceHandler) GetPlace(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := pathInt(c, "id")
+	if !ok {
 		return
 	}
 
-	place, err := h.service.GetByID(id)
+	place, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "place not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		_ = c.Error(err)
+		return
+	}
+	if notModified(c, placeETag(place)) {
 		return
 	}
 
@@ -78,17 +83,35 @@ func (h *PlaceHandler) GetPlace(c *gin.Context) {
 
 // GetAllPlaces возвращает все места
 // @Summary Получить все места
-// @Description Возвращает список всех мест
+// @Description Возвращает список мест с фильтрами по континенту, стране, региону и городу
 // @Tags Places
 // @Accept json
 // @Produce json
+// @Param continent query string false "Код континента"
+// @Param country_id query int false "ID страны"
+// @Param region_id query int false "ID региона"
+// @Param city_id query int false "ID города"
 // @Success 200 {array} entity.Place "Список мест"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Failure 400 {object} problem "Неверный формат фильтра"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/ [get]
 func (h *PlaceHandler) GetAllPlaces(c *gin.Context) {
-	places, err := h.service.GetAll()
+	filter := entity.PlaceFilter{ContinentCode: strings.ToUpper(c.Query("continent"))}
+	for name, dst := range map[string]*int{
+		"country_id": &filter.CountryID,
+		"region_id":  &filter.RegionID,
+		"city_id":    &filter.CityID,
+	} {
+		id, ok := queryInt(c, name)
+		if !ok {
+			return
+		}
+		*dst = id
+	}
+
+	places, err := h.service.GetAll(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -102,39 +125,127 @@ func (h *PlaceHandler) GetAllPlaces(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID места"
+// @Param If-Match header string true "ETag места из GET или *"
 // @Param place body entity.Place true "Обновленные данные места"
 // @Success 200 {object} entity.Place "Обновленное место"
-// @Failure 400 {object} map[string]string "Неверный формат данных"
-// @Failure 404 {object} map[string]string "Место не найдено"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Header 200 {string} ETag "Новая версия места"
+// @Failure 400 {object} problem "Неверный формат данных; fields - ошибки по полям"
+// @Failure 404 {object} problem "Место не найдено"
+// @Failure 412 {object} problem "Место изменилось после чтения"
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/{id} [put]
 func (h *PlaceHandler) UpdatePlace(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	var place entity.Place
-	if err := c.ShouldBindJSON(&place); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindJSON(c, &place) {
 		return
 	}
 	place.ID = id
+	place.Version = version
 
-	updatedPlace, err := h.service.Update(&place)
+	updatedPlace, err := h.service.Update(c.Request.Context(), &place)
 	if err != nil {
-		if err.Error() == "place not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		_ = c.Error(err)
 		return
 	}
 
+	c.Header("ETag", placeETag(updatedPlace))
 	c.JSON(http.StatusOK, updatedPlace)
 }
 
+// PatchPlace частично обновляет место
+// @Summary Частично обновить место
+// @Description Применяет JSON Merge Patch (RFC 7396): переданные поля заменяются, null очищает поле, остальные не меняются. Можно сменить страну (country_id).
+// @Tags Places
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID места"
+// @Param If-Match header string true "ETag места из GET или *"
+// @Param patch body object true "Изменяемые поля места"
+// @Success 200 {object} entity.Place "Обновленное место"
+// @Header 200 {string} ETag "Новая версия места"
+// @Failure 400 {object} problem "Неверный патч или объединенное место не прошло проверку"
+// @Failure 404 {object} problem "Место не найдено"
+// @Failure 412 {object} problem "Место изменилось после чтения"
+// @Failure 415 {object} problem "Неподдерживаемый Content-Type"
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
+// @Router /places/{id} [patch]
+func (h *PlaceHandler) PatchPlace(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	place, err := h.service.Patch(c.Request.Context(), id, version, patch)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", placeETag(place))
+
+	c.JSON(http.StatusOK, place)
+}
+
+// RestorePlaceRevision откатывает место к ревизии
+// @Summary Восстановить ревизию места
+// @Description Возвращает месту состояние из ревизии, включая страну и фото. Восстановление сохраняется новой ревизией с действием restore.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID места"
+// @Param rev path int true "Номер ревизии"
+// @Param If-Match header string true "ETag места из GET или *"
+// @Success 200 {object} entity.Place "Восстановленное место"
+// @Header 200 {string} ETag "Новая версия места"
+// @Failure 400 {object} problem "Ревизия не проходит текущие проверки"
+// @Failure 404 {object} problem "Место или ревизия не найдены"
+// @Failure 412 {object} problem "Место изменилось после чтения"
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
+// @Router /places/{id}/revisions/{rev}/restore [post]
+func (h *PlaceHandler) RestorePlaceRevision(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathInt(c, "rev")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	place, err := h.service.RestoreRevision(c.Request.Context(), id, rev, version)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("ETag", placeETag(place))
+
+	c.JSON(http.StatusOK, place)
+}
+
 // DeletePlace удаляет место
 // @Summary Удалить место
 // @Description Удаляет место по его ID
@@ -142,24 +253,26 @@ func (h *PlaceHandler) UpdatePlace(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID места"
+// @Param If-Match header string true "ETag места из GET или *"
 // @Success 204 "Место успешно удалено"
-// @Failure 400 {object} map[string]string "Неверный формат ID"
-// @Failure 404 {object} map[string]string "Место не найдено"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Failure 400 {object} problem "Неверный формат ID"
+// @Failure 404 {object} problem "Место не найдено"
+// @Failure 412 {object} problem "Место изменилось после чтения"
+// @Failure 428 {object} problem "Не передан If-Match"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/{id} [delete]
 func (h *PlaceHandler) DeletePlace(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		if err.Error() == "place not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+	if err := h.service.Delete(c.Request.Context(), id, version); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -173,22 +286,86 @@ func (h *PlaceHandler) DeletePlace(c *gin.Context) {
 // @Produce json
 // @Param country_id path int true "ID страны"
 // @Success 200 {array} entity.Place
-// @Failure 404 {object} map[string]string
+// @Failure 404 {object} problem
 // @Router /countries/{country_id}/places [get]
 func (h *PlaceHandler) GetPlacesByCountryHandler(c *gin.Context) {
-	countryID, err := strconv.Atoi(c.Param("id"))
+	countryID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	places, err := h.service.GetPlacesByCountry(c.Request.Context(), countryID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid country ID"})
+		_ = c.Error(err)
 		return
 	}
 
-	places, err := h.service.GetPlacesByCountry(countryID)
+	c.JSON(http.StatusOK, places)
+}
+
+// GetPlacesByContinentHandler возвращает места континента
+// @Summary Получить места континента
+// @Description Возвращает список всех мест в странах континента
+// @Tags Places
+// @Produce json
+// @Param code path string true "Код континента"
+// @Success 200 {array} entity.Place
+// @Failure 404 {object} problem
+// @Router /continents/{code}/places [get]
+func (h *PlaceHandler) GetPlacesByContinentHandler(c *gin.Context) {
+	places, err := h.service.GetPlacesByContinent(c.Request.Context(), c.Param("code"))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "country not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
+
+// GetPlacesByRegionHandler возвращает места региона
+// @Summary Получить места региона
+// @Description Возвращает список всех мест в административном регионе
+// @Tags Places
+// @Produce json
+// @Param id path int true "ID региона"
+// @Success 200 {array} entity.Place
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Router /regions/{id}/places [get]
+func (h *PlaceHandler) GetPlacesByRegionHandler(c *gin.Context) {
+	regionID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	places, err := h.service.GetPlacesByRegion(c.Request.Context(), regionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
+
+// GetPlacesByCityHandler возвращает места города
+// @Summary Получить места города
+// @Description Возвращает список всех мест в городе
+// @Tags Places
+// @Produce json
+// @Param id path int true "ID города"
+// @Success 200 {array} entity.Place
+// @Failure 400 {object} problem
+// @Failure 404 {object} problem
+// @Router /cities/{id}/places [get]
+func (h *PlaceHandler) GetPlacesByCityHandler(c *gin.Context) {
+	cityID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	places, err := h.service.GetPlacesByCity(c.Request.Context(), cityID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -204,7 +381,7 @@ func (h *PlaceHandler) GetPlacesByCountryHandler(c *gin.Context) {
 // @Param q query string false "Поисковый запрос"
 // @Param limit query int false "Лимит результатов (по умолчанию 10)"
 // @Success 200 {array} entity.Place "Список найденных мест"
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
 // @Router /places/search [get]
 func (h *PlaceHandler) SearchPlaces(c *gin.Context) {
 	query := c.Query("q")
@@ -216,9 +393,9 @@ func (h *PlaceHandler) SearchPlaces(c *gin.Context) {
 		}
 	}
 
-	places, err := h.service.SearchPlaces(query, limit)
+	places, err := h.service.SearchPlaces(c.Request.Context(), query, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
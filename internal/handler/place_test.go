@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository/memory"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// newPlaceRouter возвращает маршруты мест над хранилищами в памяти с одной
+// страной; ID страны - второе значение
+func newPlaceRouter(t *testing.T) (*gin.Engine, int) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	useJSONFieldNames()
+
+	stores, txm := memory.Stores(memory.NewDB())
+	revisions := service.NewRevisionService(stores.Revisions)
+	places := service.NewPlaceService(stores, txm, service.PlaceOptions{})
+	countries := service.NewCountryService(stores.Countries, nil, stores.Places, revisions, txm)
+
+	countryID, err := countries.AddCountry(context.Background(), &entity.Country{Name: "Georgia", Capital: "Tbilisi"})
+	if err != nil {
+		t.Fatalf("AddCountry: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(errorHandler())
+	router.POST("/places/", NewPlaceHandler(places).CreatePlace)
+	return router, countryID
+}
+
+func postJSON(router http.Handler, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreatePlaceWithCountryID(t *testing.T) {
+	router, countryID := newPlaceRouter(t)
+
+	w := postJSON(router, "/places/", `{"name":"Gergeti Trinity Church","country_id":`+strconv.Itoa(countryID)+`}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+
+	var place entity.Place
+	if err := json.Unmarshal(w.Body.Bytes(), &place); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if place.ID == 0 || place.CountryID != countryID || place.Country.Name != "Georgia" {
+		t.Fatalf("created place = %+v", place)
+	}
+}
+
+func TestBodyErrorNestedFieldPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useJSONFieldNames()
+
+	type request struct {
+		Name    string         `json:"name" binding:"required"`
+		Country entity.Country `json:"country"`
+	}
+	router := gin.New()
+	router.Use(errorHandler())
+	router.POST("/", func(c *gin.Context) {
+		var req request
+		if bindJSON(c, &req) {
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	w := postJSON(router, "/", `{"name":"x","country":{"capital":"Tbilisi"}}`)
+	var p problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || len(p.Fields) != 1 || p.Fields[0].Field != "country.name" {
+		t.Fatalf("status = %d, fields = %+v; want 400 with country.name", w.Code, p.Fields)
+	}
+}
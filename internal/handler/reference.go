@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/pkg/iso"
+	"github.com/gin-gonic/gin"
+)
+
+// ReferenceHandler отдает встроенные справочники ISO
+type ReferenceHandler struct{}
+
+func NewReferenceHandler() *ReferenceHandler {
+	return &ReferenceHandler{}
+}
+
+// @Summary List ISO 3166-1 countries
+// @Tags Reference
+// @Description Bundled ISO 3166-1 country codes
+// @ID list-iso-countries
+// @Produce  json
+// @Success 200 {array} iso.Country
+// @Router /reference/countries [get]
+func (h *ReferenceHandler) ListCountries(c *gin.Context) {
+	c.JSON(http.StatusOK, iso.Countries())
+}
+
+// @Summary List ISO 639 languages
+// @Tags Reference
+// @Description Bundled ISO 639 language codes accepted in country "languages"
+// @ID list-iso-languages
+// @Produce  json
+// @Success 200 {array} iso.Language
+// @Router /reference/languages [get]
+func (h *ReferenceHandler) ListLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, iso.Languages())
+}
+
+// @Summary List ISO 4217 currencies
+// @Tags Reference
+// @Description Bundled ISO 4217 currency codes accepted in country "currencies"
+// @ID list-iso-currencies
+// @Produce  json
+// @Success 200 {array} iso.Currency
+// @Router /reference/currencies [get]
+func (h *ReferenceHandler) ListCurrencies(c *gin.Context) {
+	c.JSON(http.StatusOK, iso.Currencies())
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RegionHandler struct {
+	service *service.RegionService
+}
+
+func NewRegionHandler(service *service.RegionService) *RegionHandler {
+	return &RegionHandler{service: service}
+}
+
+// @Summary Get regions
+// @Tags Regions
+// @Description List administrative regions, optionally filtered by country
+// @ID get-regions
+// @Produce  json
+// @Param country_id query int false "Country ID"
+// @Success 200 {array} entity.Region
+// @Failure 400 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/ [get]
+func (h *RegionHandler) GetRegions(c *gin.Context) {
+	countryID, ok := queryInt(c, "country_id")
+	if !ok {
+		return
+	}
+
+	regions, err := h.service.GetAll(c.Request.Context(), countryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, regions)
+}
+
+// @Summary Get region by ID
+// @Tags Regions
+// @Description Get region by ID
+// @ID get-region-by-id
+// @Produce  json
+// @Param id path int true "Region ID"
+// @Success 200 {object} entity.Region
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/{id} [get]
+func (h *RegionHandler) GetRegion(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	region, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, region)
+}
+
+// @Summary Get regions of country
+// @Tags Regions
+// @Description List administrative regions of the country
+// @ID get-country-regions
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Success 200 {array} entity.Region
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/regions [get]
+func (h *RegionHandler) GetRegionsByCountry(c *gin.Context) {
+	countryID, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	regions, err := h.service.GetByCountry(c.Request.Context(), countryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, regions)
+}
+
+// @Summary Create region
+// @Tags Regions
+// @Description Add an administrative region to a country
+// @ID create-region
+// @Accept  json
+// @Produce  json
+// @Param region body entity.Region true "Region"
+// @Param Idempotency-Key header string false "Retries with the same key from the same client return the stored response; use a UUID"
+// @Success 201 {object} entity.Region
+// @Failure 400,409,422 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/ [post]
+func (h *RegionHandler) CreateRegion(c *gin.Context) {
+	var region entity.Region
+	if !bindJSON(c, &region) {
+		return
+	}
+
+	created, err := h.service.Create(c.Request.Context(), &region)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary Update region
+// @Tags Regions
+// @Description Update region by ID
+// @ID update-region
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Region ID"
+// @Param region body entity.Region true "Region"
+// @Success 200 {object} entity.Region
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/{id} [put]
+func (h *RegionHandler) UpdateRegion(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	var region entity.Region
+	if !bindJSON(c, &region) {
+		return
+	}
+	region.ID = id
+
+	updated, err := h.service.Update(c.Request.Context(), &region)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete region
+// @Tags Regions
+// @Description Delete region by ID; its cities and places stay and lose the region link
+// @ID delete-region
+// @Param id path int true "Region ID"
+// @Success 204
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /regions/{id} [delete]
+func (h *RegionHandler) DeleteRegion(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
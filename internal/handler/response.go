@@ -1,19 +1,282 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/ShekleinAleksey/top-places/pkg/mergepatch"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
-type errorResponse struct {
-	Message string `json:"message"`
+const problemContentType = "application/problem+json"
+
+// problemCodeKey - ключ gin.Context, под которым сохраняется код отданной
+// ошибки; его читает журнал аудита
+const problemCodeKey = "problem_code"
+
+// Коды ошибок, которые возникают в самих обработчиках
+const (
+	codeInternalError        = "internal_error"
+	codeUnauthorized         = "unauthorized"
+	codeForbidden            = "forbidden"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeTimeout              = "request_timeout"
+)
+
+// statusClientClosedRequest - статус запроса, клиент которого отключился до
+// ответа (как в nginx); до клиента он не доходит, но виден в журналах
+const statusClientClosedRequest = 499
+
+// problem - тело ответа об ошибке по RFC 7807. Code - стабильный машиночитаемый
+// код ошибки, Fields - ошибки по полям для ошибок валидации.
+type problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Fields   []entity.FieldError `json:"fields,omitempty"`
+	// RetryAfter передается в заголовке Retry-After
+	RetryAfter time.Duration `json:"-"`
 }
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
-func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	logrus.Error(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{message})
+// newErrorResponse прерывает запрос и отвечает problem+json с заданным статусом
+func newErrorResponse(c *gin.Context, statusCode int, code, message string) {
+	writeProblem(c, problem{Status: statusCode, Code: code, Detail: message})
+}
+
+// writeProblem отвечает problem+json и пишет ошибку в журнал запроса:
+// серверные - как ошибки, клиентские - только на уровне debug, их статус и
+// код и так есть в записи доступа
+func writeProblem(c *gin.Context, p problem) {
+	entry := requestLogger(c).WithFields(logrus.Fields{"status": p.Status, "code": p.Code})
+	if p.Status >= http.StatusInternalServerError {
+		entry.Error(p.Detail)
+	} else {
+		entry.Debug(p.Detail)
+	}
+
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	p.Instance = c.Request.URL.Path
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if p.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(p.RetryAfter.Seconds()))))
+	}
+	c.Set(problemCodeKey, p.Code)
+	c.Abort()
+	c.Data(p.Status, problemContentType, body)
+}
+
+// errorHandler - единая точка преобразования ошибок в ответы: обработчики
+// передают ошибку через c.Error, а статус и тело определяются здесь по ее типу
+func errorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		renderError(c)
+	}
+}
+
+// renderError отвечает problem+json по последней ошибке запроса, если ответ
+// еще не записан. Middleware, которым нужен итоговый ответ до errorHandler,
+// вызывают ее сами. Если ошибка вызвана истекшим временем запроса, клиент
+// получает 504, если отключением клиента - ответ не формируется.
+func renderError(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	switch err := c.Request.Context().Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		newErrorResponse(c, http.StatusGatewayTimeout, codeTimeout, "request took too long to process")
+	case errors.Is(err, context.Canceled):
+		c.AbortWithStatus(statusClientClosedRequest)
+	default:
+		writeProblem(c, problemFor(requestLogger(c), c.Errors.Last().Err))
+	}
+}
+
+func problemFor(log *logrus.Entry, err error) problem {
+	serviceErr, ok := service.AsError(err)
+	if !ok {
+		// Детали внутренних ошибок клиенту не отдаются, только в лог
+		log.WithError(err).Error("internal error")
+		return problem{Status: http.StatusInternalServerError, Code: codeInternalError, Detail: "internal server error"}
+	}
+
+	// Вид берется из serviceErr, а не из err: AsError строит его и для
+	// ошибок репозитория (недоступность БД)
+	p := problem{Code: serviceErr.Code, Detail: serviceErr.Message, Fields: serviceErr.Fields, RetryAfter: serviceErr.RetryAfter}
+	switch kind := serviceErr.Kind; {
+	case errors.Is(kind, service.ErrValidation):
+		p.Status = http.StatusBadRequest
+	case errors.Is(kind, service.ErrNotFound):
+		p.Status = http.StatusNotFound
+	case errors.Is(kind, service.ErrConflict):
+		p.Status = http.StatusConflict
+	case errors.Is(kind, service.ErrPreconditionFailed):
+		p.Status = http.StatusPreconditionFailed
+	case errors.Is(kind, service.ErrUnprocessable):
+		p.Status = http.StatusUnprocessableEntity
+	case errors.Is(kind, service.ErrUnavailable):
+		p.Status = http.StatusServiceUnavailable
+	default:
+		p.Status = http.StatusInternalServerError
+	}
+	return p
+}
+
+// bindJSON разбирает тело запроса; при ошибке передает ее в errorHandler
+// с детализацией по полям и возвращает false
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		_ = c.Error(bodyError(err))
+		return false
+	}
+	return true
+}
+
+func bodyError(err error) error {
+	var validateErr validator.ValidationErrors
+	if !errors.As(err, &validateErr) {
+		return service.InvalidJSON(err)
+	}
+
+	v := &service.Validation{}
+	for _, fe := range validateErr {
+		v.Add(fieldPath(fe), validationMessage(fe))
+	}
+	return v.Err()
+}
+
+// fieldPath возвращает путь к полю в JSON-именах без корневого типа:
+// country.name, а не Name
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+// readMergePatch читает тело PATCH-запроса. Принимается
+// application/merge-patch+json и, для простых клиентов, application/json.
+func readMergePatch(c *gin.Context) ([]byte, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != mergepatch.ContentType && mediaType != binding.MIMEJSON {
+		c.Header("Accept-Patch", mergepatch.ContentType)
+		newErrorResponse(c, http.StatusUnsupportedMediaType, codeUnsupportedMediaType,
+			"PATCH body must be "+mergepatch.ContentType)
+		return nil, false
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		_ = c.Error(service.InvalidJSON(err))
+		return nil, false
+	}
+	return patch, true
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+// useJSONFieldNames заставляет валидатор gin называть поля так же, как в JSON
+func useJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+}
+
+// pathInt читает целочисленный параметр пути; при ошибке передает ее
+// в errorHandler и возвращает ok = false
+func pathInt(c *gin.Context, name string) (int, bool) {
+	n, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		_ = c.Error(service.Invalid(name, "must be an integer"))
+		return 0, false
+	}
+	return n, true
+}
+
+// queryInt читает необязательный целочисленный query-параметр; 0 - если он не задан.
+// При ошибке разбора передает ее в errorHandler и возвращает ok = false.
+func queryInt(c *gin.Context, name string) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		_ = c.Error(service.Invalid(name, "must be an integer"))
+		return 0, false
+	}
+	return n, true
+}
+
+// queryBool читает необязательный логический query-параметр; false - если он не задан
+func queryBool(c *gin.Context, name string) (bool, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		_ = c.Error(service.Invalid(name, "must be a boolean"))
+		return false, false
+	}
+	return b, true
+}
+
+// queryTime читает необязательный query-параметр в формате RFC 3339; нулевое
+// время - если он не задан
+func queryTime(c *gin.Context, name string) (time.Time, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		_ = c.Error(service.Invalid(name, "must be an RFC 3339 timestamp"))
+		return time.Time{}, false
+	}
+	return t, true
 }
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// serveError отвечает на GET /countries/1 ошибкой err через errorHandler
+func serveError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(errorHandler())
+	router.GET("/countries/:id", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/countries/1", nil))
+	return w
+}
+
+func TestProblemResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		detail     string
+		retryAfter string
+	}{
+		{"not found", service.NotFound("country_not_found", "country %d not found", 1),
+			http.StatusNotFound, "country_not_found", "country 1 not found", ""},
+		{"validation", service.Invalid("name", "is required"),
+			http.StatusBadRequest, service.CodeValidation, "invalid input: name: is required", ""},
+		{"conflict", service.Conflict("country_exists", "country exists"),
+			http.StatusConflict, "country_exists", "country exists", ""},
+		{"precondition failed", service.PreconditionFailed("version_mismatch", "stale"),
+			http.StatusPreconditionFailed, "version_mismatch", "stale", ""},
+		{"unprocessable", service.Unprocessable("idempotency_key_reused", "reused"),
+			http.StatusUnprocessableEntity, "idempotency_key_reused", "reused", ""},
+		{"unavailable", service.Unavailable("enrichment_unavailable", "off"),
+			http.StatusServiceUnavailable, "enrichment_unavailable", "off", ""},
+		{"wrapped service error", fmt.Errorf("update: %w", service.NotFound("place_not_found", "gone")),
+			http.StatusNotFound, "place_not_found", "gone", ""},
+		{"database unavailable", fmt.Errorf("get: %w", &repository.UnavailableError{RetryAfter: 1500 * time.Millisecond}),
+			http.StatusServiceUnavailable, "database_unavailable", "database is temporarily unavailable", "2"},
+		{"internal error hides details", errors.New("pq: password authentication failed"),
+			http.StatusInternalServerError, codeInternalError, "internal server error", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveError(tt.err)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+				t.Errorf("Content-Type = %s, want %s", ct, problemContentType)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+
+			var p problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			want := problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(tt.status),
+				Status:   tt.status,
+				Detail:   tt.detail,
+				Instance: "/countries/1",
+				Code:     tt.code,
+			}
+			p.Fields = nil
+			if !reflect.DeepEqual(p, want) {
+				t.Errorf("problem = %+v, want %+v", p, want)
+			}
+		})
+	}
+}
+
+func TestProblemValidationFields(t *testing.T) {
+	v := &service.Validation{}
+	v.Add("latitude", "is required")
+	v.Add("longitude", "is required")
+
+	var p problem
+	if err := json.Unmarshal(serveError(v.Err()).Body.Bytes(), &p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(p.Fields) != 2 || p.Fields[0].Field != "latitude" || p.Fields[1].Field != "longitude" {
+		t.Fatalf("fields = %+v, want latitude and longitude", p.Fields)
+	}
+}
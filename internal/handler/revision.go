@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	service *service.RevisionService
+}
+
+func NewRevisionHandler(service *service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{service: service}
+}
+
+// GetPlaceRevisions возвращает историю изменений места
+// @Summary История изменений места
+// @Description Возвращает ревизии места, начиная с последней: действие, автор (X-User запроса с токеном администратора, иначе anonymous), время и полный снимок. История удаленного места сохраняется.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID места"
+// @Success 200 {array} entity.Revision
+// @Failure 400 {object} problem "Неверный формат ID"
+// @Failure 404 {object} problem "У места нет ревизий"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
+// @Router /places/{id}/revisions [get]
+func (h *RevisionHandler) GetPlaceRevisions(c *gin.Context) {
+	h.list(c, entity.EntityPlace)
+}
+
+// GetPlaceRevision возвращает одну ревизию места
+// @Summary Ревизия места
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID места"
+// @Param rev path int true "Номер ревизии"
+// @Success 200 {object} entity.Revision
+// @Failure 400 {object} problem "Неверный формат параметров"
+// @Failure 404 {object} problem "Ревизия не найдена"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
+// @Router /places/{id}/revisions/{rev} [get]
+func (h *RevisionHandler) GetPlaceRevision(c *gin.Context) {
+	h.get(c, entity.EntityPlace)
+}
+
+// DiffPlaceRevisions сравнивает две ревизии места
+// @Summary Разница между ревизиями места
+// @Description Возвращает поля, значения которых различаются в ревизиях from и to
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID места"
+// @Param from query int true "Номер первой ревизии"
+// @Param to query int true "Номер второй ревизии"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400 {object} problem "Неверный формат параметров"
+// @Failure 404 {object} problem "Ревизия не найдена"
+// @Failure 500 {object} problem "Внутренняя ошибка сервера"
+// @Router /places/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffPlaceRevisions(c *gin.Context) {
+	h.diff(c, entity.EntityPlace)
+}
+
+// @Summary Country revision history
+// @Tags Revisions
+// @Description List country revisions, newest first, with action, actor (X-User header of admin-token requests, otherwise anonymous), time and full snapshot
+// @ID get-country-revisions
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Success 200 {array} entity.Revision
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/revisions [get]
+func (h *RevisionHandler) GetCountryRevisions(c *gin.Context) {
+	h.list(c, entity.EntityCountry)
+}
+
+// @Summary Get country revision
+// @Tags Revisions
+// @ID get-country-revision
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} entity.Revision
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/revisions/{rev} [get]
+func (h *RevisionHandler) GetCountryRevision(c *gin.Context) {
+	h.get(c, entity.EntityCountry)
+}
+
+// @Summary Diff country revisions
+// @Tags Revisions
+// @Description Fields whose values differ between revisions from and to
+// @ID diff-country-revisions
+// @Produce  json
+// @Param id path int true "Country ID"
+// @Param from query int true "First revision"
+// @Param to query int true "Second revision"
+// @Success 200 {object} entity.RevisionDiff
+// @Failure 400,404 {object} problem
+// @Failure 500 {object} problem
+// @Router /countries/{id}/revisions/diff [get]
+func (h *RevisionHandler) DiffCountryRevisions(c *gin.Context) {
+	h.diff(c, entity.EntityCountry)
+}
+
+func (h *RevisionHandler) list(c *gin.Context, entityType string) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	revisions, err := h.service.List(c.Request.Context(), entityType, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *RevisionHandler) get(c *gin.Context, entityType string) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathInt(c, "rev")
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.Get(c.Request.Context(), entityType, id, rev)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+func (h *RevisionHandler) diff(c *gin.Context, entityType string) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+	from, ok := queryInt(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := queryInt(c, "to")
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.Diff(c.Request.Context(), entityType, id, from, to)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
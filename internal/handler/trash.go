@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	service *service.TrashService
+}
+
+func NewTrashHandler(service *service.TrashService) *TrashHandler {
+	return &TrashHandler{service: service}
+}
+
+// ListTrash godoc
+// @Summary List trash
+// @Tags Admin
+// @Description Deleted countries and places that can still be restored, with the time they will be purged
+// @ID list-trash
+// @Produce  json
+// @Security AdminToken
+// @Param type query string false "country or place"
+// @Success 200 {array} entity.TrashItem
+// @Failure 400,401 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	items, err := h.service.List(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreFromTrash godoc
+// @Summary Restore from trash
+// @Tags Admin
+// @Description Restore a deleted country or place. A country comes back with the places deleted together with it; a place of a deleted country cannot be restored.
+// @ID restore-from-trash
+// @Produce  json
+// @Security AdminToken
+// @Param type path string true "country or place"
+// @Param id path int true "Country or place ID"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,404 {object} problem
+// @Failure 409 {object} problem "ISO codes are taken or the place's country is deleted"
+// @Failure 500 {object} problem
+// @Router /admin/trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreFromTrash(c *gin.Context) {
+	id, ok := pathInt(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Restore(c.Request.Context(), c.Param("type"), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "restored"})
+}
+
+// PurgeTrash godoc
+// @Summary Purge trash
+// @Tags Admin
+// @Description Permanently delete records whose retention period has expired; the same job runs on schedule
+// @ID purge-trash
+// @Produce  json
+// @Security AdminToken
+// @Success 200 {object} entity.PurgeReport
+// @Failure 401 {object} problem
+// @Failure 500 {object} problem
+// @Router /admin/trash/purge [post]
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	report, err := h.service.Purge(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
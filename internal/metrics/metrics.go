@@ -0,0 +1,131 @@
+// Package metrics собирает метрики сервиса в формате Prometheus: HTTP-запросы,
+// запросы к БД, состояние пула соединений и число записей.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "top_places"
+
+// statsTimeout ограничивает подсчет записей при одном опросе /metrics
+const statsTimeout = 5 * time.Second
+
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	queryErrors     *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by repository and method.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "Number of failed database queries by repository and method.",
+		}, []string{"repository", "method"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.queryErrors,
+	)
+	return m
+}
+
+// Handler отдает метрики для Prometheus. Если часть метрик собрать не
+// удалось (например, БД недоступна), остальные все равно отдаются.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.requestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveQuery учитывает запрос к БД. Пустой результат (sql.ErrNoRows)
+// ошибкой не считается: так репозитории сообщают, что записи нет.
+func (m *Metrics) ObserveQuery(repository, method string, duration time.Duration, err error) {
+	m.queryDuration.WithLabelValues(repository, method).Observe(duration.Seconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.queryErrors.WithLabelValues(repository, method).Inc()
+	}
+}
+
+// RegisterDBStats добавляет метрики пула соединений db (go_sql_*)
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterStats добавляет число стран, мест и фото. Записи считаются при
+// каждом опросе /metrics.
+func (m *Metrics) RegisterStats(get func(ctx context.Context) (*entity.Stats, error)) {
+	m.registry.MustRegister(&statsCollector{get: get})
+}
+
+var (
+	countriesDesc = prometheus.NewDesc(namespace+"_countries", "Number of countries not in the trash.", nil, nil)
+	placesDesc    = prometheus.NewDesc(namespace+"_places", "Number of places not in the trash.", nil, nil)
+	photosDesc    = prometheus.NewDesc(namespace+"_photos", "Number of photos of places not in the trash.", nil, nil)
+)
+
+type statsCollector struct {
+	get func(ctx context.Context) (*entity.Stats, error)
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- countriesDesc
+	ch <- placesDesc
+	ch <- photosDesc
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+
+	stats, err := c.get(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(countriesDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(countriesDesc, prometheus.GaugeValue, float64(stats.Countries))
+	ch <- prometheus.MustNewConstMetric(placesDesc, prometheus.GaugeValue, float64(stats.Places))
+	ch <- prometheus.MustNewConstMetric(photosDesc, prometheus.GaugeValue, float64(stats.Photos))
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type AuditRepository struct {
+	db Executor
+}
+
+func NewAuditRepository(db Executor) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// auditColumns - колонки журнала; пустые before/after читаются как JSON null
+func auditColumns(ex Executor) string {
+	return fmt.Sprintf(`id, created_at, actor, claimed_actor, ip, request_id, method, route, path, status, outcome,
+	error_code, entity_type, entity_id, %s, %s, duration_ms`, jsonOrNull(ex, "before"), jsonOrNull(ex, "after"))
+}
+
+func (r *AuditRepository) Create(ctx context.Context, e *entity.AuditEntry) error {
+	ctx = withOperation(ctx, "audit", "Create")
+	query := `
+		INSERT INTO audit_log (actor, claimed_actor, ip, request_id, method, route, path, status, outcome,
+			error_code, entity_type, entity_id, before, after, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at
+	`
+
+	err := r.db.GetContext(ctx, e, query, e.Actor, e.ClaimedActor, e.IP, e.RequestID, e.Method, e.Route, e.Path, e.Status, e.Outcome,
+		e.ErrorCode, e.EntityType, e.EntityID, jsonArg(r.db, e.Before), jsonArg(r.db, e.After), e.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// List возвращает записи журнала по фильтру, начиная с последних
+func (r *AuditRepository) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	ctx = withOperation(ctx, "audit", "List")
+	where, args := auditWhere(filter)
+	query := fmt.Sprintf("SELECT %s FROM audit_log %s ORDER BY id DESC", auditColumns(r.db), where)
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	entries := []entity.AuditEntry{}
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Export передает в fn все записи по фильтру, не загружая их в память целиком
+func (r *AuditRepository) Export(ctx context.Context, filter entity.AuditFilter, fn func(*entity.AuditEntry) error) error {
+	ctx = withOperation(ctx, "audit", "Export")
+	where, args := auditWhere(filter)
+	query := fmt.Sprintf("SELECT %s FROM audit_log %s ORDER BY id", auditColumns(r.db), where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e entity.AuditEntry
+		if err := rows.StructScan(&e); err != nil {
+			return fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func auditWhere(filter entity.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		add("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Outcome != "" {
+		add("outcome = $%d", filter.Outcome)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= $%d", dbTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		add("created_at < $%d", dbTime(filter.To))
+	}
+	if filter.BeforeID != 0 {
+		add("id < $%d", filter.BeforeID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
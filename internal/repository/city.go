@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type CityRepository struct {
+	db Executor
+}
+
+func NewCityRepository(db Executor) *CityRepository {
+	return &CityRepository{db: db}
+}
+
+func (r *CityRepository) Create(ctx context.Context, city *entity.City) (*entity.City, error) {
+	ctx = withOperation(ctx, "city", "Create")
+	query := `
+		INSERT INTO cities (name, description, country_id, region_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.GetContext(ctx, &city.ID, query, city.Name, city.Description, city.CountryID, city.RegionID)
+	if err != nil {
+		return nil, dbError("failed to create city", err)
+	}
+
+	return city, nil
+}
+
+func (r *CityRepository) GetByID(ctx context.Context, id int) (*entity.City, error) {
+	ctx = withOperation(ctx, "city", "GetByID")
+	city := &entity.City{}
+	err := r.db.GetContext(ctx, city, "SELECT * FROM cities WHERE id = $1 AND "+inLiveCountry, id)
+	if err != nil {
+		return nil, dbError("failed to get city", err)
+	}
+	return city, nil
+}
+
+func (r *CityRepository) GetAll(ctx context.Context, filter entity.CityFilter) ([]entity.City, error) {
+	ctx = withOperation(ctx, "city", "GetAll")
+	cities := []entity.City{}
+	query := `
+		SELECT *
+		FROM cities
+		WHERE ($1 = 0 OR country_id = $1)
+		  AND ($2 = 0 OR region_id = $2)
+		  AND ` + inLiveCountry + `
+		ORDER BY name
+	`
+	if err := r.db.SelectContext(ctx, &cities, query, filter.CountryID, filter.RegionID); err != nil {
+		return nil, fmt.Errorf("failed to get cities: %w", err)
+	}
+	return cities, nil
+}
+
+func (r *CityRepository) Update(ctx context.Context, city *entity.City) (*entity.City, error) {
+	ctx = withOperation(ctx, "city", "Update")
+	query := `
+		UPDATE cities
+		SET name = :name,
+			description = :description,
+			country_id = :country_id,
+			region_id = :region_id
+		WHERE id = :id AND ` + inLiveCountry + `
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, city)
+	if err != nil {
+		return nil, dbError("failed to update city", err)
+	}
+
+	if err := checkAffected(result, "failed to update city"); err != nil {
+		return nil, err
+	}
+
+	return city, nil
+}
+
+func (r *CityRepository) Delete(ctx context.Context, id int) error {
+	ctx = withOperation(ctx, "city", "Delete")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM cities WHERE id = $1 AND "+inLiveCountry, id)
+	if err != nil {
+		return dbError("failed to delete city", err)
+	}
+
+	if err := checkAffected(result, "failed to delete city"); err != nil {
+		return err
+	}
+
+	return nil
+}
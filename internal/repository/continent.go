@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type ContinentRepository struct {
+	db Executor
+}
+
+func NewContinentRepository(db Executor) *ContinentRepository {
+	return &ContinentRepository{db: db}
+}
+
+func (r *ContinentRepository) GetAll(ctx context.Context) ([]entity.Continent, error) {
+	ctx = withOperation(ctx, "continent", "GetAll")
+	continents := []entity.Continent{}
+	if err := r.db.SelectContext(ctx, &continents, "SELECT code, name FROM continents ORDER BY name"); err != nil {
+		return nil, fmt.Errorf("failed to get continents: %w", err)
+	}
+	return continents, nil
+}
+
+func (r *ContinentRepository) GetByCode(ctx context.Context, code string) (*entity.Continent, error) {
+	ctx = withOperation(ctx, "continent", "GetByCode")
+	continent := &entity.Continent{}
+	err := r.db.GetContext(ctx, continent, "SELECT code, name FROM continents WHERE code = $1", strings.ToUpper(code))
+	if err != nil {
+		return nil, dbError("failed to get continent", err)
+	}
+	return continent, nil
+}
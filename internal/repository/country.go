@@ -1,47 +1,110 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/jmoiron/sqlx"
 )
 
 type CountryRepository struct {
-	db *sqlx.DB
+	db Executor
 }
 
-func NewCountryRepository(db *sqlx.DB) *CountryRepository {
+func NewCountryRepository(db Executor) *CountryRepository {
 	return &CountryRepository{db: db}
 }
 
-func (r *CountryRepository) GetCountries() ([]entity.Country, error) {
+func (r *CountryRepository) GetCountries(ctx context.Context) ([]entity.Country, error) {
+	ctx = withOperation(ctx, "country", "GetCountries")
 	var countries []entity.Country
-	query := fmt.Sprintf("SELECT * FROM countries")
-	err := r.db.Select(&countries, query)
+	query := "SELECT * FROM countries WHERE deleted_at IS NULL"
+	if err := r.db.SelectContext(ctx, &countries, query); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCodes(ctx, countries); err != nil {
+		return nil, err
+	}
 
-	return countries, err
+	return countries, nil
 }
 
-func (r *CountryRepository) GetCountryByID(id int) (entity.Country, error) {
+func (r *CountryRepository) GetCountryByID(ctx context.Context, id int) (entity.Country, error) {
+	ctx = withOperation(ctx, "country", "GetCountryByID")
 	query := `
         SELECT * 
         FROM countries 
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 
 	var country entity.Country
 
-	err := r.db.Get(&country, query, id)
+	err := r.db.GetContext(ctx, &country, query, id)
 	if err != nil {
+		return entity.Country{}, dbError("failed to get country", err)
+	}
+
+	countries := []entity.Country{country}
+	if err := r.loadCodes(ctx, countries); err != nil {
 		return entity.Country{}, err
 	}
 
-	return country, nil
+	return countries[0], nil
+}
+
+// GetCountryByCode ищет страну по коду ISO 3166-1 alpha-2 или alpha-3
+func (r *CountryRepository) GetCountryByCode(ctx context.Context, code string) (entity.Country, error) {
+	ctx = withOperation(ctx, "country", "GetCountryByCode")
+	query := `
+        SELECT * 
+        FROM countries 
+        WHERE (iso2 = $1 OR iso3 = $1) AND deleted_at IS NULL
+    `
+
+	var country entity.Country
+
+	err := r.db.GetContext(ctx, &country, query, strings.ToUpper(code))
+	if err != nil {
+		return entity.Country{}, dbError("failed to get country by code", err)
+	}
+
+	countries := []entity.Country{country}
+	if err := r.loadCodes(ctx, countries); err != nil {
+		return entity.Country{}, err
+	}
+
+	return countries[0], nil
+}
+
+func (r *CountryRepository) GetCountriesByContinent(ctx context.Context, code string) ([]entity.Country, error) {
+	ctx = withOperation(ctx, "country", "GetCountriesByContinent")
+	countries := []entity.Country{}
+	query := `
+        SELECT * 
+        FROM countries 
+        WHERE continent_code = $1 AND deleted_at IS NULL
+        ORDER BY name
+    `
+	if err := r.db.SelectContext(ctx, &countries, query, code); err != nil {
+		return nil, fmt.Errorf("failed to get countries by continent: %w", err)
+	}
+
+	if err := r.loadCodes(ctx, countries); err != nil {
+		return nil, err
+	}
+
+	return countries, nil
 }
 
-func (r *CountryRepository) AddCountry(country *entity.Country) (int, error) {
+func (r *CountryRepository) AddCountry(ctx context.Context, country *entity.Country) (int, error) {
+	ctx = withOperation(ctx, "country", "AddCountry")
 	query := `
         INSERT INTO countries (
             name, 
@@ -49,31 +112,54 @@ func (r *CountryRepository) AddCountry(country *entity.Country) (int, error) {
             language, 
             currency, 
             description, 
-            photo_url
+            photo_url,
+            wikidata_id,
+            population,
+            area,
+            flag_url,
+            iso2,
+            iso3,
+            continent_code
         ) 
-        VALUES ($1, $2, $3, $4, $5, $6)
-        RETURNING id
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id, version, updated_at
     `
 
 	var countryID int
-	err := r.db.QueryRow(
-		query,
-		country.Name,
-		country.Capital,
-		country.Language,
-		country.Currency,
-		country.Description,
-		country.PhotoURL,
-	).Scan(&countryID)
+	err := inTx(ctx, r.db, func(tx Executor) error {
+		err := tx.QueryRowContext(ctx,
+			query,
+			country.Name,
+			country.Capital,
+			country.Language,
+			country.Currency,
+			country.Description,
+			country.PhotoURL,
+			country.WikidataID,
+			country.Population,
+			country.Area,
+			country.FlagURL,
+			country.ISO2,
+			country.ISO3,
+			country.ContinentCode,
+		).Scan(&countryID, &country.Version, &country.UpdatedAt)
+		if err != nil {
+			return dbError("failed to add country", err)
+		}
 
+		return setCodes(ctx, tx, countryID, country.Languages, country.Currencies)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to add country: %v", err)
+		return 0, err
 	}
 
 	return countryID, nil
 }
 
-func (r *CountryRepository) UpdateCountry(country *entity.Country) (*entity.Country, error) {
+// UpdateCountry перезаписывает страну, если ее версия равна country.Version
+// (0 - любая), иначе возвращает ErrVersionMismatch
+func (r *CountryRepository) UpdateCountry(ctx context.Context, country *entity.Country) (*entity.Country, error) {
+	ctx = withOperation(ctx, "country", "UpdateCountry")
 	query := `
         UPDATE countries 
         SET name = :name,
@@ -81,64 +167,248 @@ func (r *CountryRepository) UpdateCountry(country *entity.Country) (*entity.Coun
             language = :language,
             currency = :currency,
             description = :description,
-        WHERE id = :id
-        RETURNING id
+            photo_url = :photo_url,
+            wikidata_id = :wikidata_id,
+            population = :population,
+            area = :area,
+            flag_url = :flag_url,
+            iso2 = :iso2,
+            iso3 = :iso3,
+            continent_code = :continent_code,
+            version = version + 1,
+            updated_at = NOW()
+        WHERE id = :id AND (:version = 0 OR version = :version) AND deleted_at IS NULL
+        RETURNING version, updated_at
     `
 
-	result, err := r.db.NamedExec(query, country)
+	err := inTx(ctx, r.db, func(tx Executor) error {
+		err := getNamed(ctx, tx, country, query, country)
+		if errors.Is(err, sql.ErrNoRows) {
+			return versionError(ctx, tx, "countries", country.ID, "failed to update country")
+		}
+		if err != nil {
+			return dbError("failed to update country", err)
+		}
+
+		return setCodes(ctx, tx, country.ID, country.Languages, country.Currencies)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update country: %w", err)
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	return country, nil
+}
+
+// PatchCountry сохраняет поля, изменившиеся между original и updated.
+// Если страну успели изменить после чтения original, возвращает ErrVersionMismatch.
+func (r *CountryRepository) PatchCountry(ctx context.Context, original, updated *entity.Country) error {
+	ctx = withOperation(ctx, "country", "PatchCountry")
+	changes := changedColumns(countryColumns(original), countryColumns(updated))
+	codesChanged := !reflect.DeepEqual(original.Languages, updated.Languages) ||
+		!reflect.DeepEqual(original.Currencies, updated.Currencies)
+	if len(changes) == 0 && !codesChanged {
+		return nil
 	}
 
-	if rowsAffected == 0 {
-		return nil, fmt.Errorf("country not found")
+	return inTx(ctx, r.db, func(tx Executor) error {
+		if _, err := updateVersioned(ctx, tx, "countries", original.ID, original.Version, changes); err != nil {
+			return err
+		}
+		if codesChanged {
+			return setCodes(ctx, tx, original.ID, updated.Languages, updated.Currencies)
+		}
+		return nil
+	})
+}
+
+// countryColumns - значения колонок страны, которые можно менять через PATCH
+func countryColumns(c *entity.Country) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           c.Name,
+		"capital":        c.Capital,
+		"language":       c.Language,
+		"currency":       c.Currency,
+		"description":    c.Description,
+		"photo_url":      c.PhotoURL,
+		"wikidata_id":    c.WikidataID,
+		"population":     c.Population,
+		"area":           c.Area,
+		"flag_url":       c.FlagURL,
+		"iso2":           c.ISO2,
+		"iso3":           c.ISO3,
+		"continent_code": c.ContinentCode,
 	}
+}
 
-	return country, nil
+// DeleteCountry переносит страну в корзину, если ее версия равна version
+// (0 - любая). Если у страны есть места, без cascade возвращает ErrInUse,
+// а с cascade переносит в корзину и их, с тем же временем удаления.
+// Возвращает ID удаленных вместе со страной мест.
+func (r *CountryRepository) DeleteCountry(ctx context.Context, id int, version int64, cascade bool) ([]int, error) {
+	ctx = withOperation(ctx, "country", "DeleteCountry")
+	placeIDs := []int{}
+	err := inTx(ctx, r.db, func(tx Executor) error {
+		var deletedAt time.Time
+		err := tx.GetContext(ctx, &deletedAt, `
+			UPDATE countries
+			SET deleted_at = NOW(), version = version + 1, updated_at = NOW()
+			WHERE id = $1 AND ($2 = 0 OR version = $2) AND deleted_at IS NULL
+			RETURNING deleted_at
+		`, id, version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return versionError(ctx, tx, "countries", id, "failed to delete country")
+		}
+		if err != nil {
+			return dbError("failed to delete country", err)
+		}
+
+		// Места проверяются в той же транзакции: между предпросмотром и
+		// удалением в стране могли появиться новые
+		if !cascade {
+			var places int
+			err := tx.GetContext(ctx, &places, "SELECT COUNT(*) FROM places WHERE country_id = $1 AND deleted_at IS NULL", id)
+			if err != nil {
+				return fmt.Errorf("failed to count country places: %w", err)
+			}
+			if places > 0 {
+				return fmt.Errorf("country %d has %d places: %w", id, places, ErrInUse)
+			}
+			return nil
+		}
+
+		err = tx.SelectContext(ctx, &placeIDs, `
+			UPDATE places
+			SET deleted_at = $2, version = version + 1, updated_at = NOW()
+			WHERE country_id = $1 AND deleted_at IS NULL
+			RETURNING id
+		`, id, deletedAt)
+		if err != nil {
+			return fmt.Errorf("failed to delete country places: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return placeIDs, nil
 }
 
-func (r *CountryRepository) DeleteCountry(id int) (int, error) {
-	// Проверяем существование страны перед удалением
-	var exists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM countries WHERE id = $1)"
-	err := r.db.Get(&exists, checkQuery, id)
+// DeletePreview возвращает записи, которые затронет удаление страны
+func (r *CountryRepository) DeletePreview(ctx context.Context, id int) (*entity.CountryDeletePreview, error) {
+	ctx = withOperation(ctx, "country", "DeletePreview")
+	preview := &entity.CountryDeletePreview{CountryID: id, Places: []entity.PlaceRef{}}
+
+	err := r.db.SelectContext(ctx, &preview.Places, `
+		SELECT id, name
+		FROM places
+		WHERE country_id = $1 AND deleted_at IS NULL
+		ORDER BY id
+	`, id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check country existence: %w", err)
+		return nil, fmt.Errorf("failed to get country places: %w", err)
 	}
-	if !exists {
-		return 0, fmt.Errorf("country with ID %d not found", id)
+
+	var counts struct {
+		Photos  int `db:"photos"`
+		Regions int `db:"regions"`
+		Cities  int `db:"cities"`
 	}
+	err = r.db.GetContext(ctx, &counts, `
+		SELECT
+			(SELECT COUNT(*) FROM place_photos ph JOIN places p ON p.id = ph.place_id
+				WHERE p.country_id = $1 AND p.deleted_at IS NULL) AS photos,
+			(SELECT COUNT(*) FROM regions WHERE country_id = $1) AS regions,
+			(SELECT COUNT(*) FROM cities WHERE country_id = $1) AS cities
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count country dependents: %w", err)
+	}
+	preview.Photos, preview.Regions, preview.Cities = counts.Photos, counts.Regions, counts.Cities
+
+	return preview, nil
+}
 
-	// Выполняем удаление
-	query := "DELETE FROM countries WHERE id = $1 RETURNING id"
-	var deletedID int
+// ListDeleted возвращает страны из корзины, начиная с последних удаленных
+func (r *CountryRepository) ListDeleted(ctx context.Context) ([]entity.Country, error) {
+	ctx = withOperation(ctx, "country", "ListDeleted")
+	countries := []entity.Country{}
+	query := `
+		SELECT *
+		FROM countries
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &countries, query); err != nil {
+		return nil, fmt.Errorf("failed to list deleted countries: %w", err)
+	}
+	return countries, nil
+}
 
-	err = r.db.QueryRow(query, id).Scan(&deletedID)
+// Restore возвращает страну из корзины вместе с местами, удаленными вместе с
+// ней. Если страны нет в корзине, возвращает ErrNotFound; если ее коды ISO
+// уже заняты другой страной - ErrConflict. Возвращает ID восстановленных мест.
+func (r *CountryRepository) Restore(ctx context.Context, id int) ([]int, error) {
+	ctx = withOperation(ctx, "country", "Restore")
+	placeIDs := []int{}
+	err := inTx(ctx, r.db, func(tx Executor) error {
+		var deletedAt time.Time
+		err := tx.GetContext(ctx, &deletedAt, "SELECT deleted_at FROM countries WHERE id = $1 AND deleted_at IS NOT NULL"+forUpdate(tx), id)
+		if err != nil {
+			return dbError("failed to restore country", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE countries
+			SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+			WHERE id = $1
+		`, id)
+		if err != nil {
+			return dbError("failed to restore country", err)
+		}
+
+		err = tx.SelectContext(ctx, &placeIDs, `
+			UPDATE places
+			SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+			WHERE country_id = $1 AND deleted_at = $2
+			RETURNING id
+		`, id, deletedAt)
+		if err != nil {
+			return fmt.Errorf("failed to restore country places: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to delete country: %w", err)
+		return nil, err
 	}
+	return placeIDs, nil
+}
 
-	return deletedID, nil
+// Purge окончательно удаляет страны, находящиеся в корзине с момента до
+// before. Вместе со страной каскадно удаляются ее места, регионы и города.
+func (r *CountryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withOperation(ctx, "country", "Purge")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM countries WHERE deleted_at < $1", dbTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge countries: %w", err)
+	}
+	return result.RowsAffected()
 }
 
-func (r *CountryRepository) SearchByName(query string, limit int) ([]entity.Country, error) {
+func (r *CountryRepository) SearchByName(ctx context.Context, query string, limit int) ([]entity.Country, error) {
+	ctx = withOperation(ctx, "country", "SearchByName")
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return nil, nil
 	}
 
-	rows, err := r.db.Query(`
-		SELECT id, name, capital, language, currency, description, photo_url
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, name, capital, language, currency, description, photo_url,
+			wikidata_id, population, area, flag_url, iso2, iso3, continent_code, version, updated_at
 		FROM countries
-		WHERE name ILIKE '%' || $1 || '%'
+		WHERE %s AND deleted_at IS NULL
 		ORDER BY name
 		LIMIT $2
-	`, query, limit)
+	`, nameContains(r.db, "name", 1)), query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search countries: %w", err)
 	}
@@ -147,7 +417,9 @@ func (r *CountryRepository) SearchByName(query string, limit int) ([]entity.Coun
 	var countries []entity.Country
 	for rows.Next() {
 		var c entity.Country
-		if err := rows.Scan(&c.ID, &c.Name, &c.Capital, &c.Language, &c.Currency, &c.Description, &c.PhotoURL); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Capital, &c.Language, &c.Currency, &c.Description, &c.PhotoURL,
+			&c.WikidataID, &c.Population, &c.Area, &c.FlagURL, &c.ISO2, &c.ISO3, &c.ContinentCode,
+			&c.Version, &c.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan country: %w", err)
 		}
 		countries = append(countries, c)
@@ -157,5 +429,123 @@ func (r *CountryRepository) SearchByName(query string, limit int) ([]entity.Coun
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
+	if err := r.loadCodes(ctx, countries); err != nil {
+		return nil, err
+	}
+
 	return countries, nil
 }
+
+// loadCodes заполняет коды языков и валют для списка стран двумя запросами
+func (r *CountryRepository) loadCodes(ctx context.Context, countries []entity.Country) error {
+	if len(countries) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(countries))
+	index := make(map[int]*entity.Country, len(countries))
+	for i := range countries {
+		ids[i] = int64(countries[i].ID)
+		countries[i].Languages = []string{}
+		countries[i].Currencies = []string{}
+		index[countries[i].ID] = &countries[i]
+	}
+
+	var languages []struct {
+		CountryID int    `db:"country_id"`
+		Code      string `db:"language_code"`
+	}
+	err := r.selectIn(ctx, &languages, `
+		SELECT country_id, language_code
+		FROM country_languages
+		WHERE country_id IN (?)
+		ORDER BY language_code
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get country languages: %w", err)
+	}
+	for _, l := range languages {
+		index[l.CountryID].Languages = append(index[l.CountryID].Languages, l.Code)
+	}
+
+	var currencies []struct {
+		CountryID int    `db:"country_id"`
+		Code      string `db:"currency_code"`
+	}
+	err = r.selectIn(ctx, &currencies, `
+		SELECT country_id, currency_code
+		FROM country_currencies
+		WHERE country_id IN (?)
+		ORDER BY currency_code
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get country currencies: %w", err)
+	}
+	for _, c := range currencies {
+		index[c.CountryID].Currencies = append(index[c.CountryID].Currencies, c.Code)
+	}
+
+	return nil
+}
+
+// selectIn выполняет запрос с одним плейсхолдером ?, в который подставляется
+// список ids, на любом драйвере
+func (r *CountryRepository) selectIn(ctx context.Context, dest interface{}, query string, ids []int64) error {
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return err
+	}
+	return r.db.SelectContext(ctx, dest, r.db.Rebind(query), args...)
+}
+
+// setCodes заменяет языки и валюты страны; вызывается внутри транзакции
+func setCodes(ctx context.Context, tx Executor, countryID int, languages, currencies []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM country_languages WHERE country_id = $1", countryID); err != nil {
+		return fmt.Errorf("failed to clear country languages: %w", err)
+	}
+	for _, code := range languages {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO country_languages (country_id, language_code) VALUES ($1, $2)", countryID, code); err != nil {
+			return dbError("failed to add country language "+code, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM country_currencies WHERE country_id = $1", countryID); err != nil {
+		return fmt.Errorf("failed to clear country currencies: %w", err)
+	}
+	for _, code := range currencies {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO country_currencies (country_id, currency_code) VALUES ($1, $2)", countryID, code); err != nil {
+			return dbError("failed to add country currency "+code, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateField обновляет одно поле страны; используется обогащением
+func (r *CountryRepository) UpdateField(ctx context.Context, countryID int, field string, value interface{}) error {
+	ctx = withOperation(ctx, "country", "UpdateField")
+	column, ok := countryEnrichableColumns[field]
+	if !ok {
+		return fmt.Errorf("unknown country field %q", field)
+	}
+
+	query := fmt.Sprintf("UPDATE countries SET %s = $1, version = version + 1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL", column)
+	result, err := r.db.ExecContext(ctx, query, value, countryID)
+	if err != nil {
+		return dbError("failed to update country "+field, err)
+	}
+
+	return checkAffected(result, "failed to update country "+field)
+}
+
+// countryEnrichableColumns - белый список колонок для UpdateField
+var countryEnrichableColumns = map[string]string{
+	entity.CountryFieldCapital:     "capital",
+	entity.CountryFieldLanguage:    "language",
+	entity.CountryFieldCurrency:    "currency",
+	entity.CountryFieldDescription: "description",
+	entity.CountryFieldPopulation:  "population",
+	entity.CountryFieldArea:        "area",
+	entity.CountryFieldFlagURL:     "flag_url",
+	entity.CountryFieldWikidataID:  "wikidata_id",
+}
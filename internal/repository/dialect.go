@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Драйверы БД, с которыми работают репозитории. Запросы написаны для
+// PostgreSQL; SQLite принимает те же плейсхолдеры $N, RETURNING и
+// ON CONFLICT, а now() и unicode_lower регистрирует pkg/sqlite. Различия
+// собраны в функциях ниже.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+func isSQLite(ex Executor) bool {
+	return ex.DriverName() == DriverSQLite
+}
+
+// nameContains - условие "column содержит $arg без учета регистра"
+func nameContains(ex Executor, column string, arg int) string {
+	if isSQLite(ex) {
+		return fmt.Sprintf("unicode_lower(%s) LIKE '%%' || unicode_lower($%d) || '%%'", column, arg)
+	}
+	return fmt.Sprintf("%s ILIKE '%%' || $%d || '%%'", column, arg)
+}
+
+// forUpdate блокирует выбранные строки до конца транзакции. В SQLite
+// транзакция и так держит блокировку записи всей БД (_txlock=immediate).
+func forUpdate(ex Executor) string {
+	if isSQLite(ex) {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// jsonArg передает JSON в колонку JSONB (PostgreSQL) или BLOB (SQLite);
+// пустое значение - NULL. lib/pq отправляет []byte как bytea, поэтому
+// PostgreSQL получает строку; modernc.org/sqlite возвращает TEXT строкой,
+// которую нельзя прочитать в json.RawMessage, поэтому SQLite получает байты.
+func jsonArg(ex Executor, raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	if isSQLite(ex) {
+		return []byte(raw)
+	}
+	return string(raw)
+}
+
+// jsonOrNull читает колонку JSON, в которой NULL заменяется на JSON null
+func jsonOrNull(ex Executor, column string) string {
+	if isSQLite(ex) {
+		return fmt.Sprintf("COALESCE(%s, CAST('null' AS BLOB)) AS %s", column, column)
+	}
+	return fmt.Sprintf("COALESCE(%s, 'null') AS %s", column, column)
+}
+
+// dbTime приводит время к UTC: SQLite хранит время строкой и сравнивает
+// строки, поэтому у всех значений должно быть одно смещение
+func dbTime(t time.Time) time.Time {
+	return t.UTC()
+}
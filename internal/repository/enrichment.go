@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type EnrichmentRepository struct {
+	db Executor
+}
+
+func NewEnrichmentRepository(db Executor) *EnrichmentRepository {
+	return &EnrichmentRepository{db: db}
+}
+
+func (r *EnrichmentRepository) Create(ctx context.Context, e *entity.CountryEnrichment) (*entity.CountryEnrichment, error) {
+	ctx = withOperation(ctx, "enrichment", "Create")
+	query := `
+		INSERT INTO country_enrichments (country_id, field, current_value, proposed_value, source, source_ref, status, reviewed_at)
+		VALUES (:country_id, :field, :current_value, :proposed_value, :source, :source_ref, :status, :reviewed_at)
+		RETURNING id, created_at
+	`
+
+	rows, err := sqlx.NamedQueryContext(ctx, r.db, query, e)
+	if err != nil {
+		return nil, dbError("failed to create enrichment", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&e.ID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan created enrichment: %w", err)
+		}
+	}
+
+	return e, rows.Err()
+}
+
+func (r *EnrichmentRepository) GetByID(ctx context.Context, id int) (*entity.CountryEnrichment, error) {
+	ctx = withOperation(ctx, "enrichment", "GetByID")
+	e := &entity.CountryEnrichment{}
+	err := r.db.GetContext(ctx, e, "SELECT * FROM country_enrichments WHERE id = $1", id)
+	if err != nil {
+		return nil, dbError("failed to get enrichment", err)
+	}
+	return e, nil
+}
+
+// List возвращает предложения, отфильтрованные по статусу и стране (пустые фильтры игнорируются)
+func (r *EnrichmentRepository) List(ctx context.Context, status string, countryID int) ([]entity.CountryEnrichment, error) {
+	ctx = withOperation(ctx, "enrichment", "List")
+	query := `
+		SELECT *
+		FROM country_enrichments
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = 0 OR country_id = $2)
+		ORDER BY created_at DESC, id DESC
+	`
+
+	enrichments := []entity.CountryEnrichment{}
+	if err := r.db.SelectContext(ctx, &enrichments, query, status, countryID); err != nil {
+		return nil, fmt.Errorf("failed to list enrichments: %w", err)
+	}
+	return enrichments, nil
+}
+
+// LastApplied возвращает последнее значение поля, записанное обогащением
+func (r *EnrichmentRepository) LastApplied(ctx context.Context, countryID int, field string) (string, bool, error) {
+	ctx = withOperation(ctx, "enrichment", "LastApplied")
+	query := `
+		SELECT proposed_value
+		FROM country_enrichments
+		WHERE country_id = $1 AND field = $2 AND status = $3
+		ORDER BY reviewed_at DESC NULLS LAST, id DESC
+		LIMIT 1
+	`
+
+	var value string
+	err := r.db.GetContext(ctx, &value, query, countryID, field, entity.EnrichmentApplied)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get last applied enrichment: %w", err)
+	}
+	return value, true, nil
+}
+
+// Exists проверяет, было ли такое значение уже предложено (ожидает решения или отклонено)
+func (r *EnrichmentRepository) Exists(ctx context.Context, countryID int, field, value string) (bool, error) {
+	ctx = withOperation(ctx, "enrichment", "Exists")
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM country_enrichments
+			WHERE country_id = $1 AND field = $2 AND proposed_value = $3 AND status IN ($4, $5)
+		)
+	`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, countryID, field, value, entity.EnrichmentPending, entity.EnrichmentRejected)
+	if err != nil {
+		return false, fmt.Errorf("failed to check enrichment existence: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *EnrichmentRepository) SetStatus(ctx context.Context, id int, status string) error {
+	ctx = withOperation(ctx, "enrichment", "SetStatus")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE country_enrichments
+		SET status = $1, reviewed_at = NOW()
+		WHERE id = $2
+	`, status, id)
+	if err != nil {
+		return dbError("failed to update enrichment status", err)
+	}
+
+	if err := checkAffected(result, "failed to update enrichment status"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Ошибки репозиториев, не зависящие от драйвера БД
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrConflict  = errors.New("record conflicts with an existing one")
+	ErrReference = errors.New("referenced record does not exist")
+	// ErrInUse - запись нельзя удалить, пока на нее ссылаются другие
+	ErrInUse = errors.New("record is referenced by other records")
+	// ErrVersionMismatch - запись изменилась после того, как клиент ее прочитал
+	ErrVersionMismatch = errors.New("record version mismatch")
+	// ErrUnavailable - БД недоступна; конкретная ошибка - *UnavailableError
+	ErrUnavailable = errors.New("database is unavailable")
+)
+
+// Коды ошибок PostgreSQL
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+// dbError добавляет к ошибке драйвера описание операции и классифицирует ее,
+// чтобы сервисы могли проверять errors.Is(err, ErrNotFound) и т.п.
+func dbError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, ErrNotFound)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return fmt.Errorf("%s: %w: %w", op, ErrConflict, err)
+		case pqForeignKeyViolation:
+			return fmt.Errorf("%s: %w: %w", op, ErrReference, err)
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return fmt.Errorf("%s: %w: %w", op, ErrConflict, err)
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return fmt.Errorf("%s: %w: %w", op, ErrReference, err)
+		}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// versionError выясняет, почему версионированное изменение не затронуло
+// строку: записи нет или она в корзине (ErrNotFound), или ее версия уже
+// другая (ErrVersionMismatch). Таблица должна поддерживать мягкое удаление.
+func versionError(ctx context.Context, q sqlx.QueryerContext, table string, id int, op string) error {
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)", table)
+	if err := sqlx.GetContext(ctx, q, &exists, query, id); err != nil {
+		return fmt.Errorf("failed to check %s existence: %w", table, err)
+	}
+	if !exists {
+		return fmt.Errorf("%s: %w", op, ErrNotFound)
+	}
+	return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+}
+
+// checkAffected возвращает ErrNotFound, если запрос не затронул ни одной строки
+func checkAffected(result sql.Result, op string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrNotFound)
+	}
+	return nil
+}
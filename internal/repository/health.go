@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/migrations"
+)
+
+type HealthRepository struct {
+	db      Executor
+	breaker *Breaker
+}
+
+func NewHealthRepository(db Executor) *HealthRepository {
+	return &HealthRepository{db: db}
+}
+
+// Driver возвращает драйвер БД: DriverPostgres или DriverSQLite
+func (r *HealthRepository) Driver() string {
+	return r.db.DriverName()
+}
+
+// Breaker возвращает состояние выключателя БД или nil, если он не включен
+func (r *HealthRepository) Breaker() *entity.BreakerStatus {
+	if r.breaker == nil {
+		return nil
+	}
+	return r.breaker.Status()
+}
+
+// Ping проверяет, что БД отвечает на запросы
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	ctx = withOperation(ctx, "health", "Ping")
+	var one int
+	if err := r.db.GetContext(ctx, &one, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion возвращает номер последней примененной миграции. dirty -
+// миграция PostgreSQL прервалась и схема в промежуточном состоянии.
+// Миграции PostgreSQL ведет golang-migrate (одна строка version, dirty),
+// SQLite - sqlite.Migrate (строка на каждую примененную миграцию).
+func (r *HealthRepository) SchemaVersion(ctx context.Context) (version int, dirty bool, err error) {
+	ctx = withOperation(ctx, "health", "SchemaVersion")
+	if isSQLite(r.db) {
+		var name sql.NullString
+		if err := r.db.GetContext(ctx, &name, "SELECT MAX(version) FROM schema_migrations"); err != nil {
+			return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+		}
+		if !name.Valid {
+			return 0, false, nil
+		}
+		version, err := migrations.Version(name.String)
+		return version, false, err
+	}
+
+	row := struct {
+		Version int  `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}{}
+	err = r.db.GetContext(ctx, &row, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return row.Version, row.Dirty, nil
+}
+
+// Storage проверяет, что таблицы с данными доступны для чтения
+func (r *HealthRepository) Storage(ctx context.Context) error {
+	ctx = withOperation(ctx, "health", "Storage")
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM (SELECT 1 FROM countries LIMIT 1) c) +
+			(SELECT COUNT(*) FROM (SELECT 1 FROM places LIMIT 1) p)
+	`
+
+	var n int
+	if err := r.db.GetContext(ctx, &n, query); err != nil {
+		return fmt.Errorf("failed to read data tables: %w", err)
+	}
+	return nil
+}
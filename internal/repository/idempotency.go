@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type IdempotencyRepository struct {
+	db Executor
+}
+
+func NewIdempotencyRepository(db Executor) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Acquire занимает ключ для нового запроса. Ключ, созданный раньше
+// expiredBefore, считается свободным и перезаписывается. Если ключ занят,
+// возвращает false и существующую запись.
+func (r *IdempotencyRepository) Acquire(ctx context.Context, actor, key, requestHash string, expiredBefore time.Time) (bool, *entity.IdempotencyRecord, error) {
+	ctx = withOperation(ctx, "idempotency", "Acquire")
+	var acquired bool
+	err := r.db.GetContext(ctx, &acquired, `
+		INSERT INTO idempotency_keys (actor, key, request_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (actor, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status = 0, headers = '{}', body = NULL, created_at = NOW()
+		WHERE idempotency_keys.created_at < $4
+		RETURNING true
+	`, actor, key, requestHash, dbTime(expiredBefore))
+	if err == nil {
+		return true, nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, nil, fmt.Errorf("failed to acquire idempotency key: %w", err)
+	}
+
+	var row struct {
+		entity.IdempotencyRecord
+		Headers []byte `db:"headers"`
+	}
+	err = r.db.GetContext(ctx, &row, "SELECT * FROM idempotency_keys WHERE actor = $1 AND key = $2", actor, key)
+	if err != nil {
+		// Ключ успели освободить между запросами - клиент может повторить
+		return false, nil, dbError("failed to get idempotency key", err)
+	}
+	record := row.IdempotencyRecord
+	if err := json.Unmarshal(row.Headers, &record.Headers); err != nil {
+		return false, nil, fmt.Errorf("failed to decode idempotency headers: %w", err)
+	}
+	return false, &record, nil
+}
+
+// Complete сохраняет ответ на запрос
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	ctx = withOperation(ctx, "idempotency", "Complete")
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency headers: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status = $3, headers = $4, body = $5
+		WHERE actor = $1 AND key = $2
+	`, record.Actor, record.Key, record.Status, string(headers), record.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает ключ, если запрос не удалось выполнить
+func (r *IdempotencyRepository) Release(ctx context.Context, actor, key string) error {
+	ctx = withOperation(ctx, "idempotency", "Release")
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE actor = $1 AND key = $2", actor, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Purge удаляет ключи, созданные раньше before
+func (r *IdempotencyRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withOperation(ctx, "idempotency", "Purge")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1", dbTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,345 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+type CountryRepository struct {
+	db *DB
+}
+
+func NewCountryRepository(db *DB) *CountryRepository {
+	return &CountryRepository{db: db}
+}
+
+var _ repository.CountryStore = (*CountryRepository)(nil)
+
+func (r *CountryRepository) GetCountries(ctx context.Context) ([]entity.Country, error) {
+	return r.list(func(c *entity.Country) bool { return true }, byID), nil
+}
+
+func (r *CountryRepository) GetCountryByID(ctx context.Context, id int) (entity.Country, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, ok := r.db.activeCountry(id)
+	if !ok {
+		return entity.Country{}, fmt.Errorf("failed to get country: %w", repository.ErrNotFound)
+	}
+	return copyCountry(c), nil
+}
+
+func (r *CountryRepository) GetCountryByCode(ctx context.Context, code string) (entity.Country, error) {
+	code = strings.ToUpper(code)
+	countries := r.list(func(c *entity.Country) bool { return c.ISO2 == code || c.ISO3 == code }, byID)
+	if len(countries) == 0 {
+		return entity.Country{}, fmt.Errorf("failed to get country by code: %w", repository.ErrNotFound)
+	}
+	return countries[0], nil
+}
+
+func (r *CountryRepository) GetCountriesByContinent(ctx context.Context, code string) ([]entity.Country, error) {
+	return r.list(func(c *entity.Country) bool { return c.ContinentCode == code }, byName), nil
+}
+
+func (r *CountryRepository) SearchByName(ctx context.Context, query string, limit int) ([]entity.Country, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	countries := r.list(func(c *entity.Country) bool { return matchName(c.Name, query) }, byName)
+	if len(countries) == 0 {
+		return nil, nil
+	}
+	if limit >= 0 && len(countries) > limit {
+		countries = countries[:limit]
+	}
+	return countries, nil
+}
+
+func (r *CountryRepository) AddCountry(ctx context.Context, country *entity.Country) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored := copyCountry(country)
+	stored.ID = 0
+	if r.db.isoTaken(&stored) {
+		return 0, fmt.Errorf("failed to add country: %w", repository.ErrConflict)
+	}
+
+	r.db.lastID.country++
+	stored.ID = r.db.lastID.country
+	stored.Version = 1
+	stored.UpdatedAt = r.db.now()
+	stored.DeletedAt = nil
+	r.db.countries[stored.ID] = &stored
+
+	country.Version, country.UpdatedAt = stored.Version, stored.UpdatedAt
+	return stored.ID, nil
+}
+
+// UpdateCountry перезаписывает страну, если ее версия равна country.Version
+// (0 - любая)
+func (r *CountryRepository) UpdateCountry(ctx context.Context, country *entity.Country) (*entity.Country, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	current, err := r.versioned(country.ID, country.Version, "failed to update country")
+	if err != nil {
+		return nil, err
+	}
+
+	updated := copyCountry(country)
+	if r.db.isoTaken(&updated) {
+		return nil, fmt.Errorf("failed to update country: %w", repository.ErrConflict)
+	}
+	updated.Version = current.Version + 1
+	updated.UpdatedAt = r.db.now()
+	updated.DeletedAt = nil
+	r.db.countries[country.ID] = &updated
+
+	country.Version, country.UpdatedAt = updated.Version, updated.UpdatedAt
+	return country, nil
+}
+
+func (r *CountryRepository) PatchCountry(ctx context.Context, original, updated *entity.Country) error {
+	if reflect.DeepEqual(patchable(original), patchable(updated)) {
+		return nil
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	current, err := r.versioned(original.ID, original.Version, "failed to update countries")
+	if err != nil {
+		return err
+	}
+
+	patched := copyCountry(updated)
+	patched.ID = current.ID
+	if r.db.isoTaken(&patched) {
+		return fmt.Errorf("failed to update countries: %w", repository.ErrConflict)
+	}
+	patched.Version = current.Version + 1
+	patched.UpdatedAt = r.db.now()
+	patched.DeletedAt = nil
+	r.db.countries[current.ID] = &patched
+	return nil
+}
+
+// patchable - поля страны, которые сохраняет PatchCountry
+func patchable(c *entity.Country) entity.Country {
+	out := copyCountry(c)
+	out.ID, out.Version, out.UpdatedAt, out.DeletedAt = 0, 0, time.Time{}, nil
+	return out
+}
+
+func (r *CountryRepository) UpdateField(ctx context.Context, countryID int, field string, value interface{}) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, ok := r.db.activeCountry(countryID)
+	if !ok {
+		return fmt.Errorf("failed to update country %s: %w", field, repository.ErrNotFound)
+	}
+	if err := setField(c, field, value); err != nil {
+		return err
+	}
+	c.Version++
+	c.UpdatedAt = r.db.now()
+	return nil
+}
+
+func setField(c *entity.Country, field string, value interface{}) error {
+	switch field {
+	case entity.CountryFieldCapital:
+		c.Capital = fmt.Sprint(value)
+	case entity.CountryFieldLanguage:
+		c.Language = fmt.Sprint(value)
+	case entity.CountryFieldCurrency:
+		c.Currency = fmt.Sprint(value)
+	case entity.CountryFieldDescription:
+		c.Description = fmt.Sprint(value)
+	case entity.CountryFieldFlagURL:
+		c.FlagURL = fmt.Sprint(value)
+	case entity.CountryFieldWikidataID:
+		c.WikidataID = fmt.Sprint(value)
+	case entity.CountryFieldPopulation:
+		population, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("country population must be int64, got %T", value)
+		}
+		c.Population = &population
+	case entity.CountryFieldArea:
+		area, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("country area must be float64, got %T", value)
+		}
+		c.Area = &area
+	default:
+		return fmt.Errorf("unknown country field %q", field)
+	}
+	return nil
+}
+
+func (r *CountryRepository) DeleteCountry(ctx context.Context, id int, version int64, cascade bool) ([]int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, err := r.versioned(id, version, "failed to delete country")
+	if err != nil {
+		return nil, err
+	}
+
+	places := r.countryPlaces(id)
+	if len(places) > 0 && !cascade {
+		return nil, fmt.Errorf("country %d has %d places: %w", id, len(places), repository.ErrInUse)
+	}
+
+	now := r.db.now()
+	c.DeletedAt, c.UpdatedAt = &now, now
+	c.Version++
+
+	placeIDs := []int{}
+	for _, p := range places {
+		deletedAt := now
+		p.DeletedAt, p.UpdatedAt = &deletedAt, now
+		p.Version++
+		placeIDs = append(placeIDs, p.ID)
+	}
+	return placeIDs, nil
+}
+
+// DeletePreview считает места и их фото; регионов и городов в памяти нет
+func (r *CountryRepository) DeletePreview(ctx context.Context, id int) (*entity.CountryDeletePreview, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	preview := &entity.CountryDeletePreview{CountryID: id, Places: []entity.PlaceRef{}}
+	for _, p := range r.countryPlaces(id) {
+		preview.Places = append(preview.Places, entity.PlaceRef{ID: p.ID, Name: p.Name})
+		preview.Photos += len(p.PhotoURLs)
+	}
+	return preview, nil
+}
+
+func (r *CountryRepository) ListDeleted(ctx context.Context) ([]entity.Country, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	countries := []entity.Country{}
+	for _, c := range r.db.countries {
+		if c.DeletedAt != nil {
+			countries = append(countries, copyCountry(c))
+		}
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		return countries[i].DeletedAt.After(*countries[j].DeletedAt)
+	})
+	return countries, nil
+}
+
+func (r *CountryRepository) Restore(ctx context.Context, id int) ([]int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	c, ok := r.db.countries[id]
+	if !ok || c.DeletedAt == nil {
+		return nil, fmt.Errorf("failed to restore country: %w", repository.ErrNotFound)
+	}
+	if r.db.isoTaken(c) {
+		return nil, fmt.Errorf("failed to restore country: %w", repository.ErrConflict)
+	}
+
+	deletedAt, now := *c.DeletedAt, r.db.now()
+	c.DeletedAt, c.UpdatedAt = nil, now
+	c.Version++
+
+	placeIDs := []int{}
+	for _, p := range r.db.places {
+		if p.CountryID == id && p.DeletedAt != nil && p.DeletedAt.Equal(deletedAt) {
+			p.DeletedAt, p.UpdatedAt = nil, now
+			p.Version++
+			placeIDs = append(placeIDs, p.ID)
+		}
+	}
+	sort.Ints(placeIDs)
+	return placeIDs, nil
+}
+
+// Purge удаляет страны из корзины вместе со всеми их местами
+func (r *CountryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var purged int64
+	for id, c := range r.db.countries {
+		if c.DeletedAt == nil || !c.DeletedAt.Before(before) {
+			continue
+		}
+		for placeID, p := range r.db.places {
+			if p.CountryID == id {
+				delete(r.db.places, placeID)
+			}
+		}
+		delete(r.db.countries, id)
+		purged++
+	}
+	return purged, nil
+}
+
+// versioned возвращает страну не из корзины с версией version (0 - любая)
+func (r *CountryRepository) versioned(id int, version int64, op string) (*entity.Country, error) {
+	c, ok := r.db.activeCountry(id)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+	if version != 0 && c.Version != version {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrVersionMismatch)
+	}
+	return c, nil
+}
+
+// countryPlaces возвращает места страны не из корзины по возрастанию ID
+func (r *CountryRepository) countryPlaces(id int) []*entity.Place {
+	var places []*entity.Place
+	for _, p := range r.db.places {
+		if p.CountryID == id && p.DeletedAt == nil {
+			places = append(places, p)
+		}
+	}
+	sort.Slice(places, func(i, j int) bool { return places[i].ID < places[j].ID })
+	return places
+}
+
+func byID(a, b *entity.Country) bool { return a.ID < b.ID }
+
+func byName(a, b *entity.Country) bool { return a.Name < b.Name }
+
+// list возвращает копии стран не из корзины, подходящих под match
+func (r *CountryRepository) list(match func(*entity.Country) bool, less func(a, b *entity.Country) bool) []entity.Country {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var matched []*entity.Country
+	for _, c := range r.db.countries {
+		if c.DeletedAt == nil && match(c) {
+			matched = append(matched, c)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+
+	countries := make([]entity.Country, len(matched))
+	for i, c := range matched {
+		countries[i] = copyCountry(c)
+	}
+	return countries
+}
@@ -0,0 +1,109 @@
+// Package memory - хранилища стран, мест и ревизий в памяти с тем же
+// поведением, что у репозиториев PostgreSQL: версии, мягкое удаление, каскад
+// и ошибки repository.ErrNotFound, ErrConflict и т.п. Нужны для тестов
+// сервисов без БД (см. Stores); совпадение поведения проверяет storetest.
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+// DB - общее состояние хранилищ: места ссылаются на страны, а удаление и
+// очистка страны затрагивают ее места, как внешние ключи в PostgreSQL
+type DB struct {
+	mu        sync.Mutex
+	countries map[int]*entity.Country
+	places    map[int]*entity.Place
+	revisions []entity.Revision
+	lastID    struct {
+		country, place int
+		revision       int64
+	}
+	// now - источник времени для updated_at и deleted_at
+	now func() time.Time
+}
+
+func NewDB() *DB {
+	return &DB{
+		countries: make(map[int]*entity.Country),
+		places:    make(map[int]*entity.Place),
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// activeCountry возвращает страну не из корзины
+func (db *DB) activeCountry(id int) (*entity.Country, bool) {
+	c, ok := db.countries[id]
+	if !ok || c.DeletedAt != nil {
+		return nil, false
+	}
+	return c, true
+}
+
+func (db *DB) activePlace(id int) (*entity.Place, bool) {
+	p, ok := db.places[id]
+	if !ok || p.DeletedAt != nil {
+		return nil, false
+	}
+	return p, true
+}
+
+// isoTaken проверяет уникальность кодов ISO среди стран не из корзины, как
+// частичные уникальные индексы idx_countries_iso2 и idx_countries_iso3
+func (db *DB) isoTaken(c *entity.Country) bool {
+	for _, other := range db.countries {
+		if other.ID == c.ID || other.DeletedAt != nil {
+			continue
+		}
+		if c.ISO2 != "" && other.ISO2 == c.ISO2 || c.ISO3 != "" && other.ISO3 == c.ISO3 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName повторяет name ILIKE '%query%'
+func matchName(name, query string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(query))
+}
+
+func copyCountry(c *entity.Country) entity.Country {
+	out := *c
+	out.Languages = sortedCopy(c.Languages)
+	out.Currencies = sortedCopy(c.Currencies)
+	out.Population = copyPtr(c.Population)
+	out.Area = copyPtr(c.Area)
+	out.DeletedAt = copyPtr(c.DeletedAt)
+	return out
+}
+
+func copyPlace(p *entity.Place) *entity.Place {
+	out := *p
+	out.PhotoURLs = append([]string(nil), p.PhotoURLs...)
+	out.Latitude = copyPtr(p.Latitude)
+	out.Longitude = copyPtr(p.Longitude)
+	out.RegionID = copyPtr(p.RegionID)
+	out.CityID = copyPtr(p.CityID)
+	out.DeletedAt = copyPtr(p.DeletedAt)
+	return &out
+}
+
+// sortedCopy - коды языков и валют читаются из БД упорядоченными
+func sortedCopy(codes []string) []string {
+	out := append([]string{}, codes...)
+	sort.Strings(out)
+	return out
+}
+
+func copyPtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
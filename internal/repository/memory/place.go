@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+type PlaceRepository struct {
+	db *DB
+}
+
+func NewPlaceRepository(db *DB) *PlaceRepository {
+	return &PlaceRepository{db: db}
+}
+
+var _ repository.PlaceStore = (*PlaceRepository)(nil)
+
+// Create сохраняет место; страна должна существовать, хотя бы в корзине,
+// как при проверке внешнего ключа
+func (r *PlaceRepository) Create(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.countries[place.CountryID]; !ok {
+		return nil, fmt.Errorf("failed to create place: %w", repository.ErrReference)
+	}
+
+	r.db.lastID.place++
+	place.ID = r.db.lastID.place
+	place.Version = 1
+	place.UpdatedAt = r.db.now()
+	place.DeletedAt = nil
+
+	stored := copyPlace(place)
+	stored.Country = entity.Country{}
+	r.db.places[place.ID] = stored
+	return place, nil
+}
+
+func (r *PlaceRepository) GetByID(ctx context.Context, id int) (*entity.Place, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	p, ok := r.db.activePlace(id)
+	if !ok {
+		return nil, fmt.Errorf("failed to get place: %w", repository.ErrNotFound)
+	}
+	return copyPlace(p), nil
+}
+
+// GetAll возвращает места не из корзины, страна которых тоже не в корзине
+func (r *PlaceRepository) GetAll(ctx context.Context, filter entity.PlaceFilter) ([]*entity.Place, error) {
+	return r.list(func(p *entity.Place) bool {
+		c, ok := r.db.activeCountry(p.CountryID)
+		return ok &&
+			(filter.ContinentCode == "" || c.ContinentCode == filter.ContinentCode) &&
+			(filter.CountryID == 0 || p.CountryID == filter.CountryID) &&
+			(filter.RegionID == 0 || p.RegionID != nil && *p.RegionID == filter.RegionID) &&
+			(filter.CityID == 0 || p.CityID != nil && *p.CityID == filter.CityID)
+	}, byPlaceID), nil
+}
+
+func (r *PlaceRepository) GetPlacesByCountryID(ctx context.Context, countryID int) ([]*entity.Place, error) {
+	places := r.list(func(p *entity.Place) bool { return p.CountryID == countryID }, byPlaceID)
+	if len(places) == 0 {
+		return nil, nil
+	}
+	return places, nil
+}
+
+func (r *PlaceRepository) SearchByName(ctx context.Context, query string, limit int) ([]*entity.Place, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []*entity.Place{}, nil
+	}
+	places := r.list(func(p *entity.Place) bool { return matchName(p.Name, query) }, byPlaceName)
+	if len(places) == 0 {
+		return nil, nil
+	}
+	if limit >= 0 && len(places) > limit {
+		places = places[:limit]
+	}
+	return places, nil
+}
+
+// Update перезаписывает место, кроме страны и фото, если его версия равна
+// place.Version (0 - любая)
+func (r *PlaceRepository) Update(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	current, err := r.versioned(place.ID, place.Version, "failed to update place")
+	if err != nil {
+		return nil, err
+	}
+
+	current.Name = place.Name
+	current.Description = place.Description
+	current.Latitude, current.Longitude = copyPtr(place.Latitude), copyPtr(place.Longitude)
+	current.RegionID, current.CityID = copyPtr(place.RegionID), copyPtr(place.CityID)
+	current.Version++
+	current.UpdatedAt = r.db.now()
+
+	place.Version, place.UpdatedAt = current.Version, current.UpdatedAt
+	return place, nil
+}
+
+func (r *PlaceRepository) Patch(ctx context.Context, original, updated *entity.Place) error {
+	if reflect.DeepEqual(placeFields(original), placeFields(updated)) {
+		return nil
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	current, err := r.versioned(original.ID, original.Version, "failed to update places")
+	if err != nil {
+		return err
+	}
+	if _, ok := r.db.countries[updated.CountryID]; !ok {
+		return fmt.Errorf("failed to update places: %w", repository.ErrReference)
+	}
+
+	patched := copyPlace(updated)
+	patched.ID = current.ID
+	patched.Country = entity.Country{}
+	patched.Version = current.Version + 1
+	patched.UpdatedAt = r.db.now()
+	patched.DeletedAt = nil
+	r.db.places[current.ID] = patched
+	return nil
+}
+
+// placeFields - поля места, которые сохраняет Patch
+func placeFields(p *entity.Place) *entity.Place {
+	out := copyPlace(p)
+	out.ID, out.Version, out.UpdatedAt, out.DeletedAt = 0, 0, time.Time{}, nil
+	out.Country = entity.Country{}
+	if len(out.PhotoURLs) == 0 {
+		out.PhotoURLs = nil
+	}
+	return out
+}
+
+func (r *PlaceRepository) Delete(ctx context.Context, id int, version int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	p, err := r.versioned(id, version, "failed to delete place")
+	if err != nil {
+		return err
+	}
+
+	now := r.db.now()
+	p.DeletedAt, p.UpdatedAt = &now, now
+	p.Version++
+	return nil
+}
+
+func (r *PlaceRepository) ListDeleted(ctx context.Context) ([]*entity.Place, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	places := []*entity.Place{}
+	for _, p := range r.db.places {
+		if p.DeletedAt != nil {
+			places = append(places, copyPlace(p))
+		}
+	}
+	sort.Slice(places, func(i, j int) bool {
+		return places[i].DeletedAt.After(*places[j].DeletedAt)
+	})
+	return places, nil
+}
+
+func (r *PlaceRepository) Restore(ctx context.Context, id int) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	p, ok := r.db.places[id]
+	if !ok || p.DeletedAt == nil {
+		return fmt.Errorf("failed to restore place: %w", repository.ErrNotFound)
+	}
+	if _, ok := r.db.activeCountry(p.CountryID); !ok {
+		return fmt.Errorf("failed to restore place: country is deleted: %w", repository.ErrReference)
+	}
+
+	p.DeletedAt, p.UpdatedAt = nil, r.db.now()
+	p.Version++
+	return nil
+}
+
+func (r *PlaceRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var purged int64
+	for id, p := range r.db.places {
+		if p.DeletedAt != nil && p.DeletedAt.Before(before) {
+			delete(r.db.places, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// versioned возвращает место не из корзины с версией version (0 - любая)
+func (r *PlaceRepository) versioned(id int, version int64, op string) (*entity.Place, error) {
+	p, ok := r.db.activePlace(id)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+	if version != 0 && p.Version != version {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrVersionMismatch)
+	}
+	return p, nil
+}
+
+func byPlaceID(a, b *entity.Place) bool { return a.ID < b.ID }
+
+func byPlaceName(a, b *entity.Place) bool { return a.Name < b.Name }
+
+// list возвращает копии мест не из корзины, подходящих под match
+func (r *PlaceRepository) list(match func(*entity.Place) bool, less func(a, b *entity.Place) bool) []*entity.Place {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var matched []*entity.Place
+	for _, p := range r.db.places {
+		if p.DeletedAt == nil && match(p) {
+			matched = append(matched, p)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return less(matched[i], matched[j]) })
+
+	places := make([]*entity.Place, len(matched))
+	for i, p := range matched {
+		places[i] = copyPlace(p)
+	}
+	return places
+}
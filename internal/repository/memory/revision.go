@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+type RevisionRepository struct {
+	db *DB
+}
+
+func NewRevisionRepository(db *DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+var _ repository.RevisionStore = (*RevisionRepository)(nil)
+
+// Create сохраняет ревизию со следующим номером для сущности
+func (r *RevisionRepository) Create(ctx context.Context, rev *entity.Revision) (*entity.Revision, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	rev.Revision = 1
+	for _, other := range r.db.revisions {
+		if other.EntityType == rev.EntityType && other.EntityID == rev.EntityID && other.Revision >= rev.Revision {
+			rev.Revision = other.Revision + 1
+		}
+	}
+	r.db.lastID.revision++
+	rev.ID = r.db.lastID.revision
+	rev.CreatedAt = r.db.now()
+
+	stored := *rev
+	stored.Snapshot = slices.Clone(rev.Snapshot)
+	r.db.revisions = append(r.db.revisions, stored)
+	return rev, nil
+}
+
+// List возвращает ревизии сущности, начиная с последней
+func (r *RevisionRepository) List(ctx context.Context, entityType string, entityID int) ([]entity.Revision, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	revisions := []entity.Revision{}
+	for i := len(r.db.revisions) - 1; i >= 0; i-- {
+		if rev := r.db.revisions[i]; rev.EntityType == entityType && rev.EntityID == entityID {
+			rev.Snapshot = slices.Clone(rev.Snapshot)
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+func (r *RevisionRepository) Get(ctx context.Context, entityType string, entityID, revision int) (*entity.Revision, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, rev := range r.db.revisions {
+		if rev.EntityType == entityType && rev.EntityID == entityID && rev.Revision == revision {
+			rev.Snapshot = slices.Clone(rev.Snapshot)
+			return &rev, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get revision: %w", repository.ErrNotFound)
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+// TxRunner выполняет fn над хранилищами в памяти. Если fn вернула ошибку,
+// состояние db откатывается к началу транзакции; счетчики ID, как
+// последовательности PostgreSQL, не откатываются. Изоляции нет: изменения,
+// сделанные параллельно с транзакцией, откат тоже отменит.
+type TxRunner struct {
+	db     *DB
+	stores repository.Stores
+}
+
+func NewTxRunner(db *DB, stores repository.Stores) *TxRunner {
+	return &TxRunner{db: db, stores: stores}
+}
+
+var _ repository.TxRunner = (*TxRunner)(nil)
+
+func (r *TxRunner) InTx(ctx context.Context, fn func(tx repository.Stores) error) error {
+	saved := r.db.snapshot()
+	if err := fn(r.stores); err != nil {
+		r.db.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// Stores возвращает хранилища стран, мест и ревизий над db и TxRunner для них
+func Stores(db *DB) (repository.Stores, *TxRunner) {
+	stores := repository.Stores{
+		Countries: NewCountryRepository(db),
+		Places:    NewPlaceRepository(db),
+		Revisions: NewRevisionRepository(db),
+	}
+	return stores, NewTxRunner(db, stores)
+}
+
+// dbState - копия данных db для отката транзакции
+type dbState struct {
+	countries map[int]*entity.Country
+	places    map[int]*entity.Place
+	revisions []entity.Revision
+}
+
+func (db *DB) snapshot() dbState {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	state := dbState{
+		countries: make(map[int]*entity.Country, len(db.countries)),
+		places:    make(map[int]*entity.Place, len(db.places)),
+		// Ревизии не изменяются после записи, достаточно копии среза
+		revisions: slices.Clone(db.revisions),
+	}
+	for id, c := range db.countries {
+		copied := copyCountry(c)
+		state.countries[id] = &copied
+	}
+	for id, p := range db.places {
+		state.places[id] = copyPlace(p)
+	}
+	return state
+}
+
+func (db *DB) restore(state dbState) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.countries = state.countries
+	db.places = state.places
+	db.revisions = state.revisions
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryObserver получает длительность и результат каждого запроса к БД.
+// repository и method - репозиторий и его метод, выполнившие запрос,
+// например "country" и "GetCountryByID".
+type QueryObserver interface {
+	ObserveQuery(repository, method string, duration time.Duration, err error)
+}
+
+// observe оборачивает ex так, чтобы запросы через него передавались в
+// observer; без observer возвращает ex как есть
+func observe(ex Executor, observer QueryObserver) Executor {
+	if observer == nil {
+		return ex
+	}
+	return &observedExecutor{Executor: ex, observer: observer}
+}
+
+// observedExecutor измеряет и трассирует запросы, выполняемые через
+// Executor. Метод репозитория берется из контекста запроса (withOperation).
+type observedExecutor struct {
+	Executor
+	observer QueryObserver
+}
+
+// begin открывает спан запроса; возвращенная функция закрывает его и
+// передает результат в observer
+func (e *observedExecutor) begin(ctx context.Context, query string) (context.Context, func(error)) {
+	repository, method := operationFrom(ctx)
+	ctx, span := tracing.Start(ctx, repository+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNameKey.String(e.DriverName()),
+			semconv.DBQueryText(query),
+		),
+	)
+
+	start := time.Now()
+	return ctx, func(err error) {
+		e.observer.ObserveQuery(repository, method, time.Since(start), err)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (e *observedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := e.begin(ctx, query)
+	rows, err := e.Executor.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (e *observedExecutor) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx, done := e.begin(ctx, query)
+	rows, err := e.Executor.QueryxContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (e *observedExecutor) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	ctx, done := e.begin(ctx, query)
+	row := e.Executor.QueryRowxContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+func (e *observedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := e.begin(ctx, query)
+	row := e.Executor.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+func (e *observedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := e.begin(ctx, query)
+	result, err := e.Executor.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+func (e *observedExecutor) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, done := e.begin(ctx, query)
+	err := e.Executor.GetContext(ctx, dest, query, args...)
+	done(err)
+	return err
+}
+
+func (e *observedExecutor) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, done := e.begin(ctx, query)
+	err := e.Executor.SelectContext(ctx, dest, query, args...)
+	done(err)
+	return err
+}
+
+func (e *observedExecutor) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, done := e.begin(ctx, query)
+	result, err := e.Executor.NamedExecContext(ctx, query, arg)
+	done(err)
+	return result, err
+}
+
+type operationKey struct{}
+
+// operation - репозиторий и его метод, выполняющие запрос
+type operation struct {
+	repository, method string
+}
+
+// withOperation помечает ctx операцией repository.method: под этим именем
+// запросы из ctx попадают в метрики и трассировку. Каждый экспортируемый
+// метод репозитория начинается с такой пометки.
+func withOperation(ctx context.Context, repository, method string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation{repository: repository, method: method})
+}
+
+// operationFrom возвращает операцию ctx или "unknown", если ctx не помечен
+func operationFrom(ctx context.Context) (string, string) {
+	if op, ok := ctx.Value(operationKey{}).(operation); ok {
+		return op.repository, op.method
+	}
+	return "unknown", "unknown"
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// changedColumns возвращает колонки, значения которых в after отличаются от before
+func changedColumns(before, after map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for column, value := range after {
+		if !reflect.DeepEqual(before[column], value) {
+			changes[column] = value
+		}
+	}
+	return changes
+}
+
+// updateVersioned обновляет переданные колонки и увеличивает версию строки,
+// если ее текущая версия равна version (0 - любая). Имена колонок берутся
+// из белых списков репозиториев, а не от клиента. Возвращает новую версию.
+func updateVersioned(ctx context.Context, tx Executor, table string, id int, version int64, changes map[string]interface{}) (int64, error) {
+	columns := make([]string, 0, len(changes))
+	for column := range changes {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	sets := make([]string, 0, len(columns)+2)
+	args := make([]interface{}, 0, len(columns)+2)
+	for _, column := range columns {
+		args = append(args, changes[column])
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	sets = append(sets, "version = version + 1", "updated_at = NOW()")
+	args = append(args, id, version)
+
+	op := "failed to update " + table
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE id = $%d AND ($%d = 0 OR version = $%d) AND deleted_at IS NULL RETURNING version",
+		table, strings.Join(sets, ", "), len(args)-1, len(args), len(args),
+	)
+
+	var newVersion int64
+	err := tx.GetContext(ctx, &newVersion, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, versionError(ctx, tx, table, id, op)
+	}
+	if err != nil {
+		return 0, dbError(op, err)
+	}
+	return newVersion, nil
+}
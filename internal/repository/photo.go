@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// PhotoRepository - фото мест. Фото меняются вместе с местом, поэтому
+// записывающие методы обычно вызываются внутри транзакции места.
+type PhotoRepository struct {
+	db Executor
+}
+
+func NewPhotoRepository(db Executor) *PhotoRepository {
+	return &PhotoRepository{db: db}
+}
+
+// ListURLs возвращает адреса фото места в порядке добавления
+func (r *PhotoRepository) ListURLs(ctx context.Context, placeID int) ([]string, error) {
+	ctx = withOperation(ctx, "photo", "ListURLs")
+	var photos []string
+	err := r.db.SelectContext(ctx, &photos, "SELECT url FROM place_photos WHERE place_id = $1 ORDER BY id", placeID)
+	return photos, err
+}
+
+func (r *PhotoRepository) Add(ctx context.Context, placeID int, url string) error {
+	ctx = withOperation(ctx, "photo", "Add")
+	_, err := r.db.ExecContext(ctx, "INSERT INTO place_photos (place_id, url) VALUES ($1, $2)", placeID, url)
+	if err != nil {
+		return dbError(fmt.Sprintf("failed to add photo %s", url), err)
+	}
+	return nil
+}
+
+// Replace заменяет все фото места списком urls
+func (r *PhotoRepository) Replace(ctx context.Context, placeID int, urls []string) error {
+	ctx = withOperation(ctx, "photo", "Replace")
+	return inTx(ctx, r.db, func(tx Executor) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM place_photos WHERE place_id = $1", placeID); err != nil {
+			return fmt.Errorf("failed to delete photos: %w", err)
+		}
+		photos := NewPhotoRepository(tx)
+		for _, url := range urls {
+			if err := photos.Add(ctx, placeID, url); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
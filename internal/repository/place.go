@@ -1,69 +1,70 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
-	"github.com/jmoiron/sqlx"
 )
 
 type PlaceRepository struct {
-	db *sqlx.DB
+	db     Executor
+	photos *PhotoRepository
 }
 
-func NewPlaceRepository(db *sqlx.DB) *PlaceRepository {
-	return &PlaceRepository{db: db}
+func NewPlaceRepository(db Executor) *PlaceRepository {
+	return &PlaceRepository{db: db, photos: NewPhotoRepository(db)}
 }
 
-func (r *PlaceRepository) Create(place *entity.Place) (*entity.Place, error) {
+// Create сохраняет место вместе с фото в одной транзакции
+func (r *PlaceRepository) Create(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "Create")
 	query := `
-		INSERT INTO places (name, description, longitude, latitude, country_id)
-		VALUES (:name, :description, :longitude, :latitude, :country_id)
-		RETURNING id
+		INSERT INTO places (name, description, longitude, latitude, country_id, region_id, city_id)
+		VALUES (:name, :description, :longitude, :latitude, :country_id, :region_id, :city_id)
+		RETURNING id, version, updated_at
 	`
 
-	rows, err := r.db.NamedQuery(query, place)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create place: %w", err)
-	}
-	defer rows.Close()
-
-	if rows.Next() {
-		if err := rows.StructScan(place); err != nil {
-			return nil, fmt.Errorf("failed to scan created place: %w", err)
+	err := inTx(ctx, r.db, func(tx Executor) error {
+		if err := getNamed(ctx, tx, place, query, place); err != nil {
+			return dbError("failed to create place", err)
 		}
-	}
 
-	if len(place.PhotoURLs) > 0 {
+		photos := NewPhotoRepository(tx)
 		for _, url := range place.PhotoURLs {
-			if err := r.addPhotos(place.ID, url); err != nil {
-				return nil, fmt.Errorf("failed to add photo %s: %w", url, err)
+			if err := photos.Add(ctx, place.ID, url); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return place, nil
 }
 
-func (r *PlaceRepository) GetByID(id int) (*entity.Place, error) {
+func (r *PlaceRepository) GetByID(ctx context.Context, id int) (*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "GetByID")
 	place := &entity.Place{}
 	query := `
 		SELECT *
 		FROM places
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	err := r.db.Get(place, query, id)
+	err := r.db.GetContext(ctx, place, query, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("place not found")
-		}
-		return nil, fmt.Errorf("failed to get place: %w", err)
+		return nil, dbError("failed to get place", err)
 	}
 
-	photos, err := r.getPhotos(id)
+	photos, err := r.photos.ListURLs(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get photos: %w", err)
 	}
@@ -72,20 +73,29 @@ func (r *PlaceRepository) GetByID(id int) (*entity.Place, error) {
 	return place, nil
 }
 
-func (r *PlaceRepository) GetAll() ([]*entity.Place, error) {
-	var places []*entity.Place
+// GetAll возвращает места, отфильтрованные по континенту, стране, региону и городу
+func (r *PlaceRepository) GetAll(ctx context.Context, filter entity.PlaceFilter) ([]*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "GetAll")
+	places := []*entity.Place{}
 	query := `
-		SELECT *
-		FROM places
+		SELECT p.*
+		FROM places p
+		JOIN countries c ON c.id = p.country_id
+		WHERE p.deleted_at IS NULL AND c.deleted_at IS NULL
+		  AND ($1 = '' OR c.continent_code = $1)
+		  AND ($2 = 0 OR p.country_id = $2)
+		  AND ($3 = 0 OR p.region_id = $3)
+		  AND ($4 = 0 OR p.city_id = $4)
+		ORDER BY p.id
 	`
 
-	err := r.db.Select(&places, query)
+	err := r.db.SelectContext(ctx, &places, query, filter.ContinentCode, filter.CountryID, filter.RegionID, filter.CityID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get places: %w", err)
 	}
 
 	for _, place := range places {
-		photos, err := r.getPhotos(place.ID)
+		photos, err := r.photos.ListURLs(ctx, place.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get photos for place %d: %w", place.ID, err)
 		}
@@ -95,28 +105,30 @@ func (r *PlaceRepository) GetAll() ([]*entity.Place, error) {
 	return places, nil
 }
 
-func (r *PlaceRepository) Update(place *entity.Place) (*entity.Place, error) {
+// Update перезаписывает место, если его версия равна place.Version (0 - любая),
+// иначе возвращает ErrVersionMismatch
+func (r *PlaceRepository) Update(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "Update")
 	query := `
 		UPDATE places
 		SET name = :name,
 			description = :description,
 			longitude = :longitude,
 			latitude = :latitude,
-		WHERE id = :id
-		RETURNING id
+			region_id = :region_id,
+			city_id = :city_id,
+			version = version + 1,
+			updated_at = NOW()
+		WHERE id = :id AND (:version = 0 OR version = :version) AND deleted_at IS NULL
+		RETURNING version, updated_at
 	`
 
-	result, err := r.db.NamedExec(query, place)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update place: %w", err)
+	err := getNamed(ctx, r.db, place, query, place)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, versionError(ctx, r.db, "places", place.ID, "failed to update place")
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return nil, fmt.Errorf("place not found")
+		return nil, dbError("failed to update place", err)
 	}
 
 	// if err := r.updatePhotos(place.ID, place.PhotoURLs); err != nil {
@@ -126,44 +138,132 @@ func (r *PlaceRepository) Update(place *entity.Place) (*entity.Place, error) {
 	return place, nil
 }
 
-func (r *PlaceRepository) Delete(id int) error {
-	tx, err := r.db.Beginx()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// Patch сохраняет поля, изменившиеся между original и updated; если изменился
+// список фото, он заменяется целиком. Если место успели изменить после чтения
+// original, возвращает ErrVersionMismatch.
+func (r *PlaceRepository) Patch(ctx context.Context, original, updated *entity.Place) error {
+	ctx = withOperation(ctx, "place", "Patch")
+	changes := changedColumns(placeColumns(original), placeColumns(updated))
+	photosChanged := !reflect.DeepEqual(original.PhotoURLs, updated.PhotoURLs)
+	if len(changes) == 0 && !photosChanged {
+		return nil
 	}
-	defer tx.Rollback()
 
-	if _, err := tx.Exec("DELETE FROM place_photos WHERE place_id = $1", id); err != nil {
-		return fmt.Errorf("failed to delete photos: %w", err)
+	return inTx(ctx, r.db, func(tx Executor) error {
+		if _, err := updateVersioned(ctx, tx, "places", original.ID, original.Version, changes); err != nil {
+			return err
+		}
+		if photosChanged {
+			return NewPhotoRepository(tx).Replace(ctx, original.ID, updated.PhotoURLs)
+		}
+		return nil
+	})
+}
+
+// placeColumns - значения колонок места, которые можно менять через PATCH
+func placeColumns(p *entity.Place) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        p.Name,
+		"description": p.Description,
+		"longitude":   p.Longitude,
+		"latitude":    p.Latitude,
+		"country_id":  p.CountryID,
+		"region_id":   p.RegionID,
+		"city_id":     p.CityID,
 	}
+}
 
-	result, err := tx.Exec("DELETE FROM places WHERE id = $1", id)
+// Delete переносит место в корзину, если его версия равна version (0 - любая).
+// Фото остаются до окончательной очистки.
+func (r *PlaceRepository) Delete(ctx context.Context, id int, version int64) error {
+	ctx = withOperation(ctx, "place", "Delete")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE places
+		SET deleted_at = NOW(), version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND ($2 = 0 OR version = $2) AND deleted_at IS NULL
+	`, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete place: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return versionError(ctx, r.db, "places", id, "failed to delete place")
+	}
+
+	return nil
+}
+
+// ListDeleted возвращает места из корзины, начиная с последних удаленных
+func (r *PlaceRepository) ListDeleted(ctx context.Context) ([]*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "ListDeleted")
+	places := []*entity.Place{}
+	query := `
+		SELECT *
+		FROM places
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &places, query); err != nil {
+		return nil, fmt.Errorf("failed to list deleted places: %w", err)
+	}
+	return places, nil
+}
+
+// Restore возвращает место из корзины. Если места нет в корзине, возвращает
+// ErrNotFound; если в корзине его страна - ErrReference.
+func (r *PlaceRepository) Restore(ctx context.Context, id int) error {
+	ctx = withOperation(ctx, "place", "Restore")
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE places AS p
+		SET deleted_at = NULL, version = p.version + 1, updated_at = NOW()
+		FROM countries c
+		WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+		  AND c.id = p.country_id AND c.deleted_at IS NULL
+	`, id)
 	if err != nil {
+		return fmt.Errorf("failed to restore place: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected > 0 {
+		return nil
+	}
+
+	var inTrash bool
+	if err := r.db.GetContext(ctx, &inTrash, "SELECT EXISTS(SELECT 1 FROM places WHERE id = $1 AND deleted_at IS NOT NULL)", id); err != nil {
+		return fmt.Errorf("failed to check place existence: %w", err)
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("place not found")
+	if !inTrash {
+		return fmt.Errorf("failed to restore place: %w", ErrNotFound)
 	}
+	return fmt.Errorf("failed to restore place: country is deleted: %w", ErrReference)
+}
 
-	return tx.Commit()
+// Purge окончательно удаляет места, находящиеся в корзине с момента до
+// before, вместе с их фото
+func (r *PlaceRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withOperation(ctx, "place", "Purge")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM places WHERE deleted_at < $1", dbTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge places: %w", err)
+	}
+	return result.RowsAffected()
 }
 
-func (r *PlaceRepository) GetPlacesByCountryID(countryID int) ([]*entity.Place, error) {
+func (r *PlaceRepository) GetPlacesByCountryID(ctx context.Context, countryID int) ([]*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "GetPlacesByCountryID")
 	var places []*entity.Place
 	query := `
         SELECT * 
         FROM places 
-        WHERE country_id = $1
+        WHERE country_id = $1 AND deleted_at IS NULL
     `
-	err := r.db.Select(&places, query, countryID)
+	err := r.db.SelectContext(ctx, &places, query, countryID)
 
 	for _, place := range places {
-		photoUrl, err := r.getPhotos(place.CountryID)
+		photoUrl, err := r.photos.ListURLs(ctx, place.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get photos for place %d: %w", place.ID, err)
 		}
@@ -172,27 +272,28 @@ func (r *PlaceRepository) GetPlacesByCountryID(countryID int) ([]*entity.Place,
 	return places, err
 }
 
-func (r *PlaceRepository) SearchByName(query string, limit int) ([]*entity.Place, error) {
+func (r *PlaceRepository) SearchByName(ctx context.Context, query string, limit int) ([]*entity.Place, error) {
+	ctx = withOperation(ctx, "place", "SearchByName")
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return []*entity.Place{}, nil
 	}
 
 	var places []*entity.Place
-	err := r.db.Select(&places, `
-		SELECT id, name, description, longitude, latitude, country_id
+	err := r.db.SelectContext(ctx, &places, fmt.Sprintf(`
+		SELECT id, name, description, longitude, latitude, country_id, region_id, city_id, version, updated_at
 		FROM places
-		WHERE name ILIKE '%' || $1 || '%'
+		WHERE %s AND deleted_at IS NULL
 		ORDER BY name
 		LIMIT $2
-	`, query, limit)
+	`, nameContains(r.db, "name", 1)), query, limit)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to search places: %w", err)
 	}
 
 	for _, place := range places {
-		photoUrl, err := r.getPhotos(place.CountryID)
+		photoUrl, err := r.photos.ListURLs(ctx, place.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get photos for place %d: %w", place.ID, err)
 		}
@@ -201,35 +302,3 @@ func (r *PlaceRepository) SearchByName(query string, limit int) ([]*entity.Place
 
 	return places, nil
 }
-
-// Фото мест
-func (r *PlaceRepository) addPhotos(placeID int, url string) error {
-	query := `
-		INSERT INTO place_photos (place_id, url)
-		VALUES ($1, $2)
-		RETURNING id
-	`
-	_, err := r.db.Exec(query, placeID, url)
-	return err
-}
-
-func (r *PlaceRepository) getPhotos(placeID int) ([]string, error) {
-	var photos []string
-	err := r.db.Select(&photos, "SELECT url FROM place_photos WHERE place_id = $1", placeID)
-	return photos, err
-}
-
-func (r *PlaceRepository) updatePhoto(photoID int, placeID int, url string) error {
-	query := `
-        UPDATE place_photos
-        SET url = $3
-        WHERE id = $1 AND place_id = $2
-    `
-	_, err := r.db.Exec(query, photoID, placeID, url)
-	return err
-}
-
-func (r *PlaceRepository) deletePhotos(id int) error {
-	_, err := r.db.Exec("DELETE FROM place_photos WHERE id = $1", id)
-	return err
-}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+// inLiveCountry - условие на регионы и города неудаленных стран. Пока страна
+// в корзине, они скрыты; восстановление страны возвращает их, очистка
+// корзины удаляет вместе с ней.
+const inLiveCountry = "country_id IN (SELECT id FROM countries WHERE deleted_at IS NULL)"
+
+type RegionRepository struct {
+	db Executor
+}
+
+func NewRegionRepository(db Executor) *RegionRepository {
+	return &RegionRepository{db: db}
+}
+
+func (r *RegionRepository) Create(ctx context.Context, region *entity.Region) (*entity.Region, error) {
+	ctx = withOperation(ctx, "region", "Create")
+	query := `
+		INSERT INTO regions (name, code, description, country_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.GetContext(ctx, &region.ID, query, region.Name, region.Code, region.Description, region.CountryID)
+	if err != nil {
+		return nil, dbError("failed to create region", err)
+	}
+
+	return region, nil
+}
+
+func (r *RegionRepository) GetByID(ctx context.Context, id int) (*entity.Region, error) {
+	ctx = withOperation(ctx, "region", "GetByID")
+	region := &entity.Region{}
+	err := r.db.GetContext(ctx, region, "SELECT * FROM regions WHERE id = $1 AND "+inLiveCountry, id)
+	if err != nil {
+		return nil, dbError("failed to get region", err)
+	}
+	return region, nil
+}
+
+// GetAll возвращает регионы; countryID = 0 - регионы всех стран
+func (r *RegionRepository) GetAll(ctx context.Context, countryID int) ([]entity.Region, error) {
+	ctx = withOperation(ctx, "region", "GetAll")
+	regions := []entity.Region{}
+	query := `
+		SELECT *
+		FROM regions
+		WHERE ($1 = 0 OR country_id = $1) AND ` + inLiveCountry + `
+		ORDER BY name
+	`
+	if err := r.db.SelectContext(ctx, &regions, query, countryID); err != nil {
+		return nil, fmt.Errorf("failed to get regions: %w", err)
+	}
+	return regions, nil
+}
+
+func (r *RegionRepository) Update(ctx context.Context, region *entity.Region) (*entity.Region, error) {
+	ctx = withOperation(ctx, "region", "Update")
+	query := `
+		UPDATE regions
+		SET name = :name,
+			code = :code,
+			description = :description,
+			country_id = :country_id
+		WHERE id = :id AND ` + inLiveCountry + `
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, region)
+	if err != nil {
+		return nil, dbError("failed to update region", err)
+	}
+
+	if err := checkAffected(result, "failed to update region"); err != nil {
+		return nil, err
+	}
+
+	return region, nil
+}
+
+func (r *RegionRepository) Delete(ctx context.Context, id int) error {
+	ctx = withOperation(ctx, "region", "Delete")
+	result, err := r.db.ExecContext(ctx, "DELETE FROM regions WHERE id = $1 AND "+inLiveCountry, id)
+	if err != nil {
+		return dbError("failed to delete region", err)
+	}
+
+	if err := checkAffected(result, "failed to delete region"); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type replicaReadsKey struct{}
+
+// WithReplicaReads помечает ctx как контекст операции, которая ничего не
+// пишет в БД: ее SELECT-запросы можно выполнять на репликах. Реплики
+// отстают от основной БД, поэтому без пометки (запись, чтение после записи,
+// фоновые задачи) все запросы идут в основную БД.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// WithPrimaryReads отменяет WithReplicaReads: все запросы из ctx идут в
+// основную БД
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, false)
+}
+
+func replicaReads(ctx context.Context) bool {
+	allowed, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return allowed
+}
+
+// routedExecutor выполняет запросы в основной БД (Executor), а SELECT-запросы
+// из контекстов WithReplicaReads - по очереди на репликах
+type routedExecutor struct {
+	Executor
+	replicas []*sqlx.DB
+	next     atomic.Uint64
+}
+
+// route возвращает primary, если реплик нет, иначе executor, который
+// направляет на них чтения
+func route(primary *sqlx.DB, replicas []*sqlx.DB) Executor {
+	if len(replicas) == 0 {
+		return primary
+	}
+	return &routedExecutor{Executor: primary, replicas: replicas}
+}
+
+// pick выбирает, где выполнить query. На реплики уходят только запросы,
+// начинающиеся с SELECT: INSERT ... RETURNING тоже читается через
+// GetContext, но должен выполняться в основной БД.
+func (e *routedExecutor) pick(ctx context.Context, query string) Executor {
+	if !replicaReads(ctx) || !isSelect(query) {
+		return e.Executor
+	}
+	n := e.next.Add(1)
+	return e.replicas[n%uint64(len(e.replicas))]
+}
+
+func isSelect(query string) bool {
+	query = strings.TrimSpace(query)
+	return len(query) >= len("SELECT") && strings.EqualFold(query[:len("SELECT")], "SELECT")
+}
+
+func (e *routedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return e.pick(ctx, query).QueryContext(ctx, query, args...)
+}
+
+func (e *routedExecutor) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return e.pick(ctx, query).QueryxContext(ctx, query, args...)
+}
+
+func (e *routedExecutor) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return e.pick(ctx, query).QueryRowxContext(ctx, query, args...)
+}
+
+func (e *routedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return e.pick(ctx, query).QueryRowContext(ctx, query, args...)
+}
+
+func (e *routedExecutor) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return e.pick(ctx, query).GetContext(ctx, dest, query, args...)
+}
+
+func (e *routedExecutor) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return e.pick(ctx, query).SelectContext(ctx, dest, query, args...)
+}
@@ -5,13 +5,59 @@ import (
 )
 
 type Repository struct {
-	CountryRepository *CountryRepository
-	PlaceRepository   *PlaceRepository
+	CountryRepository     *CountryRepository
+	PlaceRepository       *PlaceRepository
+	PhotoRepository       *PhotoRepository
+	EnrichmentRepository  *EnrichmentRepository
+	ContinentRepository   *ContinentRepository
+	RegionRepository      *RegionRepository
+	CityRepository        *CityRepository
+	RevisionRepository    *RevisionRepository
+	AuditRepository       *AuditRepository
+	IdempotencyRepository *IdempotencyRepository
+	StatsRepository       *StatsRepository
+	HealthRepository      *HealthRepository
+	// TxManager задан только у репозиториев, не привязанных к транзакции
+	TxManager *TxManager
 }
 
-func NewRepository(db *sqlx.DB) *Repository {
+// Options - необязательные настройки репозиториев
+type Options struct {
+	// Observer получает длительность и результат каждого запроса к БД
+	Observer QueryObserver
+	// Replicas - реплики для чтений из контекстов WithReplicaReads
+	Replicas []*sqlx.DB
+	// Retry - повтор чтений и транзакций после временных сбоев
+	Retry RetryPolicy
+	// Breaker - выключатель, прекращающий запросы к недоступной БД
+	Breaker *Breaker
+}
+
+// NewRepository создает репозитории поверх db
+func NewRepository(db *sqlx.DB, opts Options) *Repository {
+	ex := resilient(observe(route(db, opts.Replicas), opts.Observer), opts.Retry, opts.Breaker)
+	repo := newRepository(ex)
+	// Готовность сервиса определяется основной БД, проверки идут в обход
+	// выключателя
+	repo.HealthRepository = NewHealthRepository(observe(db, opts.Observer))
+	repo.HealthRepository.breaker = opts.Breaker
+	repo.TxManager = &TxManager{db: ex}
+	return repo
+}
+
+func newRepository(db Executor) *Repository {
 	return &Repository{
-		CountryRepository: NewCountryRepository(db),
-		PlaceRepository:   NewPlaceRepository(db),
+		CountryRepository:     NewCountryRepository(db),
+		PlaceRepository:       NewPlaceRepository(db),
+		PhotoRepository:       NewPhotoRepository(db),
+		EnrichmentRepository:  NewEnrichmentRepository(db),
+		ContinentRepository:   NewContinentRepository(db),
+		RegionRepository:      NewRegionRepository(db),
+		CityRepository:        NewCityRepository(db),
+		RevisionRepository:    NewRevisionRepository(db),
+		AuditRepository:       NewAuditRepository(db),
+		IdempotencyRepository: NewIdempotencyRepository(db),
+		StatsRepository:       NewStatsRepository(db),
+		HealthRepository:      NewHealthRepository(db),
 	}
 }
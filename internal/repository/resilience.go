@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// UnavailableError - БД недоступна: выключатель разомкнут после серии
+// сбоев (Err пуста) или запрос не прошел из-за сбоя соединения Err и после
+// повторов. RetryAfter - когда имеет смысл повторить запрос.
+type UnavailableError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *UnavailableError) Error() string {
+	msg := fmt.Sprintf("database is unavailable, retry in %ds", retryAfterSeconds(e.RetryAfter))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfterSeconds округляет d до целых секунд вверх, не меньше 1
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
+
+// Коды ошибок PostgreSQL, после которых запрос можно повторить
+const (
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+	pqTooManyConnections   = "53300"
+	pqAdminShutdown        = "57P01"
+	pqCrashShutdown        = "57P02"
+	pqCannotConnectNow     = "57P03"
+	// pqConnectionException - класс ошибок соединения 08xxx
+	pqConnectionException = "08"
+)
+
+// isTransient - ошибка соединения с БД: сервер недоступен, перезапускается
+// или переключается на реплику. Такие ошибки размыкают выключатель.
+func isTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqTooManyConnections, pqAdminShutdown, pqCrashShutdown, pqCannotConnectNow:
+			return true
+		}
+		return string(pqErr.Code.Class()) == pqConnectionException
+	}
+	return false
+}
+
+// isSerializationFailure - транзакция откачена из-за конкурентной
+// транзакции; повторная попытка обычно проходит
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected)
+}
+
+// RetryPolicy - повтор запросов после временных сбоев. MaxAttempts - число
+// попыток вместе с первой; 0 и 1 - без повторов. Паузы между попытками
+// растут вдвое от BaseDelay до MaxDelay, из них случайна вторая половина,
+// чтобы экземпляры сервиса не повторяли запросы одновременно.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// delay возвращает паузу перед попыткой attempt+1
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << min(attempt-1, 30)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// Breaker - выключатель: после threshold сбоев соединения подряд запросы к
+// БД в течение cooldown сразу завершаются UnavailableError, не дожидаясь
+// таймаутов. Затем пропускается один пробный запрос: если он прошел,
+// выключатель замыкается, иначе снова размыкается на cooldown.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	onChange  func(state string)
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker создает замкнутый выключатель. onChange, если задана,
+// вызывается при смене состояния (entity.Breaker*).
+func NewBreaker(threshold int, cooldown time.Duration, onChange func(state string)) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown, onChange: onChange, state: entity.BreakerClosed}
+}
+
+// allow разрешает запрос или возвращает UnavailableError
+func (b *Breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case entity.BreakerOpen:
+		if wait := b.retryAfter(); wait > 0 {
+			return &UnavailableError{RetryAfter: wait}
+		}
+		b.setState(entity.BreakerHalfOpen)
+		fallthrough
+	case entity.BreakerHalfOpen:
+		if b.probing {
+			return &UnavailableError{RetryAfter: time.Second}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record учитывает результат разрешенного запроса. Любой ответ БД, даже
+// ошибка в данных, означает, что она доступна; отмена запроса клиентом не
+// говорит о БД ничего.
+func (b *Breaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case ctx.Err() != nil:
+		b.probing = false
+	case err != nil && isTransient(err):
+		b.failures++
+		b.probing = false
+		if b.state == entity.BreakerHalfOpen || (b.state == entity.BreakerClosed && b.failures >= b.threshold) {
+			b.openedAt = time.Now()
+			b.setState(entity.BreakerOpen)
+		}
+	default:
+		b.failures = 0
+		b.probing = false
+		b.setState(entity.BreakerClosed)
+	}
+}
+
+func (b *Breaker) setState(state string) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
+
+// wait возвращает, через сколько повторить запрос после сбоя соединения
+func (b *Breaker) wait() time.Duration {
+	if b == nil {
+		return time.Second
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.retryAfter()
+}
+
+// Status возвращает состояние выключателя для проверок готовности
+func (b *Breaker) Status() *entity.BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := &entity.BreakerStatus{State: b.state, Failures: b.failures}
+	if b.state == entity.BreakerOpen {
+		status.RetryAfterSeconds = retryAfterSeconds(b.retryAfter())
+	}
+	return status
+}
+
+// retryAfter - время до пробного запроса; вызывается под b.mu
+func (b *Breaker) retryAfter() time.Duration {
+	if b.state != entity.BreakerOpen {
+		return time.Second
+	}
+	return b.cooldown - time.Since(b.openedAt)
+}
+
+// resilientExecutor пропускает запросы через выключатель и повторяет
+// идемпотентные чтения (SELECT вне транзакции) после сбоев соединения.
+// Записи не повторяются: после обрыва соединения неизвестно, применились
+// ли они. QueryRowContext и QueryRowxContext выполняются напрямую: ошибку
+// выключателя нельзя вернуть в *sql.Row, поэтому вне транзакций репозитории
+// читают строку через GetContext.
+type resilientExecutor struct {
+	Executor
+	retry   RetryPolicy
+	breaker *Breaker
+}
+
+// resilient оборачивает ex; без повторов и выключателя возвращает ex как есть
+func resilient(ex Executor, retry RetryPolicy, breaker *Breaker) Executor {
+	if retry.MaxAttempts <= 1 && breaker == nil {
+		return ex
+	}
+	return &resilientExecutor{Executor: ex, retry: retry, breaker: breaker}
+}
+
+// do выполняет op, повторяя ее, пока retryable(err) и попытки не исчерпаны
+func (e *resilientExecutor) do(ctx context.Context, retryable func(error) bool, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := e.breaker.allow(); err != nil {
+			return err
+		}
+		err := op()
+		e.breaker.record(ctx, err)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if attempt >= e.retry.MaxAttempts || !retryable(err) {
+			if isTransient(err) {
+				return &UnavailableError{RetryAfter: e.breaker.wait(), Err: err}
+			}
+			return err
+		}
+
+		timer := time.NewTimer(e.retry.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// read выполняет запрос query, повторяя его, только если это SELECT
+func (e *resilientExecutor) read(ctx context.Context, query string, op func() error) error {
+	return e.do(ctx, func(err error) bool {
+		return isSelect(query) && (isTransient(err) || isSerializationFailure(err))
+	}, op)
+}
+
+// write выполняет запрос без повторов
+func (e *resilientExecutor) write(ctx context.Context, op func() error) error {
+	return e.do(ctx, func(error) bool { return false }, op)
+}
+
+// inTx выполняет fn в новой транзакции и повторяет всю транзакцию, если
+// PostgreSQL откатил ее из-за конкурентной транзакции
+func (e *resilientExecutor) inTx(ctx context.Context, fn func(tx Executor) error) error {
+	return e.do(ctx, isSerializationFailure, func() error {
+		return inTx(ctx, e.Executor, fn)
+	})
+}
+
+func (e *resilientExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := e.write(ctx, func() (err error) {
+		result, err = e.Executor.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (e *resilientExecutor) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := e.write(ctx, func() (err error) {
+		result, err = e.Executor.NamedExecContext(ctx, query, arg)
+		return err
+	})
+	return result, err
+}
+
+func (e *resilientExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := e.read(ctx, query, func() (err error) {
+		rows, err = e.Executor.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (e *resilientExecutor) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := e.read(ctx, query, func() (err error) {
+		rows, err = e.Executor.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (e *resilientExecutor) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return e.read(ctx, query, func() error {
+		return e.Executor.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (e *resilientExecutor) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return e.read(ctx, query, func() error {
+		return e.Executor.SelectContext(ctx, dest, query, args...)
+	})
+}
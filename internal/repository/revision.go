@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type RevisionRepository struct {
+	db Executor
+}
+
+func NewRevisionRepository(db Executor) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// Create сохраняет ревизию со следующим по порядку номером для сущности
+func (r *RevisionRepository) Create(ctx context.Context, rev *entity.Revision) (*entity.Revision, error) {
+	ctx = withOperation(ctx, "revision", "Create")
+	query := `
+		INSERT INTO revisions (entity_type, entity_id, revision, action, actor, snapshot)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, $5
+		FROM revisions
+		WHERE entity_type = $1 AND entity_id = $2
+		RETURNING id, revision, created_at
+	`
+
+	err := r.db.GetContext(ctx, rev, query, rev.EntityType, rev.EntityID, rev.Action, rev.Actor, jsonArg(r.db, rev.Snapshot))
+	if err != nil {
+		return nil, dbError("failed to create revision", err)
+	}
+	return rev, nil
+}
+
+// List возвращает ревизии сущности, начиная с последней
+func (r *RevisionRepository) List(ctx context.Context, entityType string, entityID int) ([]entity.Revision, error) {
+	ctx = withOperation(ctx, "revision", "List")
+	revisions := []entity.Revision{}
+	query := `
+		SELECT *
+		FROM revisions
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY revision DESC
+	`
+	if err := r.db.SelectContext(ctx, &revisions, query, entityType, entityID); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *RevisionRepository) Get(ctx context.Context, entityType string, entityID, revision int) (*entity.Revision, error) {
+	ctx = withOperation(ctx, "revision", "Get")
+	rev := &entity.Revision{}
+	query := `
+		SELECT *
+		FROM revisions
+		WHERE entity_type = $1 AND entity_id = $2 AND revision = $3
+	`
+	if err := r.db.GetContext(ctx, rev, query, entityType, entityID, revision); err != nil {
+		return nil, dbError("failed to get revision", err)
+	}
+	return rev, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+type StatsRepository struct {
+	db Executor
+}
+
+func NewStatsRepository(db Executor) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Get считает страны, места и фото мест, не находящихся в корзине
+func (r *StatsRepository) Get(ctx context.Context) (*entity.Stats, error) {
+	ctx = withOperation(ctx, "stats", "Get")
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM countries WHERE deleted_at IS NULL) AS countries,
+			(SELECT COUNT(*) FROM places WHERE deleted_at IS NULL) AS places,
+			(SELECT COUNT(*) FROM place_photos ph JOIN places p ON p.id = ph.place_id WHERE p.deleted_at IS NULL) AS photos
+	`
+
+	stats := &entity.Stats{}
+	if err := r.db.GetContext(ctx, stats, query); err != nil {
+		return nil, dbError("failed to get stats", err)
+	}
+	return stats, nil
+}
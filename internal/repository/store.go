@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+)
+
+// CountryStore - хранилище стран. Кроме CountryRepository (PostgreSQL) его
+// реализует memory.CountryRepository; одинаковое поведение реализаций
+// проверяет storetest.
+type CountryStore interface {
+	GetCountries(ctx context.Context) ([]entity.Country, error)
+	GetCountryByID(ctx context.Context, id int) (entity.Country, error)
+	GetCountryByCode(ctx context.Context, code string) (entity.Country, error)
+	GetCountriesByContinent(ctx context.Context, code string) ([]entity.Country, error)
+	SearchByName(ctx context.Context, query string, limit int) ([]entity.Country, error)
+	AddCountry(ctx context.Context, country *entity.Country) (int, error)
+	UpdateCountry(ctx context.Context, country *entity.Country) (*entity.Country, error)
+	PatchCountry(ctx context.Context, original, updated *entity.Country) error
+	UpdateField(ctx context.Context, countryID int, field string, value interface{}) error
+	DeleteCountry(ctx context.Context, id int, version int64, cascade bool) ([]int, error)
+	DeletePreview(ctx context.Context, id int) (*entity.CountryDeletePreview, error)
+	ListDeleted(ctx context.Context) ([]entity.Country, error)
+	Restore(ctx context.Context, id int) ([]int, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PlaceStore - хранилище мест вместе с их фото
+type PlaceStore interface {
+	Create(ctx context.Context, place *entity.Place) (*entity.Place, error)
+	GetByID(ctx context.Context, id int) (*entity.Place, error)
+	GetAll(ctx context.Context, filter entity.PlaceFilter) ([]*entity.Place, error)
+	GetPlacesByCountryID(ctx context.Context, countryID int) ([]*entity.Place, error)
+	SearchByName(ctx context.Context, query string, limit int) ([]*entity.Place, error)
+	Update(ctx context.Context, place *entity.Place) (*entity.Place, error)
+	Patch(ctx context.Context, original, updated *entity.Place) error
+	Delete(ctx context.Context, id int, version int64) error
+	ListDeleted(ctx context.Context) ([]*entity.Place, error)
+	Restore(ctx context.Context, id int) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RevisionStore - история изменений стран и мест
+type RevisionStore interface {
+	Create(ctx context.Context, rev *entity.Revision) (*entity.Revision, error)
+	List(ctx context.Context, entityType string, entityID int) ([]entity.Revision, error)
+	Get(ctx context.Context, entityType string, entityID, revision int) (*entity.Revision, error)
+}
+
+// Stores - хранилища, привязанные к одной транзакции. Continents, Regions,
+// Cities и Enrichment есть только у TxManager; у реализаций без БД они nil.
+type Stores struct {
+	Countries  CountryStore
+	Places     PlaceStore
+	Revisions  RevisionStore
+	Continents *ContinentRepository
+	Regions    *RegionRepository
+	Cities     *CityRepository
+	Enrichment *EnrichmentRepository
+}
+
+// Stores возвращает хранилища r
+func (r *Repository) Stores() Stores {
+	return Stores{
+		Countries:  r.CountryRepository,
+		Places:     r.PlaceRepository,
+		Revisions:  r.RevisionRepository,
+		Continents: r.ContinentRepository,
+		Regions:    r.RegionRepository,
+		Cities:     r.CityRepository,
+		Enrichment: r.EnrichmentRepository,
+	}
+}
+
+// TxRunner выполняет fn в транзакции; fn получает хранилища, привязанные к
+// ней. Реализации - TxManager и memory.TxRunner.
+type TxRunner interface {
+	InTx(ctx context.Context, fn func(tx Stores) error) error
+}
+
+var (
+	_ CountryStore  = (*CountryRepository)(nil)
+	_ PlaceStore    = (*PlaceRepository)(nil)
+	_ RevisionStore = (*RevisionRepository)(nil)
+	_ TxRunner      = (*TxManager)(nil)
+)
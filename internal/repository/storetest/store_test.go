@@ -0,0 +1,23 @@
+package storetest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ShekleinAleksey/top-places/internal/repository/storetest"
+)
+
+func TestMemory(t *testing.T) {
+	storetest.Run(t, storetest.Memory)
+}
+
+func TestSQLite(t *testing.T) {
+	storetest.Run(t, storetest.SQLite)
+}
+
+func TestPostgres(t *testing.T) {
+	if os.Getenv(storetest.DatabaseURLEnv) == "" {
+		t.Skipf("%s is not set", storetest.DatabaseURLEnv)
+	}
+	storetest.Run(t, storetest.Postgres)
+}
@@ -0,0 +1,601 @@
+// Package storetest - общий набор проверок для реализаций
+// repository.CountryStore и repository.PlaceStore. Тесты вызывают Run для
+// каждой реализации:
+//
+//	storetest.Run(t, storetest.Memory)
+//	storetest.Run(t, storetest.SQLite)
+//	storetest.Run(t, storetest.Postgres)
+//
+// SQLite создает новую БД во временном каталоге теста. Postgres подключается
+// к TEST_DATABASE_URL с примененными миграциями и очищает таблицы стран и
+// мест; без переменной проверки пропускаются.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/internal/repository/memory"
+	"github.com/ShekleinAleksey/top-places/migrations"
+	"github.com/ShekleinAleksey/top-places/pkg/sqlite"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// DatabaseURLEnv - переменная окружения с адресом тестовой БД
+const DatabaseURLEnv = "TEST_DATABASE_URL"
+
+// Stores - проверяемая пара хранилищ над общими данными
+type Stores struct {
+	Countries repository.CountryStore
+	Places    repository.PlaceStore
+}
+
+// Memory возвращает пустые хранилища в памяти
+func Memory(t *testing.T) Stores {
+	db := memory.NewDB()
+	return Stores{
+		Countries: memory.NewCountryRepository(db),
+		Places:    memory.NewPlaceRepository(db),
+	}
+}
+
+// SQLite возвращает репозитории над новой БД SQLite со всеми миграциями
+func SQLite(t *testing.T) Stores {
+	db, err := sqlite.NewDB(sqlite.Config{Path: filepath.Join(t.TempDir(), "store.db")})
+	if err != nil {
+		t.Fatalf("open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := sqlite.Migrate(context.Background(), db, migrations.SQLite()); err != nil {
+		t.Fatalf("migrate sqlite database: %v", err)
+	}
+
+	repo := repository.NewRepository(db, repository.Options{})
+	return Stores{Countries: repo.CountryRepository, Places: repo.PlaceRepository}
+}
+
+// Postgres возвращает репозитории PostgreSQL над очищенной тестовой БД
+func Postgres(t *testing.T) Stores {
+	url := os.Getenv(DatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", DatabaseURLEnv)
+	}
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec("TRUNCATE countries, places, place_photos, country_languages, country_currencies RESTART IDENTITY CASCADE"); err != nil {
+		t.Fatalf("clean test database: %v", err)
+	}
+
+	repo := repository.NewRepository(db, repository.Options{})
+	return Stores{Countries: repo.CountryRepository, Places: repo.PlaceRepository}
+}
+
+// Run проверяет хранилища, которые возвращает open; для каждого подтеста
+// open вызывается заново и должна вернуть пустые хранилища
+func Run(t *testing.T, open func(t *testing.T) Stores) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CountryCreateAndGet", testCountryCreateAndGet},
+		{"CountryNotFound", testCountryNotFound},
+		{"CountryISOConflict", testCountryISOConflict},
+		{"CountryVersions", testCountryVersions},
+		{"CountrySearch", testCountrySearch},
+		{"CountryDeleteInUse", testCountryDeleteInUse},
+		{"CountryCascadeAndRestore", testCountryCascadeAndRestore},
+		{"CountryPurge", testCountryPurge},
+		{"PlaceCreateAndGet", testPlaceCreateAndGet},
+		{"PlaceMissingCountry", testPlaceMissingCountry},
+		{"PlaceVersions", testPlaceVersions},
+		{"PlaceFilterAndSearch", testPlaceFilterAndSearch},
+		{"PlaceTrash", testPlaceTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, open(t))
+		})
+	}
+}
+
+func testCountryCreateAndGet(t *testing.T, s Stores) {
+	ctx := context.Background()
+	population := int64(3700000)
+	country := &entity.Country{
+		Name:          "Georgia",
+		Capital:       "Tbilisi",
+		Population:    &population,
+		ISO2:          "GE",
+		ISO3:          "GEO",
+		ContinentCode: "AS",
+		Languages:     []string{"ka", "ab"},
+		Currencies:    []string{"GEL"},
+	}
+	id := addCountry(t, s, country)
+	if country.Version != 1 {
+		t.Errorf("version after create = %d, want 1", country.Version)
+	}
+
+	got, err := s.Countries.GetCountryByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetCountryByID: %v", err)
+	}
+	if got.Name != "Georgia" || got.Capital != "Tbilisi" || got.Population == nil || *got.Population != population {
+		t.Errorf("GetCountryByID = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Languages, []string{"ab", "ka"}) || !reflect.DeepEqual(got.Currencies, []string{"GEL"}) {
+		t.Errorf("codes = %v %v, want sorted languages and currencies", got.Languages, got.Currencies)
+	}
+
+	for _, code := range []string{"ge", "GEO"} {
+		byCode, err := s.Countries.GetCountryByCode(ctx, code)
+		if err != nil || byCode.ID != id {
+			t.Errorf("GetCountryByCode(%q) = %d, %v; want %d", code, byCode.ID, err, id)
+		}
+	}
+
+	byContinent, err := s.Countries.GetCountriesByContinent(ctx, "AS")
+	if err != nil || len(byContinent) != 1 || byContinent[0].ID != id {
+		t.Errorf("GetCountriesByContinent = %v, %v", byContinent, err)
+	}
+}
+
+func testCountryNotFound(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if _, err := s.Countries.GetCountryByID(ctx, 404); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetCountryByID = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Countries.GetCountryByCode(ctx, "ZZ"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetCountryByCode = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Countries.UpdateCountry(ctx, &entity.Country{ID: 404, Name: "X", Capital: "X"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateCountry = %v, want ErrNotFound", err)
+	}
+	if err := s.Countries.UpdateField(ctx, 404, entity.CountryFieldCapital, "X"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateField = %v, want ErrNotFound", err)
+	}
+}
+
+func testCountryISOConflict(t *testing.T, s Stores) {
+	ctx := context.Background()
+	addCountry(t, s, &entity.Country{Name: "France", Capital: "Paris", ISO2: "FR", ISO3: "FRA"})
+
+	_, err := s.Countries.AddCountry(ctx, &entity.Country{Name: "Duplicate", Capital: "X", ISO2: "FR"})
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("AddCountry with taken iso2 = %v, want ErrConflict", err)
+	}
+	// Пустые коды уникальными не считаются
+	addCountry(t, s, &entity.Country{Name: "A", Capital: "A"})
+	addCountry(t, s, &entity.Country{Name: "B", Capital: "B"})
+}
+
+func testCountryVersions(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := addCountry(t, s, &entity.Country{Name: "Italy", Capital: "Rome"})
+
+	stale := &entity.Country{ID: id, Name: "Italy", Capital: "Rome", Version: 7}
+	if _, err := s.Countries.UpdateCountry(ctx, stale); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("UpdateCountry with stale version = %v, want ErrVersionMismatch", err)
+	}
+
+	updated, err := s.Countries.UpdateCountry(ctx, &entity.Country{ID: id, Name: "Italy", Capital: "Roma", PhotoURL: "colosseum.jpg", Version: 1})
+	if err != nil {
+		t.Fatalf("UpdateCountry: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("version after update = %d, want 2", updated.Version)
+	}
+	if got := getCountry(t, s, id); got.PhotoURL != "colosseum.jpg" {
+		t.Errorf("photo after update = %q, want colosseum.jpg", got.PhotoURL)
+	}
+
+	original := getCountry(t, s, id)
+	patched := original
+	patched.Description = "Boot-shaped"
+	original.Version = 1
+	if err := s.Countries.PatchCountry(ctx, &original, &patched); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("PatchCountry with stale version = %v, want ErrVersionMismatch", err)
+	}
+	original.Version = 2
+	if err := s.Countries.PatchCountry(ctx, &original, &patched); err != nil {
+		t.Fatalf("PatchCountry: %v", err)
+	}
+	if got := getCountry(t, s, id); got.Description != "Boot-shaped" || got.Capital != "Roma" || got.Version != 3 {
+		t.Errorf("after patch = %+v", got)
+	}
+
+	// Патч без изменений версию не меняет
+	same := getCountry(t, s, id)
+	if err := s.Countries.PatchCountry(ctx, &same, &same); err != nil {
+		t.Fatalf("empty PatchCountry: %v", err)
+	}
+	if got := getCountry(t, s, id); got.Version != 3 {
+		t.Errorf("version after empty patch = %d, want 3", got.Version)
+	}
+
+	if err := s.Countries.UpdateField(ctx, id, entity.CountryFieldPopulation, int64(59000000)); err != nil {
+		t.Fatalf("UpdateField: %v", err)
+	}
+	if got := getCountry(t, s, id); got.Population == nil || *got.Population != 59000000 || got.Version != 4 {
+		t.Errorf("after UpdateField = %+v", got)
+	}
+}
+
+func testCountrySearch(t *testing.T, s Stores) {
+	ctx := context.Background()
+	for _, name := range []string{"Saint Lucia", "Malta", "Saint Kitts and Nevis"} {
+		addCountry(t, s, &entity.Country{Name: name, Capital: "X"})
+	}
+
+	found, err := s.Countries.SearchByName(ctx, "  saint ", 10)
+	if err != nil {
+		t.Fatalf("SearchByName: %v", err)
+	}
+	if names := countryNames(found); !reflect.DeepEqual(names, []string{"Saint Kitts and Nevis", "Saint Lucia"}) {
+		t.Errorf("SearchByName = %v", names)
+	}
+
+	if found, _ := s.Countries.SearchByName(ctx, "saint", 1); len(found) != 1 {
+		t.Errorf("SearchByName with limit 1 returned %d countries", len(found))
+	}
+	if found, _ := s.Countries.SearchByName(ctx, " ", 10); len(found) != 0 {
+		t.Errorf("SearchByName with empty query returned %d countries", len(found))
+	}
+}
+
+func testCountryDeleteInUse(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := addCountry(t, s, &entity.Country{Name: "Spain", Capital: "Madrid"})
+	createPlace(t, s, &entity.Place{Name: "Alhambra", CountryID: id})
+
+	if _, err := s.Countries.DeleteCountry(ctx, id, 0, false); !errors.Is(err, repository.ErrInUse) {
+		t.Fatalf("DeleteCountry without cascade = %v, want ErrInUse", err)
+	}
+	if _, err := s.Countries.GetCountryByID(ctx, id); err != nil {
+		t.Errorf("country is gone after refused delete: %v", err)
+	}
+	if _, err := s.Countries.DeleteCountry(ctx, id, 5, true); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("DeleteCountry with stale version = %v, want ErrVersionMismatch", err)
+	}
+}
+
+func testCountryCascadeAndRestore(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := addCountry(t, s, &entity.Country{Name: "Peru", Capital: "Lima", ISO2: "PE"})
+	first := createPlace(t, s, &entity.Place{Name: "Machu Picchu", CountryID: id, PhotoURLs: []string{"a.jpg", "b.jpg"}})
+	second := createPlace(t, s, &entity.Place{Name: "Nazca Lines", CountryID: id})
+	// Место, удаленное раньше страны, при ее восстановлении остается в корзине
+	early := createPlace(t, s, &entity.Place{Name: "Lake Titicaca", CountryID: id})
+	if err := s.Places.Delete(ctx, early.ID, 0); err != nil {
+		t.Fatalf("Delete place: %v", err)
+	}
+
+	preview, err := s.Countries.DeletePreview(ctx, id)
+	if err != nil {
+		t.Fatalf("DeletePreview: %v", err)
+	}
+	if len(preview.Places) != 2 || preview.Places[0].ID != first.ID || preview.Photos != 2 {
+		t.Errorf("DeletePreview = %+v", preview)
+	}
+
+	placeIDs, err := s.Countries.DeleteCountry(ctx, id, 1, true)
+	if err != nil {
+		t.Fatalf("DeleteCountry: %v", err)
+	}
+	if !sameIDs(placeIDs, []int{first.ID, second.ID}) {
+		t.Errorf("DeleteCountry returned places %v", placeIDs)
+	}
+	if _, err := s.Countries.GetCountryByID(ctx, id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("deleted country: %v, want ErrNotFound", err)
+	}
+	if _, err := s.Places.GetByID(ctx, first.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("place of deleted country: %v, want ErrNotFound", err)
+	}
+	if deleted, _ := s.Countries.ListDeleted(ctx); len(deleted) != 1 || deleted[0].ID != id || deleted[0].DeletedAt == nil {
+		t.Errorf("ListDeleted = %+v", deleted)
+	}
+
+	// Коды удаленной страны свободны, пока ее не восстановили
+	takenID := addCountry(t, s, &entity.Country{Name: "Peru again", Capital: "Lima", ISO2: "PE"})
+	if _, err := s.Countries.Restore(ctx, id); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Restore with taken iso2 = %v, want ErrConflict", err)
+	}
+	if _, err := s.Countries.DeleteCountry(ctx, takenID, 0, false); err != nil {
+		t.Fatalf("DeleteCountry: %v", err)
+	}
+
+	restored, err := s.Countries.Restore(ctx, id)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if !sameIDs(restored, []int{first.ID, second.ID}) {
+		t.Errorf("Restore returned places %v", restored)
+	}
+	if got := getCountry(t, s, id); got.Version != 3 {
+		t.Errorf("version after delete and restore = %d, want 3", got.Version)
+	}
+	place, err := s.Places.GetByID(ctx, first.ID)
+	if err != nil || !reflect.DeepEqual(place.PhotoURLs, []string{"a.jpg", "b.jpg"}) {
+		t.Errorf("restored place = %+v, %v", place, err)
+	}
+	if _, err := s.Places.GetByID(ctx, early.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("place deleted before the country: %v, want ErrNotFound", err)
+	}
+	if _, err := s.Countries.Restore(ctx, id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore of active country = %v, want ErrNotFound", err)
+	}
+}
+
+func testCountryPurge(t *testing.T, s Stores) {
+	ctx := context.Background()
+	kept := addCountry(t, s, &entity.Country{Name: "Kept", Capital: "X"})
+	purged := addCountry(t, s, &entity.Country{Name: "Purged", Capital: "X"})
+	place := createPlace(t, s, &entity.Place{Name: "Gone", CountryID: purged})
+	if _, err := s.Countries.DeleteCountry(ctx, purged, 0, true); err != nil {
+		t.Fatalf("DeleteCountry: %v", err)
+	}
+
+	if n, err := s.Countries.Purge(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("Purge before deletion = %d, %v; want 0", n, err)
+	}
+	if n, err := s.Countries.Purge(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("Purge = %d, %v; want 1", n, err)
+	}
+	if deleted, _ := s.Places.ListDeleted(ctx); len(deleted) != 0 {
+		t.Errorf("place %d survived country purge", place.ID)
+	}
+	if _, err := s.Countries.Restore(ctx, purged); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore of purged country = %v, want ErrNotFound", err)
+	}
+	getCountry(t, s, kept)
+}
+
+func testPlaceCreateAndGet(t *testing.T, s Stores) {
+	ctx := context.Background()
+	countryID := addCountry(t, s, &entity.Country{Name: "Japan", Capital: "Tokyo"})
+	lat, lon := 35.3606, 138.7274
+	place := createPlace(t, s, &entity.Place{
+		Name:      "Mount Fuji",
+		CountryID: countryID,
+		Latitude:  &lat,
+		Longitude: &lon,
+		PhotoURLs: []string{"fuji.jpg"},
+	})
+	if place.ID == 0 || place.Version != 1 {
+		t.Errorf("created place = %+v", place)
+	}
+
+	got, err := s.Places.GetByID(ctx, place.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Name != "Mount Fuji" || got.CountryID != countryID || got.Latitude == nil || *got.Latitude != lat {
+		t.Errorf("GetByID = %+v", got)
+	}
+	if !reflect.DeepEqual(got.PhotoURLs, []string{"fuji.jpg"}) {
+		t.Errorf("photos = %v", got.PhotoURLs)
+	}
+
+	byCountry, err := s.Places.GetPlacesByCountryID(ctx, countryID)
+	if err != nil || len(byCountry) != 1 || byCountry[0].ID != place.ID {
+		t.Errorf("GetPlacesByCountryID = %v, %v", byCountry, err)
+	}
+	if _, err := s.Places.GetByID(ctx, 404); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID = %v, want ErrNotFound", err)
+	}
+}
+
+func testPlaceMissingCountry(t *testing.T, s Stores) {
+	_, err := s.Places.Create(context.Background(), &entity.Place{Name: "Nowhere", CountryID: 404})
+	if !errors.Is(err, repository.ErrReference) {
+		t.Errorf("Create with missing country = %v, want ErrReference", err)
+	}
+}
+
+func testPlaceVersions(t *testing.T, s Stores) {
+	ctx := context.Background()
+	countryID := addCountry(t, s, &entity.Country{Name: "Egypt", Capital: "Cairo"})
+	otherID := addCountry(t, s, &entity.Country{Name: "Sudan", Capital: "Khartoum"})
+	place := createPlace(t, s, &entity.Place{Name: "Pyramids", CountryID: countryID, PhotoURLs: []string{"1.jpg"}})
+
+	stale := &entity.Place{ID: place.ID, Name: "Pyramids of Giza", CountryID: countryID, Version: 9}
+	if _, err := s.Places.Update(ctx, stale); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Update with stale version = %v, want ErrVersionMismatch", err)
+	}
+	// PUT не меняет страну и фото
+	updated, err := s.Places.Update(ctx, &entity.Place{ID: place.ID, Name: "Pyramids of Giza", CountryID: otherID, Version: 1})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("version after update = %d, want 2", updated.Version)
+	}
+	got := getPlace(t, s, place.ID)
+	if got.Name != "Pyramids of Giza" || got.CountryID != countryID || !reflect.DeepEqual(got.PhotoURLs, []string{"1.jpg"}) {
+		t.Errorf("after update = %+v", got)
+	}
+
+	// PATCH переносит место в другую страну и заменяет фото целиком
+	patched := *got
+	patched.CountryID = otherID
+	patched.PhotoURLs = []string{"2.jpg", "3.jpg"}
+	if err := s.Places.Patch(ctx, got, &patched); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	got = getPlace(t, s, place.ID)
+	if got.CountryID != otherID || !reflect.DeepEqual(got.PhotoURLs, []string{"2.jpg", "3.jpg"}) || got.Version != 3 {
+		t.Errorf("after patch = %+v", got)
+	}
+
+	staleOriginal := *got
+	staleOriginal.Version = 2
+	patched = *got
+	patched.Description = "stale"
+	if err := s.Places.Patch(ctx, &staleOriginal, &patched); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Patch with stale version = %v, want ErrVersionMismatch", err)
+	}
+	if err := s.Places.Delete(ctx, place.ID, 2); !errors.Is(err, repository.ErrVersionMismatch) {
+		t.Errorf("Delete with stale version = %v, want ErrVersionMismatch", err)
+	}
+}
+
+func testPlaceFilterAndSearch(t *testing.T, s Stores) {
+	ctx := context.Background()
+	italy := addCountry(t, s, &entity.Country{Name: "Italy", Capital: "Rome", ContinentCode: "EU"})
+	chile := addCountry(t, s, &entity.Country{Name: "Chile", Capital: "Santiago", ContinentCode: "SA"})
+	colosseum := createPlace(t, s, &entity.Place{Name: "Colosseum", CountryID: italy})
+	createPlace(t, s, &entity.Place{Name: "Easter Island", CountryID: chile})
+	createPlace(t, s, &entity.Place{Name: "Atacama Desert", CountryID: chile})
+
+	all, err := s.Places.GetAll(ctx, entity.PlaceFilter{})
+	if err != nil || len(all) != 3 || all[0].ID != colosseum.ID {
+		t.Errorf("GetAll = %v, %v; want 3 places ordered by ID", placeNames(all), err)
+	}
+	if eu, _ := s.Places.GetAll(ctx, entity.PlaceFilter{ContinentCode: "EU"}); !reflect.DeepEqual(placeNames(eu), []string{"Colosseum"}) {
+		t.Errorf("GetAll by continent = %v", placeNames(eu))
+	}
+	if inChile, _ := s.Places.GetAll(ctx, entity.PlaceFilter{CountryID: chile}); len(inChile) != 2 {
+		t.Errorf("GetAll by country = %v", placeNames(inChile))
+	}
+
+	found, err := s.Places.SearchByName(ctx, "A", 10)
+	if err != nil {
+		t.Fatalf("SearchByName: %v", err)
+	}
+	if names := placeNames(found); !reflect.DeepEqual(names, []string{"Atacama Desert", "Easter Island"}) {
+		t.Errorf("SearchByName = %v", names)
+	}
+	if found, _ := s.Places.SearchByName(ctx, "a", 1); len(found) != 1 {
+		t.Errorf("SearchByName with limit 1 returned %d places", len(found))
+	}
+}
+
+func testPlaceTrash(t *testing.T, s Stores) {
+	ctx := context.Background()
+	countryID := addCountry(t, s, &entity.Country{Name: "Greece", Capital: "Athens"})
+	place := createPlace(t, s, &entity.Place{Name: "Acropolis", CountryID: countryID})
+
+	if err := s.Places.Restore(ctx, place.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore of active place = %v, want ErrNotFound", err)
+	}
+	if err := s.Places.Delete(ctx, place.ID, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Places.Delete(ctx, place.ID, 0); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if deleted, _ := s.Places.ListDeleted(ctx); len(deleted) != 1 || deleted[0].ID != place.ID {
+		t.Errorf("ListDeleted = %v", placeNames(deleted))
+	}
+
+	// Место удаленной страны не восстанавливается
+	if _, err := s.Countries.DeleteCountry(ctx, countryID, 0, false); err != nil {
+		t.Fatalf("DeleteCountry: %v", err)
+	}
+	if err := s.Places.Restore(ctx, place.ID); !errors.Is(err, repository.ErrReference) {
+		t.Errorf("Restore with deleted country = %v, want ErrReference", err)
+	}
+	if _, err := s.Countries.Restore(ctx, countryID); err != nil {
+		t.Fatalf("Restore country: %v", err)
+	}
+	if err := s.Places.Restore(ctx, place.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := getPlace(t, s, place.ID); got.Version != 3 || got.DeletedAt != nil {
+		t.Errorf("restored place = %+v", got)
+	}
+
+	if err := s.Places.Delete(ctx, place.ID, 0); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, err := s.Places.Purge(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("Purge = %d, %v; want 1", n, err)
+	}
+	if err := s.Places.Restore(ctx, place.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore of purged place = %v, want ErrNotFound", err)
+	}
+}
+
+func addCountry(t *testing.T, s Stores, country *entity.Country) int {
+	t.Helper()
+	id, err := s.Countries.AddCountry(context.Background(), country)
+	if err != nil {
+		t.Fatalf("AddCountry(%s): %v", country.Name, err)
+	}
+	return id
+}
+
+func getCountry(t *testing.T, s Stores, id int) entity.Country {
+	t.Helper()
+	country, err := s.Countries.GetCountryByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetCountryByID(%d): %v", id, err)
+	}
+	return country
+}
+
+func createPlace(t *testing.T, s Stores, place *entity.Place) *entity.Place {
+	t.Helper()
+	created, err := s.Places.Create(context.Background(), place)
+	if err != nil {
+		t.Fatalf("Create(%s): %v", place.Name, err)
+	}
+	return created
+}
+
+func getPlace(t *testing.T, s Stores, id int) *entity.Place {
+	t.Helper()
+	place, err := s.Places.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID(%d): %v", id, err)
+	}
+	return place
+}
+
+func countryNames(countries []entity.Country) []string {
+	names := []string{}
+	for _, c := range countries {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func placeNames(places []*entity.Place) []string {
+	names := []string{}
+	for _, p := range places {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// sameIDs сравнивает наборы ID без учета порядка
+func sameIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[int]int)
+	for _, id := range got {
+		seen[id]++
+	}
+	for _, id := range want {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Executor - общее у *sqlx.DB и *sqlx.Tx. Репозитории работают через него,
+// поэтому одни и те же методы выполняются и отдельно, и внутри транзакции
+// TxManager.
+type Executor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// TxManager выполняет вызовы нескольких репозиториев в одной транзакции
+type TxManager struct {
+	db Executor
+}
+
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx передает в fn репозитории, привязанные к новой транзакции.
+// Транзакция фиксируется, если fn вернула nil, иначе откатывается; при
+// отмене ctx откатывается и незавершенная транзакция.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(repo *Repository) error) error {
+	return inTx(ctx, m.db, func(tx Executor) error {
+		return fn(newRepository(tx))
+	})
+}
+
+// InTx - WithinTx для сервисов, работающих через TxRunner
+func (m *TxManager) InTx(ctx context.Context, fn func(tx Stores) error) error {
+	return m.WithinTx(ctx, func(repo *Repository) error {
+		return fn(repo.Stores())
+	})
+}
+
+// inTx выполняет fn в транзакции: в текущей, если ex уже транзакция
+// (репозиторий получен из WithinTx), иначе в новой. Так многошаговые методы
+// репозиториев атомарны и сами по себе, и в составе транзакции сервиса.
+func inTx(ctx context.Context, ex Executor, fn func(tx Executor) error) error {
+	if r, ok := ex.(*resilientExecutor); ok {
+		return r.inTx(ctx, fn)
+	}
+	if o, ok := ex.(*observedExecutor); ok {
+		return inTx(ctx, o.Executor, func(tx Executor) error {
+			return fn(observe(tx, o.observer))
+		})
+	}
+
+	// транзакции всегда открываются в основной БД
+	if r, ok := ex.(*routedExecutor); ok {
+		ex = r.Executor
+	}
+
+	db, ok := ex.(*sqlx.DB)
+	if !ok {
+		return fn(ex)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// getNamed выполняет запрос с параметрами :name из полей arg и читает
+// первую строку результата в dest
+func getNamed(ctx context.Context, ex Executor, dest interface{}, query string, arg interface{}) error {
+	query, args, err := ex.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return ex.GetContext(ctx, dest, query, args...)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+// Ограничения размера страницы журнала аудита
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type auditTrailKey struct{}
+
+// AuditTrail накапливает изменения сущностей за один запрос: сервисы
+// добавляют в него состояния до и после изменения, а записывает в журнал
+// middleware вместе с данными запроса
+type AuditTrail struct {
+	mu      sync.Mutex
+	changes []entity.AuditChange
+}
+
+// WithAuditTrail добавляет в контекст пустой AuditTrail
+func WithAuditTrail(ctx context.Context) (context.Context, *AuditTrail) {
+	trail := &AuditTrail{}
+	return context.WithValue(ctx, auditTrailKey{}, trail), trail
+}
+
+func (t *AuditTrail) Changes() []entity.AuditChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]entity.AuditChange(nil), t.changes...)
+}
+
+// auditChange добавляет изменение сущности в AuditTrail запроса, если он
+// есть в контексте. before или after равны nil (в том числе nil-указателю),
+// если сущности не было до создания или не стало после удаления.
+func auditChange(ctx context.Context, entityType string, entityID int, before, after interface{}) {
+	trail, ok := ctx.Value(auditTrailKey{}).(*AuditTrail)
+	if !ok {
+		return
+	}
+
+	change := entity.AuditChange{EntityType: entityType, EntityID: entityID}
+	// Ошибка кодирования не должна отменять уже сохраненное изменение:
+	// в журнале останется запись без состояния
+	if !isNil(before) {
+		change.Before, _ = json.Marshal(before)
+	}
+	if !isNil(after) {
+		change.After, _ = json.Marshal(after)
+	}
+
+	trail.mu.Lock()
+	trail.changes = append(trail.changes, change)
+	trail.mu.Unlock()
+}
+
+// isNil сообщает, что v - nil или nil-указатель: nil-указатель в
+// interface{} не равен nil и записался бы в журнал как JSON null
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+type AuditService struct {
+	repo *repository.AuditRepository
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) Record(ctx context.Context, e *entity.AuditEntry) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	return s.repo.Create(ctx, e)
+}
+
+// List возвращает страницу журнала, начиная с последних записей
+func (s *AuditService) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.List")
+	defer span.End()
+
+	if err := validateAuditFilter(filter); err != nil {
+		return nil, err
+	}
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditLimit
+	case filter.Limit < 0 || filter.Limit > maxAuditLimit:
+		return nil, Invalid("limit", "must be between 1 and 1000")
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Export передает в fn все записи по фильтру в порядке их появления; limit не применяется
+func (s *AuditService) Export(ctx context.Context, filter entity.AuditFilter, fn func(*entity.AuditEntry) error) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Export")
+	defer span.End()
+
+	if err := validateAuditFilter(filter); err != nil {
+		return err
+	}
+	filter.Limit = 0
+	return s.repo.Export(ctx, filter, fn)
+}
+
+func validateAuditFilter(filter entity.AuditFilter) error {
+	verr := &Validation{}
+	if filter.Outcome != "" && filter.Outcome != entity.AuditSuccess && filter.Outcome != entity.AuditFailure {
+		verr.Add("outcome", "must be success or failure")
+	}
+	switch filter.EntityType {
+	case "", entity.EntityCountry, entity.EntityPlace, entity.EntityRegion, entity.EntityCity:
+	default:
+		verr.Add("entity_type", "must be country, place, region or city")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		verr.Add("to", "must be after from")
+	}
+	return verr.Err()
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+type CityService struct {
+	repo        *repository.CityRepository
+	regionRepo  *repository.RegionRepository
+	countryRepo repository.CountryStore
+}
+
+func NewCityService(repo *repository.CityRepository, regionRepo *repository.RegionRepository, countryRepo repository.CountryStore) *CityService {
+	return &CityService{repo: repo, regionRepo: regionRepo, countryRepo: countryRepo}
+}
+
+func (s *CityService) Create(ctx context.Context, city *entity.City) (*entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.Create")
+	defer span.End()
+
+	if err := s.validate(ctx, city); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(ctx, city)
+	if err != nil {
+		return nil, repoError("city", err)
+	}
+	auditChange(ctx, entity.EntityCity, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *CityService) GetByID(ctx context.Context, id int) (*entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.GetByID")
+	defer span.End()
+
+	if id <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	city, err := s.repo.GetByID(ctx, id)
+	return city, repoError("city", err)
+}
+
+func (s *CityService) GetAll(ctx context.Context, filter entity.CityFilter) ([]entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.GetAll")
+	defer span.End()
+
+	cities, err := s.repo.GetAll(ctx, filter)
+	return cities, repoError("city", err)
+}
+
+func (s *CityService) GetByCountry(ctx context.Context, countryID int) ([]entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.GetByCountry")
+	defer span.End()
+
+	if _, err := s.countryRepo.GetCountryByID(ctx, countryID); err != nil {
+		return nil, repoError("country", err)
+	}
+	cities, err := s.repo.GetAll(ctx, entity.CityFilter{CountryID: countryID})
+	return cities, repoError("city", err)
+}
+
+func (s *CityService) GetByRegion(ctx context.Context, regionID int) ([]entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.GetByRegion")
+	defer span.End()
+
+	if _, err := s.regionRepo.GetByID(ctx, regionID); err != nil {
+		return nil, repoError("region", err)
+	}
+	cities, err := s.repo.GetAll(ctx, entity.CityFilter{RegionID: regionID})
+	return cities, repoError("city", err)
+}
+
+func (s *CityService) Update(ctx context.Context, city *entity.City) (*entity.City, error) {
+	ctx, span := tracing.Start(ctx, "CityService.Update")
+	defer span.End()
+
+	if city.ID <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	if err := s.validate(ctx, city); err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetByID(ctx, city.ID)
+	if err != nil {
+		return nil, repoError("city", err)
+	}
+	updated, err := s.repo.Update(ctx, city)
+	if err != nil {
+		return nil, repoError("city", err)
+	}
+	auditChange(ctx, entity.EntityCity, updated.ID, before, updated)
+	return updated, nil
+}
+
+func (s *CityService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "CityService.Delete")
+	defer span.End()
+
+	if id <= 0 {
+		return Invalid("id", "must be positive")
+	}
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return repoError("city", err)
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return repoError("city", err)
+	}
+	auditChange(ctx, entity.EntityCity, id, before, nil)
+	return nil
+}
+
+// validate проверяет, что регион города принадлежит той же стране
+func (s *CityService) validate(ctx context.Context, city *entity.City) error {
+	if city.Name == "" {
+		return Invalid("name", "is required")
+	}
+	if _, err := s.countryRepo.GetCountryByID(ctx, city.CountryID); errors.Is(err, repository.ErrNotFound) {
+		return Invalid("country_id", "country not found")
+	} else if err != nil {
+		return err
+	}
+	if city.RegionID != nil {
+		region, err := s.regionRepo.GetByID(ctx, *city.RegionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return Invalid("region_id", "region not found")
+		}
+		if err != nil {
+			return err
+		}
+		if region.CountryID != city.CountryID {
+			return Invalid("region_id", "region belongs to another country")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+type ContinentService struct {
+	repo        *repository.ContinentRepository
+	countryRepo repository.CountryStore
+}
+
+func NewContinentService(repo *repository.ContinentRepository, countryRepo repository.CountryStore) *ContinentService {
+	return &ContinentService{repo: repo, countryRepo: countryRepo}
+}
+
+func (s *ContinentService) GetAll(ctx context.Context) ([]entity.Continent, error) {
+	ctx, span := tracing.Start(ctx, "ContinentService.GetAll")
+	defer span.End()
+
+	continents, err := s.repo.GetAll(ctx)
+	return continents, repoError("continent", err)
+}
+
+func (s *ContinentService) GetByCode(ctx context.Context, code string) (*entity.Continent, error) {
+	ctx, span := tracing.Start(ctx, "ContinentService.GetByCode")
+	defer span.End()
+
+	continent, err := s.repo.GetByCode(ctx, code)
+	return continent, repoError("continent", err)
+}
+
+func (s *ContinentService) GetCountries(ctx context.Context, code string) ([]entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "ContinentService.GetCountries")
+	defer span.End()
+
+	continent, err := s.repo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, repoError("continent", err)
+	}
+	countries, err := s.countryRepo.GetCountriesByContinent(ctx, continent.Code)
+	return countries, repoError("country", err)
+}
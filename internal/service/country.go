@@ -1,53 +1,365 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/iso"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
 )
 
 type CountryService struct {
-	repo repository.CountryRepository
+	repo          repository.CountryStore
+	continentRepo *repository.ContinentRepository
+	placeRepo     repository.PlaceStore
+	revisions     *RevisionService
+	txm           repository.TxRunner
 }
 
-func NewCountryService(repo repository.CountryRepository) *CountryService {
-	return &CountryService{repo: repo}
+func NewCountryService(repo repository.CountryStore, continentRepo *repository.ContinentRepository, placeRepo repository.PlaceStore, revisions *RevisionService, txm repository.TxRunner) *CountryService {
+	return &CountryService{repo: repo, continentRepo: continentRepo, placeRepo: placeRepo, revisions: revisions, txm: txm}
 }
 
-func (s *CountryService) GetCountries() ([]entity.Country, error) {
-	return s.repo.GetCountries()
+func (s *CountryService) GetCountries(ctx context.Context) ([]entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.GetCountries")
+	defer span.End()
+
+	countries, err := s.repo.GetCountries(ctx)
+	return countries, repoError("country", err)
 }
 
-func (s *CountryService) GetCountryByID(id int) (entity.Country, error) {
-	return s.repo.GetCountryByID(id)
+func (s *CountryService) GetCountryByID(ctx context.Context, id int) (entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.GetCountryByID")
+	defer span.End()
+
+	country, err := s.repo.GetCountryByID(ctx, id)
+	return country, repoError("country", err)
 }
 
-func (s *CountryService) AddCountry(country *entity.Country) (int, error) {
-	return s.repo.AddCountry(country)
+func (s *CountryService) GetCountryByCode(ctx context.Context, code string) (entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.GetCountryByCode")
+	defer span.End()
+
+	if _, ok := iso.LookupCountry(code); !ok {
+		return entity.Country{}, Invalid("code", fmt.Sprintf("%q is not an ISO 3166-1 country code", code))
+	}
+	country, err := s.repo.GetCountryByCode(ctx, code)
+	return country, repoError("country", err)
 }
 
-func (s *CountryService) DeleteCountry(id int) (int, error) {
-	return s.repo.DeleteCountry(id)
+func (s *CountryService) AddCountry(ctx context.Context, country *entity.Country) (int, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.AddCountry")
+	defer span.End()
+
+	if err := normalizeCodes(country); err != nil {
+		return 0, err
+	}
+	if err := s.validateContinent(ctx, country); err != nil {
+		return 0, err
+	}
+	created, err := s.write(ctx, nil, entity.RevisionCreate, func(tx *CountryService) (int, error) {
+		id, err := tx.repo.AddCountry(ctx, country)
+		return id, repoError("country", err)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
 }
 
-func (s *CountryService) UpdateCountry(country *entity.Country) (*entity.Country, error) {
+// DeleteCountry переносит страну в корзину, если ее версия равна version
+// (0 - любая). Страну с местами можно удалить только с cascade - тогда места
+// уходят в корзину вместе с ней. В историю записывается последнее состояние
+// страны и мест. Возвращает ID удаленных мест.
+func (s *CountryService) DeleteCountry(ctx context.Context, id int, version int64, cascade bool) ([]int, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.DeleteCountry")
+	defer span.End()
+
+	var (
+		country  entity.Country
+		places   []*entity.Place
+		placeIDs []int
+	)
+	deleted := make(map[int]bool)
+	// Страна и места читаются в той же транзакции, что и удаление, чтобы
+	// история и аудит описывали ровно те записи, которые ушли в корзину
+	err := s.txm.InTx(ctx, func(repo repository.Stores) error {
+		tx := s.bind(repo)
+		var err error
+		if country, err = tx.repo.GetCountryByID(ctx, id); err != nil {
+			return repoError("country", err)
+		}
+		if places, err = tx.placeRepo.GetPlacesByCountryID(ctx, id); err != nil {
+			return err
+		}
+		if len(places) > 0 && !cascade {
+			return Conflict("country_has_places",
+				"country has %d places; see GET /countries/%d/delete-preview and pass cascade=true to delete them too", len(places), id)
+		}
+
+		if placeIDs, err = tx.repo.DeleteCountry(ctx, id, version, cascade); err != nil {
+			return repoError("country", err)
+		}
+		if err := tx.revisions.Record(ctx, entity.EntityCountry, id, entity.RevisionDelete, country); err != nil {
+			return err
+		}
+		for _, placeID := range placeIDs {
+			deleted[placeID] = true
+		}
+		for _, place := range places {
+			if !deleted[place.ID] {
+				continue
+			}
+			if err := tx.revisions.Record(ctx, entity.EntityPlace, place.ID, entity.RevisionDelete, place); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditChange(ctx, entity.EntityCountry, id, country, nil)
+	for _, place := range places {
+		if deleted[place.ID] {
+			auditChange(ctx, entity.EntityPlace, place.ID, place, nil)
+		}
+	}
+	return placeIDs, nil
+}
+
+// DeletePreview показывает, какие записи затронет удаление страны
+func (s *CountryService) DeletePreview(ctx context.Context, id int) (*entity.CountryDeletePreview, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.DeletePreview")
+	defer span.End()
+
+	if _, err := s.repo.GetCountryByID(ctx, id); err != nil {
+		return nil, repoError("country", err)
+	}
+	preview, err := s.repo.DeletePreview(ctx, id)
+	return preview, repoError("country", err)
+}
+
+// UpdateCountry перезаписывает страну; country.Version - ожидаемая текущая
+// версия (0 - любая)
+func (s *CountryService) UpdateCountry(ctx context.Context, country *entity.Country) (*entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.UpdateCountry")
+	defer span.End()
+
 	// Проверяем существование страны
-	if _, err := s.repo.GetCountryByID(country.ID); err != nil {
-		return nil, fmt.Errorf("country not found")
+	existing, err := s.repo.GetCountryByID(ctx, country.ID)
+	if err != nil {
+		return nil, repoError("country", err)
+	}
+
+	if err := s.validate(ctx, country); err != nil {
+		return nil, err
+	}
+
+	return s.write(ctx, &existing, entity.RevisionUpdate, func(tx *CountryService) (int, error) {
+		_, err := tx.repo.UpdateCountry(ctx, country)
+		return country.ID, repoError("country", err)
+	})
+}
+
+// PatchCountry применяет к стране merge patch (RFC 7396) и сохраняет
+// изменившиеся поля. Объединенная страна проверяется так же, как при PUT.
+// version - ожидаемая текущая версия (0 - любая).
+func (s *CountryService) PatchCountry(ctx context.Context, id int, version int64, patch []byte) (*entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.PatchCountry")
+	defer span.End()
+
+	original, err := s.repo.GetCountryByID(ctx, id)
+	if err != nil {
+		return nil, repoError("country", err)
+	}
+	if version != 0 && original.Version != version {
+		return nil, repoError("country", repository.ErrVersionMismatch)
+	}
+
+	var updated entity.Country
+	if err := applyMergePatch(original, patch, &updated); err != nil {
+		return nil, err
+	}
+	if updated.ID != original.ID {
+		return nil, Invalid("id", "is read-only")
+	}
+	if err := s.validate(ctx, &updated); err != nil {
+		return nil, err
 	}
 
-	// Валидация данных
+	return s.write(ctx, &original, entity.RevisionUpdate, func(tx *CountryService) (int, error) {
+		return id, repoError("country", tx.repo.PatchCountry(ctx, &original, &updated))
+	})
+}
+
+// RestoreRevision возвращает стране состояние из ревизии rev. Восстановленная
+// страна проверяется так же, как при PUT, и сохраняется новой ревизией.
+// version - ожидаемая текущая версия (0 - любая).
+func (s *CountryService) RestoreRevision(ctx context.Context, id, rev int, version int64) (*entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.RestoreRevision")
+	defer span.End()
+
+	original, err := s.repo.GetCountryByID(ctx, id)
+	if err != nil {
+		return nil, repoError("country", err)
+	}
+	if version != 0 && original.Version != version {
+		return nil, repoError("country", repository.ErrVersionMismatch)
+	}
+
+	revision, err := s.revisions.Get(ctx, entity.EntityCountry, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	var restored entity.Country
+	if err := json.Unmarshal(revision.Snapshot, &restored); err != nil {
+		return nil, fmt.Errorf("failed to decode country revision %d: %w", rev, err)
+	}
+	restored.ID, restored.Version = original.ID, original.Version
+	if err := s.validate(ctx, &restored); err != nil {
+		return nil, err
+	}
+
+	return s.write(ctx, &original, entity.RevisionRestore, func(tx *CountryService) (int, error) {
+		return id, repoError("country", tx.repo.PatchCountry(ctx, &original, &restored))
+	})
+}
+
+// write выполняет изменение fn, чтение сохраненной страны и запись ревизии в
+// одной транзакции; fn возвращает ID страны. В журнал аудита изменение
+// попадает только после фиксации; before - страна до изменения (nil при
+// создании).
+func (s *CountryService) write(ctx context.Context, before *entity.Country, action string, fn func(tx *CountryService) (int, error)) (*entity.Country, error) {
+	var country entity.Country
+	err := s.txm.InTx(ctx, func(repo repository.Stores) error {
+		tx := s.bind(repo)
+		id, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		if country, err = tx.GetCountryByID(ctx, id); err != nil {
+			return err
+		}
+		return tx.revisions.Record(ctx, entity.EntityCountry, id, action, country)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditChange(ctx, entity.EntityCountry, country.ID, before, country)
+	return &country, nil
+}
+
+// bind возвращает копию сервиса, работающую с хранилищами транзакции repo
+func (s *CountryService) bind(repo repository.Stores) *CountryService {
+	tx := *s
+	tx.repo = repo.Countries
+	tx.continentRepo = repo.Continents
+	tx.placeRepo = repo.Places
+	tx.revisions = NewRevisionService(repo.Revisions)
+	return &tx
+}
+
+func (s *CountryService) SearchCountries(ctx context.Context, query string, limit int) ([]entity.Country, error) {
+	ctx, span := tracing.Start(ctx, "CountryService.SearchCountries")
+	defer span.End()
+
+	countries, err := s.repo.SearchByName(ctx, query, limit)
+	return countries, repoError("country", err)
+}
+
+// validate проверяет обязательные поля и коды страны перед сохранением
+func (s *CountryService) validate(ctx context.Context, country *entity.Country) error {
+	verr := &Validation{}
 	if country.Name == "" {
-		return nil, fmt.Errorf("country name is required")
+		verr.Add("name", "is required")
 	}
 	if country.Capital == "" {
-		return nil, fmt.Errorf("capital is required")
+		verr.Add("capital", "is required")
 	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+	if err := normalizeCodes(country); err != nil {
+		return err
+	}
+	return s.validateContinent(ctx, country)
+}
 
-	return s.repo.UpdateCountry(country)
+func (s *CountryService) validateContinent(ctx context.Context, country *entity.Country) error {
+	if country.ContinentCode == "" {
+		return nil
+	}
+	continent, err := s.continentRepo.GetByCode(ctx, country.ContinentCode)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Invalid("continent_code", fmt.Sprintf("unknown continent %q", country.ContinentCode))
+	}
+	if err != nil {
+		return err
+	}
+	country.ContinentCode = continent.Code
+	return nil
 }
 
-func (s *CountryService) SearchCountries(query string, limit int) ([]entity.Country, error) {
-	return s.repo.SearchByName(query, limit)
+// normalizeCodes проверяет коды ISO страны, языков и валют и приводит их к
+// каноническому виду. Если указан только один из кодов страны, второй
+// заполняется по справочнику.
+func normalizeCodes(country *entity.Country) error {
+	if country.ISO2 != "" || country.ISO3 != "" {
+		var ref iso.Country
+		var ok bool
+		if country.ISO2 != "" {
+			if ref, ok = iso.LookupCountry(country.ISO2); !ok || len(strings.TrimSpace(country.ISO2)) != 2 {
+				return Invalid("iso2", fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code", country.ISO2))
+			}
+		}
+		if country.ISO3 != "" {
+			ref3, ok3 := iso.LookupCountry(country.ISO3)
+			if !ok3 || len(strings.TrimSpace(country.ISO3)) != 3 {
+				return Invalid("iso3", fmt.Sprintf("%q is not an ISO 3166-1 alpha-3 code", country.ISO3))
+			}
+			if ok && ref3.Alpha2 != ref.Alpha2 {
+				return Invalid("iso3", fmt.Sprintf("%q does not match iso2 code %q", country.ISO3, country.ISO2))
+			}
+			ref = ref3
+		}
+		country.ISO2, country.ISO3 = ref.Alpha2, ref.Alpha3
+	}
+
+	languages := make([]string, 0, len(country.Languages))
+	seen := make(map[string]bool)
+	for _, code := range country.Languages {
+		l, ok := iso.LookupLanguage(code)
+		if !ok {
+			return Invalid("languages", fmt.Sprintf("%q is not an ISO 639 language code", code))
+		}
+		if !seen[l.Code] {
+			seen[l.Code] = true
+			languages = append(languages, l.Code)
+		}
+	}
+	country.Languages = languages
+
+	currencies := make([]string, 0, len(country.Currencies))
+	seen = make(map[string]bool)
+	for _, code := range country.Currencies {
+		c, ok := iso.LookupCurrency(code)
+		if !ok {
+			return Invalid("currencies", fmt.Sprintf("%q is not an ISO 4217 currency code", code))
+		}
+		if !seen[c.Code] {
+			seen[c.Code] = true
+			currencies = append(currencies, c.Code)
+		}
+	}
+	country.Currencies = currencies
+
+	return nil
 }
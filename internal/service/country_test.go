@@ -0,0 +1,205 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/internal/repository/memory"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+)
+
+// addCountryWithPlace создает страну с одним местом
+func addCountryWithPlace(t *testing.T, s memoryServices) (countryID, placeID int) {
+	t.Helper()
+	ctx := context.Background()
+	countryID, err := s.countries.AddCountry(ctx, &entity.Country{Name: "Georgia", Capital: "Tbilisi"})
+	if err != nil {
+		t.Fatalf("AddCountry: %v", err)
+	}
+	place, err := s.places.Create(ctx, &entity.Place{Name: "Narikala", CountryID: countryID})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return countryID, place.ID
+}
+
+// wantError проверяет вид и код ошибки сервиса
+func wantError(t *testing.T, err, kind error, code string) {
+	t.Helper()
+	serr, ok := service.AsError(err)
+	if !ok || !errors.Is(err, kind) || serr.Code != code {
+		t.Fatalf("error = %v, want %v with code %s", err, kind, code)
+	}
+}
+
+// lastAction возвращает действие последней ревизии сущности
+func lastAction(t *testing.T, s memoryServices, entityType string, id int) string {
+	t.Helper()
+	history, err := s.revisions.List(context.Background(), entityType, id)
+	if err != nil {
+		t.Fatalf("List revisions: %v", err)
+	}
+	if len(history) == 0 {
+		t.Fatalf("%s %d has no revisions", entityType, id)
+	}
+	return history[0].Action
+}
+
+func TestDeleteCountryCascade(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices()
+	countryID, placeID := addCountryWithPlace(t, s)
+
+	_, err := s.countries.DeleteCountry(ctx, countryID, 0, false)
+	wantError(t, err, service.ErrConflict, "country_has_places")
+	if _, err := s.countries.GetCountryByID(ctx, countryID); err != nil {
+		t.Fatalf("country deleted without cascade: %v", err)
+	}
+
+	placeIDs, err := s.countries.DeleteCountry(ctx, countryID, 0, true)
+	if err != nil {
+		t.Fatalf("DeleteCountry cascade: %v", err)
+	}
+	if len(placeIDs) != 1 || placeIDs[0] != placeID {
+		t.Fatalf("deleted places = %v, want [%d]", placeIDs, placeID)
+	}
+	_, err = s.places.GetByID(ctx, placeID)
+	wantError(t, err, service.ErrNotFound, "place_not_found")
+	if action := lastAction(t, s, entity.EntityCountry, countryID); action != entity.RevisionDelete {
+		t.Errorf("country last revision = %s, want delete", action)
+	}
+	if action := lastAction(t, s, entity.EntityPlace, placeID); action != entity.RevisionDelete {
+		t.Errorf("place last revision = %s, want delete", action)
+	}
+}
+
+func TestTrashRestoreCountry(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices()
+	countryID, placeID := addCountryWithPlace(t, s)
+	if _, err := s.countries.DeleteCountry(ctx, countryID, 0, true); err != nil {
+		t.Fatalf("DeleteCountry: %v", err)
+	}
+
+	// Место удаленной страны восстановить нельзя
+	if err := s.trash.Restore(ctx, entity.EntityPlace, placeID); err == nil {
+		t.Fatal("restored place of a deleted country")
+	}
+
+	if err := s.trash.Restore(ctx, entity.EntityCountry, countryID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := s.countries.GetCountryByID(ctx, countryID); err != nil {
+		t.Fatalf("country not restored: %v", err)
+	}
+	if _, err := s.places.GetByID(ctx, placeID); err != nil {
+		t.Fatalf("place not restored with country: %v", err)
+	}
+	if action := lastAction(t, s, entity.EntityCountry, countryID); action != entity.RevisionRestore {
+		t.Errorf("country last revision = %s, want restore", action)
+	}
+	if action := lastAction(t, s, entity.EntityPlace, placeID); action != entity.RevisionRestore {
+		t.Errorf("place last revision = %s, want restore", action)
+	}
+
+	items, err := s.trash.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("trash after restore = %+v, want empty", items)
+	}
+}
+
+func TestVersionMismatch(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices()
+	countryID, placeID := addCountryWithPlace(t, s)
+
+	country, err := s.countries.GetCountryByID(ctx, countryID)
+	if err != nil {
+		t.Fatalf("GetCountryByID: %v", err)
+	}
+	stale := country.Version
+	country.Capital = "Kutaisi"
+	if _, err := s.countries.UpdateCountry(ctx, &country); err != nil {
+		t.Fatalf("UpdateCountry: %v", err)
+	}
+
+	country.Version = stale
+	_, err = s.countries.UpdateCountry(ctx, &country)
+	wantError(t, err, service.ErrPreconditionFailed, "country_version_mismatch")
+	_, err = s.countries.DeleteCountry(ctx, countryID, stale, true)
+	wantError(t, err, service.ErrPreconditionFailed, "country_version_mismatch")
+
+	place, err := s.places.GetByID(ctx, placeID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	err = s.places.Delete(ctx, placeID, place.Version+1)
+	wantError(t, err, service.ErrPreconditionFailed, "place_version_mismatch")
+	if _, err := s.places.GetByID(ctx, placeID); err != nil {
+		t.Fatalf("place deleted despite version mismatch: %v", err)
+	}
+}
+
+// failingRevisions отказывает в записи ревизий сущностей типа entityType
+type failingRevisions struct {
+	repository.RevisionStore
+	entityType string
+}
+
+var errRevisionWrite = errors.New("revision write failed")
+
+func (r failingRevisions) Create(ctx context.Context, rev *entity.Revision) (*entity.Revision, error) {
+	if rev.EntityType == r.entityType {
+		return nil, errRevisionWrite
+	}
+	return r.RevisionStore.Create(ctx, rev)
+}
+
+// failingTx подменяет в транзакции хранилище ревизий на failingRevisions
+type failingTx struct {
+	txm        repository.TxRunner
+	entityType string
+}
+
+func (f *failingTx) InTx(ctx context.Context, fn func(tx repository.Stores) error) error {
+	return f.txm.InTx(ctx, func(tx repository.Stores) error {
+		tx.Revisions = failingRevisions{tx.Revisions, f.entityType}
+		return fn(tx)
+	})
+}
+
+func TestDeleteCountryRollback(t *testing.T) {
+	ctx := context.Background()
+	stores, txm := memory.Stores(memory.NewDB())
+	countryID, placeID := addCountryWithPlace(t, newServices(stores, txm))
+
+	// Страна и место уходят в корзину, ревизия страны пишется, а ревизия
+	// места - нет: вся транзакция должна откатиться
+	s := newServices(stores, &failingTx{txm: txm, entityType: entity.EntityPlace})
+	if _, err := s.countries.DeleteCountry(ctx, countryID, 0, true); !errors.Is(err, errRevisionWrite) {
+		t.Fatalf("DeleteCountry error = %v, want %v", err, errRevisionWrite)
+	}
+
+	if _, err := s.countries.GetCountryByID(ctx, countryID); err != nil {
+		t.Fatalf("country not rolled back: %v", err)
+	}
+	if _, err := s.places.GetByID(ctx, placeID); err != nil {
+		t.Fatalf("place not rolled back: %v", err)
+	}
+	if action := lastAction(t, s, entity.EntityCountry, countryID); action != entity.RevisionCreate {
+		t.Errorf("country last revision = %s, want create", action)
+	}
+	items, err := s.trash.List(ctx, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("trash after rollback = %+v, want empty", items)
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+	"github.com/ShekleinAleksey/top-places/pkg/wikidata"
+)
+
+// EnrichmentService заполняет поля стран данными Wikidata. Пустые поля
+// заполняются сразу, а значения, отредактированные вручную, перезаписываются
+// только после одобрения администратором.
+type EnrichmentService struct {
+	source         wikidata.Source
+	lang           string
+	countryRepo    repository.CountryStore
+	enrichmentRepo *repository.EnrichmentRepository
+	revisions      *RevisionService
+	txm            repository.TxRunner
+}
+
+func NewEnrichmentService(source wikidata.Source, lang string, countryRepo repository.CountryStore, enrichmentRepo *repository.EnrichmentRepository, revisions *RevisionService, txm repository.TxRunner) *EnrichmentService {
+	return &EnrichmentService{
+		source:         source,
+		lang:           lang,
+		countryRepo:    countryRepo,
+		enrichmentRepo: enrichmentRepo,
+		revisions:      revisions,
+		txm:            txm,
+	}
+}
+
+func (s *EnrichmentService) Run(ctx context.Context) (*entity.EnrichmentReport, error) {
+	ctx, span := tracing.Start(ctx, "EnrichmentService.Run")
+	defer span.End()
+
+	if s.source == nil {
+		return nil, Unavailable("enrichment_unavailable", "enrichment source is not configured")
+	}
+
+	countries, err := s.countryRepo.GetCountries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.EnrichmentReport{Countries: len(countries), Unmatched: []string{}}
+	for _, country := range countries {
+		e, err := s.source.Lookup(ctx, wikidata.Ref{ID: country.WikidataID, Name: country.Name})
+		if errors.Is(err, wikidata.ErrNotFound) {
+			report.Unmatched = append(report.Unmatched, country.Name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %q: %w", country.Name, err)
+		}
+		report.Matched++
+
+		values, err := s.extract(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+
+		var result enrichResult
+		err = s.txm.InTx(ctx, func(repo repository.Stores) (err error) {
+			result, err = s.bind(repo).enrichCountry(ctx, country.ID, e.ID, values)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.Applied += result.applied
+		report.Proposed += result.proposed
+		if result.after != nil {
+			auditChange(ctx, entity.EntityCountry, country.ID, result.before, result.after)
+		}
+	}
+
+	return report, nil
+}
+
+// enrichResult - итог обогащения одной страны. after == nil - поля страны
+// не изменились.
+type enrichResult struct {
+	applied, proposed int
+	before, after     *entity.Country
+}
+
+// enrichCountry записывает в страну id значения values сущности Wikidata ref
+// и создает предложения для полей, измененных вручную. Вызывается в
+// транзакции, чтобы поля страны не изменились без записей об источнике
+// значений и без ревизии.
+func (s *EnrichmentService) enrichCountry(ctx context.Context, id int, ref string, values map[string]string) (enrichResult, error) {
+	var result enrichResult
+	country, err := s.countryRepo.GetCountryByID(ctx, id)
+	if err != nil {
+		return result, repoError("country", err)
+	}
+
+	changed := country.WikidataID == ""
+	if changed {
+		if err := s.countryRepo.UpdateField(ctx, id, entity.CountryFieldWikidataID, ref); err != nil {
+			return result, repoError("country", err)
+		}
+	}
+
+	current := countryValues(country)
+	for _, field := range enrichableFields {
+		applied, proposed, err := s.enrichField(ctx, id, field, current[field], values[field], ref)
+		if err != nil {
+			return result, err
+		}
+		if applied {
+			result.applied++
+			changed = true
+		}
+		if proposed {
+			result.proposed++
+		}
+	}
+	if !changed {
+		return result, nil
+	}
+
+	after, err := s.recordCountry(ctx, id)
+	if err != nil {
+		return result, err
+	}
+	result.before, result.after = &country, after
+	return result, nil
+}
+
+func (s *EnrichmentService) ListProposals(ctx context.Context, status string, countryID int) ([]entity.CountryEnrichment, error) {
+	ctx, span := tracing.Start(ctx, "EnrichmentService.ListProposals")
+	defer span.End()
+
+	proposals, err := s.enrichmentRepo.List(ctx, status, countryID)
+	return proposals, repoError("enrichment", err)
+}
+
+func (s *EnrichmentService) Approve(ctx context.Context, id int) (*entity.CountryEnrichment, error) {
+	ctx, span := tracing.Start(ctx, "EnrichmentService.Approve")
+	defer span.End()
+
+	var (
+		applied *entity.CountryEnrichment
+		result  enrichResult
+	)
+	err := s.txm.InTx(ctx, func(repo repository.Stores) (err error) {
+		applied, result, err = s.bind(repo).approve(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	auditChange(ctx, entity.EntityCountry, applied.CountryID, result.before, result.after)
+	return applied, nil
+}
+
+// approve применяет предложение и отмечает его примененным; вызывается в
+// транзакции, чтобы страна не изменилась без смены статуса предложения
+func (s *EnrichmentService) approve(ctx context.Context, id int) (*entity.CountryEnrichment, enrichResult, error) {
+	var result enrichResult
+	e, err := s.pendingProposal(ctx, id)
+	if err != nil {
+		return nil, result, err
+	}
+
+	value, err := fieldValue(e.Field, e.ProposedValue)
+	if err != nil {
+		return nil, result, err
+	}
+	before, err := s.countryRepo.GetCountryByID(ctx, e.CountryID)
+	if err != nil {
+		return nil, result, repoError("country", err)
+	}
+	if err := s.countryRepo.UpdateField(ctx, e.CountryID, e.Field, value); err != nil {
+		return nil, result, repoError("country", err)
+	}
+	if err := s.enrichmentRepo.SetStatus(ctx, e.ID, entity.EnrichmentApplied); err != nil {
+		return nil, result, repoError("enrichment", err)
+	}
+	after, err := s.recordCountry(ctx, e.CountryID)
+	if err != nil {
+		return nil, result, err
+	}
+	result.before, result.after = &before, after
+
+	applied, err := s.enrichmentRepo.GetByID(ctx, id)
+	return applied, result, repoError("enrichment", err)
+}
+
+func (s *EnrichmentService) Reject(ctx context.Context, id int) (*entity.CountryEnrichment, error) {
+	ctx, span := tracing.Start(ctx, "EnrichmentService.Reject")
+	defer span.End()
+
+	e, err := s.pendingProposal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enrichmentRepo.SetStatus(ctx, e.ID, entity.EnrichmentRejected); err != nil {
+		return nil, repoError("enrichment", err)
+	}
+
+	rejected, err := s.enrichmentRepo.GetByID(ctx, id)
+	return rejected, repoError("enrichment", err)
+}
+
+// recordCountry сохраняет ревизию страны после изменения ее полей
+// обогащением и возвращает измененную страну
+func (s *EnrichmentService) recordCountry(ctx context.Context, countryID int) (*entity.Country, error) {
+	country, err := s.countryRepo.GetCountryByID(ctx, countryID)
+	if err != nil {
+		return nil, repoError("country", err)
+	}
+	if err := s.revisions.Record(ctx, entity.EntityCountry, countryID, entity.RevisionUpdate, country); err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// bind возвращает копию сервиса, работающую с хранилищами транзакции repo
+func (s *EnrichmentService) bind(repo repository.Stores) *EnrichmentService {
+	tx := *s
+	tx.countryRepo = repo.Countries
+	tx.enrichmentRepo = repo.Enrichment
+	tx.revisions = NewRevisionService(repo.Revisions)
+	return &tx
+}
+
+func (s *EnrichmentService) pendingProposal(ctx context.Context, id int) (*entity.CountryEnrichment, error) {
+	e, err := s.enrichmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repoError("enrichment", err)
+	}
+	if e.Status != entity.EnrichmentPending {
+		return nil, Conflict("enrichment_already_reviewed", "enrichment is already %s", e.Status)
+	}
+	return e, nil
+}
+
+// enrichField применяет значение сразу, если поле пустое или еще хранит
+// значение, ранее записанное обогащением; иначе создает предложение
+func (s *EnrichmentService) enrichField(ctx context.Context, countryID int, field, current, proposed, ref string) (applied, pending bool, err error) {
+	if proposed == "" || proposed == current {
+		return false, false, nil
+	}
+
+	autoApply := current == ""
+	if !autoApply {
+		last, ok, err := s.enrichmentRepo.LastApplied(ctx, countryID, field)
+		if err != nil {
+			return false, false, err
+		}
+		autoApply = ok && last == current
+	}
+
+	if !autoApply {
+		exists, err := s.enrichmentRepo.Exists(ctx, countryID, field, proposed)
+		if err != nil || exists {
+			return false, false, err
+		}
+	}
+
+	e := &entity.CountryEnrichment{
+		CountryID:     countryID,
+		Field:         field,
+		CurrentValue:  current,
+		ProposedValue: proposed,
+		Source:        s.source.Name(),
+		SourceRef:     wikidata.EntityURL(ref),
+		Status:        entity.EnrichmentPending,
+	}
+	if !autoApply {
+		_, err := s.enrichmentRepo.Create(ctx, e)
+		return false, err == nil, err
+	}
+
+	value, err := fieldValue(field, proposed)
+	if err != nil {
+		return false, false, err
+	}
+	if err := s.countryRepo.UpdateField(ctx, countryID, field, value); err != nil {
+		return false, false, repoError("country", err)
+	}
+	now := time.Now()
+	e.Status = entity.EnrichmentApplied
+	e.ReviewedAt = &now
+	if _, err := s.enrichmentRepo.Create(ctx, e); err != nil {
+		return false, false, err
+	}
+	return true, false, nil
+}
+
+// extract переводит сущность Wikidata в строковые значения полей страны
+func (s *EnrichmentService) extract(ctx context.Context, e *wikidata.Entity) (map[string]string, error) {
+	capitals := e.EntityIDs(wikidata.PropCapital)
+	languages := e.EntityIDs(wikidata.PropOfficialLanguage)
+	currencies := e.EntityIDs(wikidata.PropCurrency)
+
+	ids := append(append(append([]string{}, capitals...), languages...), currencies...)
+	labels, err := s.source.Labels(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve wikidata labels: %w", err)
+	}
+
+	values := map[string]string{
+		entity.CountryFieldCapital:     joinLabels(capitals[:min(len(capitals), 1)], labels),
+		entity.CountryFieldLanguage:    joinLabels(languages, labels),
+		entity.CountryFieldCurrency:    joinLabels(currencies, labels),
+		entity.CountryFieldDescription: e.Description(s.lang),
+		entity.CountryFieldFlagURL:     wikidata.CommonsFileURL(e.String(wikidata.PropFlagImage)),
+	}
+	if population, ok := e.Quantity(wikidata.PropPopulation); ok {
+		values[entity.CountryFieldPopulation] = strconv.FormatInt(int64(population), 10)
+	}
+	if area, ok := e.Quantity(wikidata.PropArea); ok {
+		values[entity.CountryFieldArea] = strconv.FormatFloat(area, 'f', -1, 64)
+	}
+
+	return values, nil
+}
+
+var enrichableFields = []string{
+	entity.CountryFieldCapital,
+	entity.CountryFieldLanguage,
+	entity.CountryFieldCurrency,
+	entity.CountryFieldDescription,
+	entity.CountryFieldPopulation,
+	entity.CountryFieldArea,
+	entity.CountryFieldFlagURL,
+}
+
+func countryValues(c entity.Country) map[string]string {
+	values := map[string]string{
+		entity.CountryFieldCapital:     c.Capital,
+		entity.CountryFieldLanguage:    c.Language,
+		entity.CountryFieldCurrency:    c.Currency,
+		entity.CountryFieldDescription: c.Description,
+		entity.CountryFieldFlagURL:     c.FlagURL,
+	}
+	if c.Population != nil {
+		values[entity.CountryFieldPopulation] = strconv.FormatInt(*c.Population, 10)
+	}
+	if c.Area != nil {
+		values[entity.CountryFieldArea] = strconv.FormatFloat(*c.Area, 'f', -1, 64)
+	}
+	return values
+}
+
+// fieldValue приводит строковое значение к типу колонки
+func fieldValue(field, value string) (interface{}, error) {
+	switch field {
+	case entity.CountryFieldPopulation:
+		return strconv.ParseInt(value, 10, 64)
+	case entity.CountryFieldArea:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+func joinLabels(ids []string, labels map[string]string) string {
+	var names []string
+	for _, id := range ids {
+		if label := labels[id]; label != "" {
+			names = append(names, label)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+// Виды ошибок сервисного слоя; проверяются через errors.Is
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrValidation  = errors.New("validation failed")
+	ErrConflict    = errors.New("conflict")
+	ErrUnavailable = errors.New("unavailable")
+	// ErrPreconditionFailed - версия записи не совпала с ожидаемой клиентом
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrUnprocessable - запрос корректен, но противоречит ранее принятому
+	ErrUnprocessable = errors.New("unprocessable")
+)
+
+// Коды ошибок валидации входных данных
+const (
+	CodeValidation  = "validation_failed"
+	CodeInvalidBody = "invalid_body"
+)
+
+// Error - ошибка с видом (Kind), стабильным машиночитаемым кодом и, для
+// ошибок валидации, списком ошибок по полям
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []entity.FieldError
+	// RetryAfter - через сколько повторить запрос, для ErrUnavailable
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func PreconditionFailed(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Unprocessable(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnprocessable, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func Unavailable(code, format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnavailable, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// AsError находит в err ошибку сервиса. Недоступность БД переводится в
+// ErrUnavailable, даже если сервис вернул ошибку репозитория как есть.
+func AsError(err error) (*Error, bool) {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr, true
+	}
+
+	var unavailable *repository.UnavailableError
+	if errors.As(err, &unavailable) {
+		return &Error{
+			Kind:       ErrUnavailable,
+			Code:       "database_unavailable",
+			Message:    "database is temporarily unavailable",
+			RetryAfter: unavailable.RetryAfter,
+		}, true
+	}
+	return nil, false
+}
+
+// Invalid - ошибка валидации одного поля
+func Invalid(field, message string) *Error {
+	v := &Validation{}
+	v.Add(field, message)
+	return v.Err().(*Error)
+}
+
+// Validation собирает ошибки валидации по полям, чтобы клиент получил их все сразу
+type Validation struct {
+	Fields []entity.FieldError
+}
+
+func (v *Validation) Add(field, message string) {
+	v.Fields = append(v.Fields, entity.FieldError{Field: field, Message: message})
+}
+
+// Err возвращает nil, если ошибок нет
+func (v *Validation) Err() error {
+	if len(v.Fields) == 0 {
+		return nil
+	}
+
+	parts := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return &Error{
+		Kind:    ErrValidation,
+		Code:    CodeValidation,
+		Message: "invalid input: " + strings.Join(parts, "; "),
+		Fields:  v.Fields,
+	}
+}
+
+// InvalidJSON переводит ошибку разбора JSON в ошибку валидации: ошибки
+// отдельных полей - с детализацией, синтаксические - с кодом invalid_body
+func InvalidJSON(err error) error {
+	var (
+		fieldErr  *entity.FieldError
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &fieldErr):
+		return Invalid(fieldErr.Field, fieldErr.Message)
+	case errors.As(err, &typeErr):
+		return Invalid(typeErr.Field, "must be of type "+typeErr.Type.String())
+	case errors.As(err, &syntaxErr):
+		return &Error{Kind: ErrValidation, Code: CodeInvalidBody, Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)}
+	case errors.Is(err, io.EOF):
+		return &Error{Kind: ErrValidation, Code: CodeInvalidBody, Message: "request body is empty"}
+	default:
+		return &Error{Kind: ErrValidation, Code: CodeInvalidBody, Message: "malformed request body"}
+	}
+}
+
+// repoError переводит ошибки репозитория в типизированные ошибки сервиса.
+// name - имя сущности в snake_case, из него строятся коды (place_not_found).
+func repoError(name string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrNotFound):
+		return NotFound(name+"_not_found", "%s not found", humanize(name))
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return PreconditionFailed(name+"_version_mismatch", "%s has been modified since it was read", humanize(name))
+	case errors.Is(err, repository.ErrConflict):
+		return Conflict(name+"_conflict", "%s conflicts with an existing record", humanize(name))
+	case errors.Is(err, repository.ErrInUse):
+		return Conflict(name+"_in_use", "%s is referenced by other records", humanize(name))
+	case errors.Is(err, repository.ErrReference):
+		return &Error{Kind: ErrValidation, Code: "invalid_reference", Message: fmt.Sprintf("%s references a missing record", humanize(name))}
+	default:
+		return err
+	}
+}
+
+func humanize(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/geo"
+	"github.com/ShekleinAleksey/top-places/pkg/iso"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+// GeoService проверяет координаты по границам стран. Если границы не
+// загружены (index == nil), проверка отключена, а обратное геокодирование недоступно.
+type GeoService struct {
+	index       *geo.Index
+	toleranceKm float64
+	countryRepo repository.CountryStore
+}
+
+func NewGeoService(index *geo.Index, toleranceKm float64, countryRepo repository.CountryStore) *GeoService {
+	return &GeoService{index: index, toleranceKm: toleranceKm, countryRepo: countryRepo}
+}
+
+func (s *GeoService) Enabled() bool {
+	return s != nil && s.index != nil
+}
+
+// Reverse возвращает страну, в которой лежит точка
+func (s *GeoService) Reverse(ctx context.Context, lat, lon float64) (*entity.ReverseGeocode, error) {
+	ctx, span := tracing.Start(ctx, "GeoService.Reverse")
+	defer span.End()
+
+	if !s.Enabled() {
+		return nil, Unavailable("geocoding_unavailable", "reverse geocoding is not configured")
+	}
+	verr := &Validation{}
+	if lat < -90 || lat > 90 {
+		verr.Add("lat", "must be in range [-90, 90]")
+	}
+	if lon < -180 || lon > 180 {
+		verr.Add("lon", "must be in range [-180, 180]")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	codes := s.index.Locate(lat, lon)
+	if len(codes) == 0 {
+		return nil, NotFound("location_not_in_country", "no country found at this location")
+	}
+
+	result := &entity.ReverseGeocode{Latitude: lat, Longitude: lon, ISO2: codes[0]}
+	if ref, ok := iso.LookupCountry(codes[0]); ok {
+		result.ISO3, result.Name = ref.Alpha3, ref.Name
+	}
+	country, err := s.countryRepo.GetCountryByCode(ctx, codes[0])
+	if err == nil {
+		result.Country = &country
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CheckPlacement проверяет, что точка лежит в стране (с допуском у границы).
+// Страны без кода ISO или без границ в индексе не проверяются.
+func (s *GeoService) CheckPlacement(country entity.Country, lat, lon float64) error {
+	if !s.Enabled() || country.ISO2 == "" {
+		return nil
+	}
+
+	distance, ok := s.index.DistanceKm(country.ISO2, lat, lon)
+	if !ok || distance <= s.toleranceKm {
+		return nil
+	}
+
+	located := s.index.Locate(lat, lon)
+	if len(located) > 0 {
+		return fmt.Errorf("point lies in %s, not in %s (%.0f km from its border)",
+			strings.Join(located, ", "), country.ISO2, distance)
+	}
+	return fmt.Errorf("point lies %.0f km outside %s", distance, country.ISO2)
+}
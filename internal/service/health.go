@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/buildinfo"
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+)
+
+// checkTimeout ограничивает каждую проверку готовности, чтобы зависшая БД
+// не задерживала ответ оркестратору
+const checkTimeout = 3 * time.Second
+
+// HealthService проверяет готовность сервиса к работе: БД отвечает, схема
+// мигрирована до версии, которую ожидает код, таблицы доступны
+type HealthService struct {
+	repo *repository.HealthRepository
+	// schemaVersion - номер последней миграции, известной сервису
+	schemaVersion int
+	config        entity.ServiceConfig
+	startedAt     time.Time
+}
+
+func NewHealthService(repo *repository.HealthRepository, schemaVersion int, config entity.ServiceConfig) *HealthService {
+	config.DBDriver = repo.Driver()
+	config.SchemaVersion = schemaVersion
+	return &HealthService{repo: repo, schemaVersion: schemaVersion, config: config, startedAt: time.Now()}
+}
+
+// Ready выполняет проверки параллельно; сервис готов, если прошли все
+func (s *HealthService) Ready(ctx context.Context) *entity.Health {
+	checks := map[string]func(ctx context.Context) error{
+		"database":   s.repo.Ping,
+		"migrations": s.checkMigrations,
+		"storage":    s.repo.Storage,
+	}
+
+	health := &entity.Health{Status: entity.HealthOK, Checks: make(map[string]entity.HealthCheck, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			health.Checks[name] = result
+			if result.Status != entity.HealthOK {
+				health.Status = entity.HealthUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+	// Разомкнутый выключатель не делает сервис неготовым: его состояние
+	// показывается для диагностики, а доступность БД проверяет database
+	health.Breaker = s.repo.Breaker()
+	return health
+}
+
+// Status возвращает версию сборки, время работы, основные настройки и
+// результат проверок готовности
+func (s *HealthService) Status(ctx context.Context) *entity.ServiceStatus {
+	return &entity.ServiceStatus{
+		Version:       buildinfo.Version,
+		Commit:        buildinfo.Commit,
+		BuildTime:     buildinfo.BuildTime,
+		GoVersion:     runtime.Version(),
+		StartedAt:     s.startedAt.UTC(),
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		Health:        *s.Ready(ctx),
+		Config:        s.config,
+	}
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, dirty, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d did not complete", version)
+	}
+	if version != s.schemaVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version, s.schemaVersion)
+	}
+	return nil
+}
+
+func runCheck(ctx context.Context, check func(ctx context.Context) error) entity.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := entity.HealthCheck{
+		Status:     entity.HealthOK,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = entity.HealthUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+// maxIdempotencyKeyLength - размер колонки idempotency_keys.key
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyService хранит ответы на запросы с Idempotency-Key, чтобы
+// повтор запроса не создавал запись второй раз. Ключи действуют ttl и
+// принадлежат пользователю, отправившему запрос.
+type IdempotencyService struct {
+	repo *repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo *repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin занимает ключ для запроса с хешем requestHash. Если запрос с этим
+// ключом уже выполнен, возвращает сохраненный ответ. Ключ, использованный с
+// другим запросом, - ошибка unprocessable; ключ запроса, который еще
+// выполняется, - conflict.
+func (s *IdempotencyService) Begin(ctx context.Context, actor, key, requestHash string) (*entity.IdempotencyRecord, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, Invalid("Idempotency-Key", "must be at most 255 characters")
+	}
+
+	// Вторая попытка нужна, если чужой ключ освободили между INSERT и SELECT
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, record, err := s.repo.Acquire(ctx, actor, key, requestHash, time.Now().Add(-s.ttl))
+		if acquired {
+			return nil, nil
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+
+		switch {
+		case record.RequestHash != requestHash:
+			return nil, Unprocessable("idempotency_key_reused", "Idempotency-Key has already been used with a different request")
+		case !record.Completed():
+			return nil, Conflict("idempotency_request_in_progress", "a request with this Idempotency-Key is still being processed")
+		default:
+			return record, nil
+		}
+	}
+	return nil, Conflict("idempotency_request_in_progress", "a request with this Idempotency-Key is still being processed")
+}
+
+// Complete сохраняет ответ, который получат повторы запроса
+func (s *IdempotencyService) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	return s.repo.Complete(ctx, record)
+}
+
+// Release освобождает ключ, чтобы клиент мог повторить неудавшийся запрос
+func (s *IdempotencyService) Release(ctx context.Context, actor, key string) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer span.End()
+
+	return s.repo.Release(ctx, actor, key)
+}
+
+// Purge удаляет ключи с истекшим сроком действия
+func (s *IdempotencyService) Purge(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Purge")
+	defer span.End()
+
+	return s.repo.Purge(ctx, time.Now().Add(-s.ttl))
+}
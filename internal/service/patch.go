@@ -0,0 +1,37 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ShekleinAleksey/top-places/pkg/mergepatch"
+)
+
+// applyMergePatch применяет merge patch (RFC 7396) к JSON-представлению
+// original и разбирает результат в dst. Проверять объединенную сущность
+// должен вызывающий.
+func applyMergePatch(original interface{}, patch []byte, dst interface{}) error {
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return fmt.Errorf("failed to encode original: %w", err)
+	}
+
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return invalidPatch(err)
+	}
+
+	if err := json.Unmarshal(merged, dst); err != nil {
+		return InvalidJSON(err)
+	}
+	return nil
+}
+
+// invalidPatch переводит ошибку разбора патча в ошибку валидации
+func invalidPatch(err error) error {
+	if errors.Is(err, mergepatch.ErrNotObject) {
+		return &Error{Kind: ErrValidation, Code: CodeInvalidBody, Message: err.Error()}
+	}
+	return InvalidJSON(errors.Unwrap(err))
+}
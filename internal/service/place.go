@@ -1,62 +1,119 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/ShekleinAleksey/top-places/internal/entity"
 	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/mergepatch"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
 )
 
 type PlaceService struct {
-	placeRepo   *repository.PlaceRepository
-	countryRepo *repository.CountryRepository
+	placeRepo     repository.PlaceStore
+	countryRepo   repository.CountryStore
+	continentRepo *repository.ContinentRepository
+	regionRepo    *repository.RegionRepository
+	cityRepo      *repository.CityRepository
+	geo           *GeoService
+	revisions     *RevisionService
+	txm           repository.TxRunner
+
+	coordinatesRequired bool
+}
+
+// PlaceOptions - необязательные настройки сервиса мест
+type PlaceOptions struct {
+	// Geo проверяет, что координаты места лежат в его стране; nil - без
+	// проверки
+	Geo *GeoService
+	// CoordinatesRequired запрещает создавать места без координат
+	CoordinatesRequired bool
 }
 
-func NewPlaceService(placeRepo *repository.PlaceRepository, countryRepo *repository.CountryRepository) *PlaceService {
+// NewPlaceService создает сервис мест над хранилищами stores; txm
+// открывает транзакции над теми же хранилищами
+func NewPlaceService(stores repository.Stores, txm repository.TxRunner, opts PlaceOptions) *PlaceService {
 	return &PlaceService{
-		placeRepo:   placeRepo,
-		countryRepo: countryRepo,
+		placeRepo:     stores.Places,
+		countryRepo:   stores.Countries,
+		continentRepo: stores.Continents,
+		regionRepo:    stores.Regions,
+		cityRepo:      stores.Cities,
+		geo:           opts.Geo,
+		revisions:     NewRevisionService(stores.Revisions),
+		txm:           txm,
+
+		coordinatesRequired: opts.CoordinatesRequired,
 	}
 }
 
-func (s *PlaceService) Create(place *entity.Place) (*entity.Place, error) {
+func (s *PlaceService) Create(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.Create")
+	defer span.End()
+
 	if place.Name == "" {
-		return nil, fmt.Errorf("name is required")
+		return nil, Invalid("name", "is required")
+	}
+	if err := s.validateCountry(ctx, place.CountryID); err != nil {
+		return nil, err
+	}
+	if err := s.validateHierarchy(ctx, place); err != nil {
+		return nil, err
+	}
+	if err := s.validateCoordinates(ctx, place); err != nil {
+		return nil, err
 	}
 	// if len(place.PhotoURLs) == 0 {
 	// 	return nil, fmt.Errorf("at least one photo is required")
 	// }
-	return s.placeRepo.Create(place)
+	return s.write(ctx, nil, entity.RevisionCreate, func(tx *PlaceService) (int, error) {
+		created, err := tx.placeRepo.Create(ctx, place)
+		if err != nil {
+			return 0, repoError("place", err)
+		}
+		return created.ID, nil
+	})
 }
 
-func (s *PlaceService) GetByID(id int) (*entity.Place, error) {
+func (s *PlaceService) GetByID(ctx context.Context, id int) (*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetByID")
+	defer span.End()
+
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid ID")
+		return nil, Invalid("id", "must be positive")
 	}
-	place, err := s.placeRepo.GetByID(id)
+	place, err := s.placeRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, repoError("place", err)
 	}
 
-	country, err := s.countryRepo.GetCountryByID(place.CountryID)
+	country, err := s.countryRepo.GetCountryByID(ctx, place.CountryID)
 	if err != nil {
-		return nil, err
+		return nil, repoError("country", err)
 	}
 	place.Country = country
 
 	return place, nil
 }
 
-func (s *PlaceService) GetAll() ([]*entity.Place, error) {
-	places, err := s.placeRepo.GetAll()
+func (s *PlaceService) GetAll(ctx context.Context, filter entity.PlaceFilter) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetAll")
+	defer span.End()
+
+	places, err := s.placeRepo.GetAll(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, repoError("place", err)
 	}
 
 	for _, place := range places {
-		country, err := s.countryRepo.GetCountryByID(place.CountryID)
+		country, err := s.countryRepo.GetCountryByID(ctx, place.CountryID)
 		if err != nil {
-			return nil, err
+			return nil, repoError("country", err)
 		}
 		place.Country = country
 	}
@@ -65,37 +122,216 @@ func (s *PlaceService) GetAll() ([]*entity.Place, error) {
 
 }
 
-func (s *PlaceService) Update(place *entity.Place) (*entity.Place, error) {
+// Update перезаписывает место; place.Version - ожидаемая текущая версия (0 - любая)
+func (s *PlaceService) Update(ctx context.Context, place *entity.Place) (*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.Update")
+	defer span.End()
+
 	if place.ID <= 0 {
-		return nil, fmt.Errorf("invalid ID")
+		return nil, Invalid("id", "must be positive")
 	}
 	if place.Name == "" {
-		return nil, fmt.Errorf("name is required")
+		return nil, Invalid("name", "is required")
+	}
+
+	// Страна места через PUT не меняется, иерархию проверяем относительно текущей
+	existing, err := s.placeRepo.GetByID(ctx, place.ID)
+	if err != nil {
+		return nil, repoError("place", err)
+	}
+	place.CountryID = existing.CountryID
+	if err := s.validateHierarchy(ctx, place); err != nil {
+		return nil, err
+	}
+	if err := s.validateCoordinates(ctx, place); err != nil {
+		return nil, err
+	}
+
+	return s.write(ctx, existing, entity.RevisionUpdate, func(tx *PlaceService) (int, error) {
+		_, err := tx.placeRepo.Update(ctx, place)
+		return place.ID, repoError("place", err)
+	})
+}
+
+// Patch применяет к месту merge patch (RFC 7396) и сохраняет изменившиеся
+// поля. В отличие от PUT, через PATCH можно перенести место в другую страну;
+// объединенное место проверяется так же, как при создании. version -
+// ожидаемая текущая версия (0 - любая).
+func (s *PlaceService) Patch(ctx context.Context, id int, version int64, patch []byte) (*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.Patch")
+	defer span.End()
+
+	if id <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	original, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repoError("place", err)
+	}
+	if version != 0 && original.Version != version {
+		return nil, repoError("place", repository.ErrVersionMismatch)
+	}
+
+	fields, err := mergepatch.Parse(patch)
+	if err != nil {
+		return nil, invalidPatch(err)
+	}
+	// Пара coordinates заменяет latitude и longitude целиком
+	base := *original
+	if _, ok := fields["coordinates"]; ok {
+		base.Latitude, base.Longitude = nil, nil
+	}
+
+	updated := &entity.Place{}
+	if err := applyMergePatch(&base, patch, updated); err != nil {
+		return nil, err
+	}
+	if updated.ID != original.ID {
+		return nil, Invalid("id", "is read-only")
+	}
+	if updated.Name == "" {
+		return nil, Invalid("name", "is required")
+	}
+	if updated.CountryID != original.CountryID {
+		if err := s.validateCountry(ctx, updated.CountryID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateHierarchy(ctx, updated); err != nil {
+		return nil, err
+	}
+	if err := s.validateCoordinates(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	return s.write(ctx, original, entity.RevisionUpdate, func(tx *PlaceService) (int, error) {
+		return original.ID, repoError("place", tx.placeRepo.Patch(ctx, original, updated))
+	})
+}
+
+// Delete удаляет место, если его версия равна version (0 - любая). В историю
+// записывается последнее состояние места.
+func (s *PlaceService) Delete(ctx context.Context, id int, version int64) error {
+	ctx, span := tracing.Start(ctx, "PlaceService.Delete")
+	defer span.End()
+
+	if id <= 0 {
+		return Invalid("id", "must be positive")
+	}
+	place, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return repoError("place", err)
+	}
+	err = s.txm.InTx(ctx, func(repo repository.Stores) error {
+		tx := s.bind(repo)
+		if err := tx.placeRepo.Delete(ctx, id, version); err != nil {
+			return repoError("place", err)
+		}
+		return tx.revisions.Record(ctx, entity.EntityPlace, id, entity.RevisionDelete, place)
+	})
+	if err != nil {
+		return err
 	}
-	return s.placeRepo.Update(place)
+	auditChange(ctx, entity.EntityPlace, id, place, nil)
+	return nil
 }
 
-func (s *PlaceService) Delete(id int) error {
+// RestoreRevision возвращает место к состоянию из ревизии rev, включая страну
+// и фото. Восстановленное место проверяется так же, как при создании, и
+// сохраняется новой ревизией. version - ожидаемая текущая версия (0 - любая).
+func (s *PlaceService) RestoreRevision(ctx context.Context, id, rev int, version int64) (*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.RestoreRevision")
+	defer span.End()
+
 	if id <= 0 {
-		return fmt.Errorf("invalid ID")
+		return nil, Invalid("id", "must be positive")
+	}
+	original, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repoError("place", err)
+	}
+	if version != 0 && original.Version != version {
+		return nil, repoError("place", repository.ErrVersionMismatch)
+	}
+
+	revision, err := s.revisions.Get(ctx, entity.EntityPlace, id, rev)
+	if err != nil {
+		return nil, err
+	}
+	restored := &entity.Place{}
+	if err := json.Unmarshal(revision.Snapshot, restored); err != nil {
+		return nil, fmt.Errorf("failed to decode place revision %d: %w", rev, err)
+	}
+	restored.ID, restored.Version = original.ID, original.Version
+
+	if err := s.validateCountry(ctx, restored.CountryID); err != nil {
+		return nil, err
+	}
+	if err := s.validateHierarchy(ctx, restored); err != nil {
+		return nil, err
+	}
+	if err := s.validateCoordinates(ctx, restored); err != nil {
+		return nil, err
 	}
-	return s.placeRepo.Delete(id)
+
+	return s.write(ctx, original, entity.RevisionRestore, func(tx *PlaceService) (int, error) {
+		return original.ID, repoError("place", tx.placeRepo.Patch(ctx, original, restored))
+	})
 }
 
-func (s *PlaceService) GetPlacesByCountry(countryID int) ([]*entity.Place, error) {
+// write выполняет изменение fn, чтение сохраненного места и запись ревизии
+// в одной транзакции: при любой ошибке не остается ни места без фото, ни
+// изменения без истории. fn возвращает ID измененного места. В журнал аудита
+// изменение попадает только после фиксации; before - место до изменения.
+func (s *PlaceService) write(ctx context.Context, before *entity.Place, action string, fn func(tx *PlaceService) (int, error)) (*entity.Place, error) {
+	var place *entity.Place
+	err := s.txm.InTx(ctx, func(repo repository.Stores) error {
+		tx := s.bind(repo)
+		id, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		if place, err = tx.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return tx.revisions.Record(ctx, entity.EntityPlace, place.ID, action, place)
+	})
+	if err != nil {
+		return nil, err
+	}
+	auditChange(ctx, entity.EntityPlace, place.ID, before, place)
+	return place, nil
+}
+
+// bind возвращает копию сервиса, работающую с хранилищами транзакции repo
+func (s *PlaceService) bind(repo repository.Stores) *PlaceService {
+	tx := *s
+	tx.placeRepo = repo.Places
+	tx.countryRepo = repo.Countries
+	tx.continentRepo = repo.Continents
+	tx.regionRepo = repo.Regions
+	tx.cityRepo = repo.Cities
+	tx.revisions = NewRevisionService(repo.Revisions)
+	return &tx
+}
+
+func (s *PlaceService) GetPlacesByCountry(ctx context.Context, countryID int) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetPlacesByCountry")
+	defer span.End()
+
 	// Проверяем существование страны
-	// if _, err := s.repo.GetCountryByID(countryID); err != nil {
+	// if _, err := s.repo.GetCountryByID(ctx, countryID); err != nil {
 	// 	return nil, fmt.Errorf("country not found")
 	// }
-	places, err := s.placeRepo.GetPlacesByCountryID(countryID)
+	places, err := s.placeRepo.GetPlacesByCountryID(ctx, countryID)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, place := range places {
-		country, err := s.countryRepo.GetCountryByID(place.CountryID)
+		country, err := s.countryRepo.GetCountryByID(ctx, place.CountryID)
 		if err != nil {
-			return nil, err
+			return nil, repoError("country", err)
 		}
 		place.Country = country
 	}
@@ -103,8 +339,11 @@ func (s *PlaceService) GetPlacesByCountry(countryID int) ([]*entity.Place, error
 	return places, nil
 }
 
-func (s *PlaceService) SearchPlaces(query string, limit int) ([]*entity.Place, error) {
-	places, err := s.placeRepo.SearchByName(query, limit)
+func (s *PlaceService) SearchPlaces(ctx context.Context, query string, limit int) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.SearchPlaces")
+	defer span.End()
+
+	places, err := s.placeRepo.SearchByName(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -113,12 +352,172 @@ func (s *PlaceService) SearchPlaces(query string, limit int) ([]*entity.Place, e
 		return []*entity.Place{}, nil
 	}
 	for _, place := range places {
-		country, err := s.countryRepo.GetCountryByID(place.CountryID)
+		country, err := s.countryRepo.GetCountryByID(ctx, place.CountryID)
 		if err != nil {
-			return nil, err
+			return nil, repoError("country", err)
 		}
 		place.Country = country
 	}
 
 	return places, nil
 }
+
+func (s *PlaceService) GetPlacesByContinent(ctx context.Context, code string) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetPlacesByContinent")
+	defer span.End()
+
+	continent, err := s.continentRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, repoError("continent", err)
+	}
+	return s.GetAll(ctx, entity.PlaceFilter{ContinentCode: continent.Code})
+}
+
+func (s *PlaceService) GetPlacesByRegion(ctx context.Context, regionID int) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetPlacesByRegion")
+	defer span.End()
+
+	if _, err := s.regionRepo.GetByID(ctx, regionID); err != nil {
+		return nil, repoError("region", err)
+	}
+	return s.GetAll(ctx, entity.PlaceFilter{RegionID: regionID})
+}
+
+func (s *PlaceService) GetPlacesByCity(ctx context.Context, cityID int) ([]*entity.Place, error) {
+	ctx, span := tracing.Start(ctx, "PlaceService.GetPlacesByCity")
+	defer span.End()
+
+	if _, err := s.cityRepo.GetByID(ctx, cityID); err != nil {
+		return nil, repoError("city", err)
+	}
+	return s.GetAll(ctx, entity.PlaceFilter{CityID: cityID})
+}
+
+func (s *PlaceService) validateCountry(ctx context.Context, countryID int) error {
+	_, err := s.countryRepo.GetCountryByID(ctx, countryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Invalid("country_id", "country not found")
+	}
+	return err
+}
+
+// validateHierarchy проверяет, что регион и город места лежат в его стране
+// и что город относится к указанному региону
+func (s *PlaceService) validateHierarchy(ctx context.Context, place *entity.Place) error {
+	var region *entity.Region
+	if place.RegionID != nil {
+		r, err := s.regionRepo.GetByID(ctx, *place.RegionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return Invalid("region_id", "region not found")
+		}
+		if err != nil {
+			return err
+		}
+		if r.CountryID != place.CountryID {
+			return Invalid("region_id", "region belongs to another country")
+		}
+		region = r
+	}
+
+	if place.CityID != nil {
+		city, err := s.cityRepo.GetByID(ctx, *place.CityID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return Invalid("city_id", "city not found")
+		}
+		if err != nil {
+			return err
+		}
+		if city.CountryID != place.CountryID {
+			return Invalid("city_id", "city belongs to another country")
+		}
+		if region != nil && city.RegionID != nil && *city.RegionID != region.ID {
+			return Invalid("city_id", "city belongs to another region")
+		}
+		// Регион можно не указывать явно - он берется из города
+		if region == nil && city.RegionID != nil {
+			place.RegionID = city.RegionID
+		}
+	}
+
+	return nil
+}
+
+// validateCoordinates проверяет наличие и диапазон координат, округляет их
+// до точности колонок (6 знаков, ~0.1 м) и сверяет точку с границами страны
+func (s *PlaceService) validateCoordinates(ctx context.Context, place *entity.Place) error {
+	verr := &Validation{}
+
+	if place.Latitude == nil || place.Longitude == nil {
+		switch {
+		case place.Latitude != nil:
+			verr.Add("longitude", "is required when latitude is set")
+		case place.Longitude != nil:
+			verr.Add("latitude", "is required when longitude is set")
+		case s.coordinatesRequired:
+			verr.Add("latitude", "is required")
+			verr.Add("longitude", "is required")
+		}
+		return verr.Err()
+	}
+
+	lat, lon := *place.Latitude, *place.Longitude
+	switch {
+	case math.IsNaN(lat) || math.IsInf(lat, 0):
+		verr.Add("latitude", "must be a finite number")
+	case lat < -90 || lat > 90:
+		if math.Abs(lon) <= 90 && math.Abs(lat) <= 180 {
+			verr.Add("latitude", fmt.Sprintf("%g is out of range [-90, 90]; latitude and longitude appear to be swapped", lat))
+		} else {
+			verr.Add("latitude", fmt.Sprintf("%g is out of range [-90, 90]", lat))
+		}
+	}
+	switch {
+	case math.IsNaN(lon) || math.IsInf(lon, 0):
+		verr.Add("longitude", "must be a finite number")
+	case lon < -180 || lon > 180:
+		verr.Add("longitude", fmt.Sprintf("%g is out of range [-180, 180]", lon))
+	}
+	if lat == 0 && lon == 0 {
+		verr.Add("coordinates", "(0, 0) is not a valid place location; omit coordinates if they are unknown")
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	lat, lon = roundCoordinate(lat), roundCoordinate(lon)
+	place.Latitude, place.Longitude = &lat, &lon
+
+	return s.validateLocation(ctx, place.CountryID, lat, lon)
+}
+
+// validateLocation проверяет, что точка лежит в стране места. Если не лежит,
+// но лежит после перестановки широты и долготы, сообщает о перепутанных полях.
+func (s *PlaceService) validateLocation(ctx context.Context, countryID int, lat, lon float64) error {
+	if !s.geo.Enabled() {
+		return nil
+	}
+
+	country, err := s.countryRepo.GetCountryByID(ctx, countryID)
+	if err != nil {
+		return repoError("country", err)
+	}
+
+	placementErr := s.geo.CheckPlacement(country, lat, lon)
+	if placementErr == nil {
+		return nil
+	}
+
+	verr := &Validation{}
+	if math.Abs(lon) <= 90 && s.geo.CheckPlacement(country, lon, lat) == nil {
+		verr.Add("latitude", "latitude and longitude appear to be swapped")
+		verr.Add("longitude", "latitude and longitude appear to be swapped")
+	} else {
+		verr.Add("coordinates", placementErr.Error())
+	}
+	return verr.Err()
+}
+
+// roundCoordinate округляет до 6 знаков - точности колонок DECIMAL(10, 6)
+func roundCoordinate(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/internal/repository/memory"
+	"github.com/ShekleinAleksey/top-places/internal/service"
+	"github.com/ShekleinAleksey/top-places/pkg/geo"
+)
+
+type memoryServices struct {
+	places    *service.PlaceService
+	countries *service.CountryService
+	revisions *service.RevisionService
+	trash     *service.TrashService
+}
+
+// newMemoryServices собирает сервисы стран и мест над хранилищами в памяти
+func newMemoryServices() memoryServices {
+	stores, txm := memory.Stores(memory.NewDB())
+	return newServices(stores, txm)
+}
+
+func newServices(stores repository.Stores, txm repository.TxRunner) memoryServices {
+	revisions := service.NewRevisionService(stores.Revisions)
+	return memoryServices{
+		places:    service.NewPlaceService(stores, txm, service.PlaceOptions{}),
+		countries: service.NewCountryService(stores.Countries, nil, stores.Places, revisions, txm),
+		revisions: revisions,
+		trash:     service.NewTrashService(stores.Countries, stores.Places, revisions, txm, 0),
+	}
+}
+
+func TestPlaceServiceMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newMemoryServices()
+
+	countryID, err := s.countries.AddCountry(ctx, &entity.Country{Name: "Georgia", Capital: "Tbilisi"})
+	if err != nil {
+		t.Fatalf("AddCountry: %v", err)
+	}
+
+	created, err := s.places.Create(ctx, &entity.Place{Name: "Gergeti Trinity Church", CountryID: countryID})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == 0 || created.Version != 1 || created.Country.Name != "Georgia" {
+		t.Fatalf("Create returned %+v", created)
+	}
+
+	created.Description = "14th century church"
+	updated, err := s.places.Update(ctx, created)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Version != 2 || updated.Description != created.Description {
+		t.Fatalf("Update returned %+v", updated)
+	}
+
+	history, err := s.revisions.List(ctx, entity.EntityPlace, created.ID)
+	if err != nil {
+		t.Fatalf("List revisions: %v", err)
+	}
+	if len(history) != 2 || history[0].Action != entity.RevisionUpdate || history[1].Action != entity.RevisionCreate {
+		t.Fatalf("revisions = %+v, want update and create", history)
+	}
+}
+
+func TestAuditCreateHasNoBefore(t *testing.T) {
+	ctx, trail := service.WithAuditTrail(context.Background())
+	s := newMemoryServices()
+
+	countryID, err := s.countries.AddCountry(ctx, &entity.Country{Name: "Georgia", Capital: "Tbilisi"})
+	if err != nil {
+		t.Fatalf("AddCountry: %v", err)
+	}
+	if _, err := s.places.Create(ctx, &entity.Place{Name: "Narikala", CountryID: countryID}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	changes := trail.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("audit changes = %d, want 2", len(changes))
+	}
+	for _, change := range changes {
+		if change.Before != nil || change.After == nil {
+			t.Errorf("%s %d: before = %s, after = %s; want no before", change.EntityType, change.EntityID, change.Before, change.After)
+		}
+	}
+}
+
+func TestPlaceCoordinateValidation(t *testing.T) {
+	ctx := context.Background()
+	// Грузия упрощена до прямоугольника: широта 41-44, долгота 40-47
+	index, err := geo.Load([]byte(`{"type":"FeatureCollection","features":[{"type":"Feature",
+		"properties":{"ISO_A2":"GE"},
+		"geometry":{"type":"Polygon","coordinates":[[[40,41],[47,41],[47,44],[40,44],[40,41]]]}}]}`))
+	if err != nil {
+		t.Fatalf("geo.Load: %v", err)
+	}
+	stores, txm := memory.Stores(memory.NewDB())
+	countries := service.NewCountryService(stores.Countries, nil, stores.Places, service.NewRevisionService(stores.Revisions), txm)
+	places := service.NewPlaceService(stores, txm, service.PlaceOptions{
+		Geo: service.NewGeoService(index, 10, stores.Countries),
+	})
+	countryID, err := countries.AddCountry(ctx, &entity.Country{Name: "Georgia", Capital: "Tbilisi", ISO2: "GE"})
+	if err != nil {
+		t.Fatalf("AddCountry: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		field    string // "" - место создается
+		message  string
+	}{
+		{"inside", 41.7167, 44.7833, "", ""},
+		{"latitude out of range", 95, 120, "latitude", "out of range [-90, 90]"},
+		{"latitude out of range, swapped", 95, 42, "latitude", "appear to be swapped"},
+		{"longitude out of range", 42, 181, "longitude", "out of range [-180, 180]"},
+		{"zero point", 0, 0, "coordinates", "not a valid place location"},
+		{"swapped inside country", 44.7833, 41.7167, "latitude", "appear to be swapped"},
+		{"outside country", 10, 10, "coordinates", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lon := tt.lat, tt.lon
+			_, err := places.Create(ctx, &entity.Place{Name: tt.name, CountryID: countryID, Latitude: &lat, Longitude: &lon})
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+				return
+			}
+
+			serr, ok := service.AsError(err)
+			if !ok || serr.Kind != service.ErrValidation {
+				t.Fatalf("Create error = %v, want validation error", err)
+			}
+			for _, f := range serr.Fields {
+				if f.Field == tt.field && strings.Contains(f.Message, tt.message) {
+					return
+				}
+			}
+			t.Fatalf("fields = %+v, want %s: ...%s...", serr.Fields, tt.field, tt.message)
+		})
+	}
+}
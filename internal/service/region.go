@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+type RegionService struct {
+	repo        *repository.RegionRepository
+	countryRepo repository.CountryStore
+}
+
+func NewRegionService(repo *repository.RegionRepository, countryRepo repository.CountryStore) *RegionService {
+	return &RegionService{repo: repo, countryRepo: countryRepo}
+}
+
+func (s *RegionService) Create(ctx context.Context, region *entity.Region) (*entity.Region, error) {
+	ctx, span := tracing.Start(ctx, "RegionService.Create")
+	defer span.End()
+
+	if err := s.validate(ctx, region); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(ctx, region)
+	if err != nil {
+		return nil, repoError("region", err)
+	}
+	auditChange(ctx, entity.EntityRegion, created.ID, nil, created)
+	return created, nil
+}
+
+func (s *RegionService) GetByID(ctx context.Context, id int) (*entity.Region, error) {
+	ctx, span := tracing.Start(ctx, "RegionService.GetByID")
+	defer span.End()
+
+	if id <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	region, err := s.repo.GetByID(ctx, id)
+	return region, repoError("region", err)
+}
+
+func (s *RegionService) GetAll(ctx context.Context, countryID int) ([]entity.Region, error) {
+	ctx, span := tracing.Start(ctx, "RegionService.GetAll")
+	defer span.End()
+
+	regions, err := s.repo.GetAll(ctx, countryID)
+	return regions, repoError("region", err)
+}
+
+// GetByCountry возвращает регионы страны, предварительно проверив, что она существует
+func (s *RegionService) GetByCountry(ctx context.Context, countryID int) ([]entity.Region, error) {
+	ctx, span := tracing.Start(ctx, "RegionService.GetByCountry")
+	defer span.End()
+
+	if _, err := s.countryRepo.GetCountryByID(ctx, countryID); err != nil {
+		return nil, repoError("country", err)
+	}
+	regions, err := s.repo.GetAll(ctx, countryID)
+	return regions, repoError("region", err)
+}
+
+func (s *RegionService) Update(ctx context.Context, region *entity.Region) (*entity.Region, error) {
+	ctx, span := tracing.Start(ctx, "RegionService.Update")
+	defer span.End()
+
+	if region.ID <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	if err := s.validate(ctx, region); err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetByID(ctx, region.ID)
+	if err != nil {
+		return nil, repoError("region", err)
+	}
+	updated, err := s.repo.Update(ctx, region)
+	if err != nil {
+		return nil, repoError("region", err)
+	}
+	auditChange(ctx, entity.EntityRegion, updated.ID, before, updated)
+	return updated, nil
+}
+
+func (s *RegionService) Delete(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "RegionService.Delete")
+	defer span.End()
+
+	if id <= 0 {
+		return Invalid("id", "must be positive")
+	}
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return repoError("region", err)
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return repoError("region", err)
+	}
+	auditChange(ctx, entity.EntityRegion, id, before, nil)
+	return nil
+}
+
+func (s *RegionService) validate(ctx context.Context, region *entity.Region) error {
+	if region.Name == "" {
+		return Invalid("name", "is required")
+	}
+	if _, err := s.countryRepo.GetCountryByID(ctx, region.CountryID); errors.Is(err, repository.ErrNotFound) {
+		return Invalid("country_id", "country not found")
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
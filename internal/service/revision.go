@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+// AnonymousActor записывается в историю, если пользователь не представился
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте имя пользователя, от которого идут изменения
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom возвращает пользователя из контекста или AnonymousActor
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+type claimedActorKey struct{}
+
+// WithClaimedActor сохраняет в контексте имя, которым представился
+// непроверенный пользователь; оно попадает только в журнал аудита
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, claimedActorKey{}, actor)
+}
+
+// ClaimedActorFrom возвращает имя из WithClaimedActor или пустую строку
+func ClaimedActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(claimedActorKey{}).(string)
+	return actor
+}
+
+// snapshotOmit - поля, не входящие в снимок: служебные (меняются при каждой
+// записи) и вложенная страна места, у которой своя история
+var snapshotOmit = []string{"version", "updated_at", "country"}
+
+type RevisionService struct {
+	repo repository.RevisionStore
+}
+
+func NewRevisionService(repo repository.RevisionStore) *RevisionService {
+	return &RevisionService{repo: repo}
+}
+
+// Record сохраняет ревизию с полным снимком v от имени пользователя из ctx
+func (s *RevisionService) Record(ctx context.Context, entityType string, entityID int, action string, v interface{}) error {
+	ctx, span := tracing.Start(ctx, "RevisionService.Record")
+	defer span.End()
+
+	snapshot, err := makeSnapshot(v)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Create(ctx, &entity.Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      ActorFrom(ctx),
+		Snapshot:   snapshot,
+	})
+	return err
+}
+
+func (s *RevisionService) List(ctx context.Context, entityType string, entityID int) ([]entity.Revision, error) {
+	ctx, span := tracing.Start(ctx, "RevisionService.List")
+	defer span.End()
+
+	if entityID <= 0 {
+		return nil, Invalid("id", "must be positive")
+	}
+	revisions, err := s.repo.List(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, NotFound(entityType+"_not_found", "%s %d has no revisions", entityType, entityID)
+	}
+	return revisions, nil
+}
+
+func (s *RevisionService) Get(ctx context.Context, entityType string, entityID, revision int) (*entity.Revision, error) {
+	ctx, span := tracing.Start(ctx, "RevisionService.Get")
+	defer span.End()
+
+	if revision <= 0 {
+		return nil, Invalid("rev", "must be positive")
+	}
+	rev, err := s.repo.Get(ctx, entityType, entityID, revision)
+	return rev, repoError("revision", err)
+}
+
+// Diff возвращает поля верхнего уровня, различающиеся в ревизиях from и to
+func (s *RevisionService) Diff(ctx context.Context, entityType string, entityID, from, to int) (*entity.RevisionDiff, error) {
+	ctx, span := tracing.Start(ctx, "RevisionService.Diff")
+	defer span.End()
+
+	verr := &Validation{}
+	if from <= 0 {
+		verr.Add("from", "must be positive")
+	}
+	if to <= 0 {
+		verr.Add("to", "must be positive")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	fromRev, err := s.Get(ctx, entityType, entityID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.Get(ctx, entityType, entityID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffSnapshots(fromRev.Snapshot, toRev.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.RevisionDiff{
+		EntityType: entityType,
+		EntityID:   entityID,
+		From:       from,
+		To:         to,
+		Changes:    changes,
+	}, nil
+}
+
+// makeSnapshot кодирует сущность в JSON без полей snapshotOmit
+func makeSnapshot(v interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	for _, name := range snapshotOmit {
+		delete(fields, name)
+	}
+	return json.Marshal(fields)
+}
+
+func diffSnapshots(from, to json.RawMessage) ([]entity.FieldChange, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(from, &before); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if err := json.Unmarshal(to, &after); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	fields := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		fields[name] = true
+	}
+	for name := range after {
+		fields[name] = true
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := []entity.FieldChange{}
+	for _, name := range names {
+		if !jsonEqual(before[name], after[name]) {
+			changes = append(changes, entity.FieldChange{Field: name, From: before[name], To: after[name]})
+		}
+	}
+	return changes, nil
+}
+
+// jsonEqual сравнивает значения без учета форматирования: JSONB хранит
+// снимки в нормализованном виде
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
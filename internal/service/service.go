@@ -1,15 +1,90 @@
 package service
 
-import "github.com/ShekleinAleksey/top-places/internal/repository"
+import (
+	"context"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/geo"
+	"github.com/ShekleinAleksey/top-places/pkg/wikidata"
+)
 
 type Service struct {
-	CountryService *CountryService
-	PlaceService   *PlaceService
+	CountryService     *CountryService
+	PlaceService       *PlaceService
+	EnrichmentService  *EnrichmentService
+	ContinentService   *ContinentService
+	RegionService      *RegionService
+	CityService        *CityService
+	GeoService         *GeoService
+	RevisionService    *RevisionService
+	TrashService       *TrashService
+	AuditService       *AuditService
+	IdempotencyService *IdempotencyService
+	StatsService       *StatsService
+	HealthService      *HealthService
+}
+
+// Options - настройки сервисов; нулевые значения отключают обогащение,
+// проверку координат, очистку корзины и хранение ключей идемпотентности
+type Options struct {
+	// WikiSource - источник данных обогащения стран; nil - обогащение выключено
+	WikiSource wikidata.Source
+	// WikiLang - язык, на котором берутся значения Wikidata
+	WikiLang string
+	// GeoIndex - границы стран для обратного геокодирования и проверки координат
+	GeoIndex *geo.Index
+	// BorderToleranceKm - допустимое удаление места от границы его страны
+	BorderToleranceKm float64
+	// CoordinatesRequired запрещает создавать места без координат
+	CoordinatesRequired bool
+	// TrashRetention - срок хранения удаленных записей в корзине
+	TrashRetention time.Duration
+	// IdempotencyTTL - срок хранения ответов по ключам идемпотентности
+	IdempotencyTTL time.Duration
+	// SchemaVersion - номер последней миграции, которую ожидает сервис
+	SchemaVersion int
 }
 
-func NewService(repo *repository.Repository) *Service {
+func NewService(repo *repository.Repository, opts Options) *Service {
+	geoService := NewGeoService(opts.GeoIndex, opts.BorderToleranceKm, repo.CountryRepository)
+	revisionService := NewRevisionService(repo.RevisionRepository)
 	return &Service{
-		CountryService: NewCountryService(*repo.CountryRepository),
-		PlaceService:   NewPlaceService(*&repo.PlaceRepository, *&repo.CountryRepository),
+		CountryService: NewCountryService(repo.CountryRepository, repo.ContinentRepository, repo.PlaceRepository, revisionService, repo.TxManager),
+		PlaceService: NewPlaceService(repo.Stores(), repo.TxManager, PlaceOptions{
+			Geo:                 geoService,
+			CoordinatesRequired: opts.CoordinatesRequired,
+		}),
+		EnrichmentService:  NewEnrichmentService(opts.WikiSource, opts.WikiLang, repo.CountryRepository, repo.EnrichmentRepository, revisionService, repo.TxManager),
+		ContinentService:   NewContinentService(repo.ContinentRepository, repo.CountryRepository),
+		RegionService:      NewRegionService(repo.RegionRepository, repo.CountryRepository),
+		CityService:        NewCityService(repo.CityRepository, repo.RegionRepository, repo.CountryRepository),
+		GeoService:         geoService,
+		RevisionService:    revisionService,
+		TrashService:       NewTrashService(repo.CountryRepository, repo.PlaceRepository, revisionService, repo.TxManager, opts.TrashRetention),
+		AuditService:       NewAuditService(repo.AuditRepository),
+		IdempotencyService: NewIdempotencyService(repo.IdempotencyRepository, opts.IdempotencyTTL),
+		StatsService:       NewStatsService(repo.StatsRepository),
+		HealthService: NewHealthService(repo.HealthRepository, opts.SchemaVersion, entity.ServiceConfig{
+			EnrichmentEnabled:   opts.WikiSource != nil,
+			EnrichmentLanguage:  opts.WikiLang,
+			GeoValidation:       opts.GeoIndex != nil,
+			CoordinatesRequired: opts.CoordinatesRequired,
+			TrashRetention:      opts.TrashRetention.String(),
+			IdempotencyTTL:      opts.IdempotencyTTL.String(),
+		}),
 	}
 }
+
+// ReadOnly помечает ctx как контекст запроса, который ничего не изменяет:
+// его чтения могут выполняться на репликах БД
+func ReadOnly(ctx context.Context) context.Context {
+	return repository.WithReplicaReads(ctx)
+}
+
+// ReadPrimary направляет чтения ctx в основную БД, даже если он помечен
+// ReadOnly
+func ReadPrimary(ctx context.Context) context.Context {
+	return repository.WithPrimaryReads(ctx)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+type StatsService struct {
+	repo *repository.StatsRepository
+}
+
+func NewStatsService(repo *repository.StatsRepository) *StatsService {
+	return &StatsService{repo: repo}
+}
+
+func (s *StatsService) Get(ctx context.Context) (*entity.Stats, error) {
+	ctx, span := tracing.Start(ctx, "StatsService.Get")
+	defer span.End()
+
+	stats, err := s.repo.Get(ctx)
+	return stats, repoError("stats", err)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ShekleinAleksey/top-places/internal/entity"
+	"github.com/ShekleinAleksey/top-places/internal/repository"
+	"github.com/ShekleinAleksey/top-places/pkg/tracing"
+)
+
+// TrashService управляет удаленными странами и местами: их можно
+// восстановить, пока не истечет срок хранения, после чего очистка удаляет
+// их окончательно
+type TrashService struct {
+	countryRepo repository.CountryStore
+	placeRepo   repository.PlaceStore
+	revisions   *RevisionService
+	txm         repository.TxRunner
+	// retention - срок хранения в корзине; 0 - без очистки
+	retention time.Duration
+}
+
+func NewTrashService(countryRepo repository.CountryStore, placeRepo repository.PlaceStore, revisions *RevisionService, txm repository.TxRunner, retention time.Duration) *TrashService {
+	return &TrashService{
+		countryRepo: countryRepo,
+		placeRepo:   placeRepo,
+		revisions:   revisions,
+		txm:         txm,
+		retention:   retention,
+	}
+}
+
+// Retention возвращает срок хранения удаленных записей
+func (s *TrashService) Retention() time.Duration {
+	return s.retention
+}
+
+// List возвращает содержимое корзины; entityType ограничивает выборку
+// странами или местами, пустой - все записи
+func (s *TrashService) List(ctx context.Context, entityType string) ([]entity.TrashItem, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.List")
+	defer span.End()
+
+	if entityType != "" && entityType != entity.EntityCountry && entityType != entity.EntityPlace {
+		return nil, Invalid("type", "must be country or place")
+	}
+
+	items := []entity.TrashItem{}
+	if entityType != entity.EntityPlace {
+		countries, err := s.countryRepo.ListDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range countries {
+			items = append(items, s.item(entity.EntityCountry, c.ID, c.Name, 0, *c.DeletedAt))
+		}
+	}
+	if entityType != entity.EntityCountry {
+		places, err := s.placeRepo.ListDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range places {
+			items = append(items, s.item(entity.EntityPlace, p.ID, p.Name, p.CountryID, *p.DeletedAt))
+		}
+	}
+	return items, nil
+}
+
+// Restore возвращает запись из корзины и записывает восстановление в историю.
+// Страна восстанавливается вместе с местами, удаленными вместе с ней; место
+// удаленной страны восстановить нельзя.
+func (s *TrashService) Restore(ctx context.Context, entityType string, id int) error {
+	ctx, span := tracing.Start(ctx, "TrashService.Restore")
+	defer span.End()
+
+	var restored []restoredEntity
+	err := s.txm.InTx(ctx, func(repo repository.Stores) (err error) {
+		restored, err = s.bind(repo).restore(ctx, entityType, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range restored {
+		auditChange(ctx, r.entityType, r.id, nil, r.value)
+	}
+	return nil
+}
+
+// restoredEntity - запись, возвращенная из корзины, для журнала аудита
+type restoredEntity struct {
+	entityType string
+	id         int
+	value      interface{}
+}
+
+func (s *TrashService) restore(ctx context.Context, entityType string, id int) ([]restoredEntity, error) {
+	switch entityType {
+	case entity.EntityCountry:
+		placeIDs, err := s.countryRepo.Restore(ctx, id)
+		if err != nil {
+			return nil, repoError("country", err)
+		}
+		country, err := s.countryRepo.GetCountryByID(ctx, id)
+		if err != nil {
+			return nil, repoError("country", err)
+		}
+		if err := s.revisions.Record(ctx, entity.EntityCountry, id, entity.RevisionRestore, country); err != nil {
+			return nil, err
+		}
+		restored := []restoredEntity{{entity.EntityCountry, id, country}}
+		for _, placeID := range placeIDs {
+			place, err := s.recordPlace(ctx, placeID)
+			if err != nil {
+				return nil, err
+			}
+			restored = append(restored, restoredEntity{entity.EntityPlace, placeID, place})
+		}
+		return restored, nil
+
+	case entity.EntityPlace:
+		err := s.placeRepo.Restore(ctx, id)
+		if errors.Is(err, repository.ErrReference) {
+			return nil, Conflict("country_deleted", "place %d belongs to a deleted country; restore the country first", id)
+		}
+		if err != nil {
+			return nil, repoError("place", err)
+		}
+		place, err := s.recordPlace(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []restoredEntity{{entity.EntityPlace, id, place}}, nil
+
+	default:
+		return nil, Invalid("type", "must be country or place")
+	}
+}
+
+// Purge окончательно удаляет записи, срок хранения которых истек. Места
+// удаляются первыми, страны - вместе с оставшимися у них местами.
+func (s *TrashService) Purge(ctx context.Context) (*entity.PurgeReport, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.Purge")
+	defer span.End()
+
+	report := &entity.PurgeReport{}
+	if s.retention <= 0 {
+		return report, nil
+	}
+
+	before := time.Now().Add(-s.retention)
+	places, err := s.placeRepo.Purge(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	countries, err := s.countryRepo.Purge(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Places, report.Countries = places, countries
+	return report, nil
+}
+
+func (s *TrashService) recordPlace(ctx context.Context, id int) (*entity.Place, error) {
+	place, err := s.placeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repoError("place", err)
+	}
+	if err := s.revisions.Record(ctx, entity.EntityPlace, id, entity.RevisionRestore, place); err != nil {
+		return nil, err
+	}
+	return place, nil
+}
+
+// bind возвращает копию сервиса, работающую с хранилищами транзакции repo
+func (s *TrashService) bind(repo repository.Stores) *TrashService {
+	tx := *s
+	tx.countryRepo = repo.Countries
+	tx.placeRepo = repo.Places
+	tx.revisions = NewRevisionService(repo.Revisions)
+	return &tx
+}
+
+func (s *TrashService) item(entityType string, id int, name string, countryID int, deletedAt time.Time) entity.TrashItem {
+	item := entity.TrashItem{
+		Type:      entityType,
+		ID:        id,
+		Name:      name,
+		CountryID: countryID,
+		DeletedAt: deletedAt,
+	}
+	if s.retention > 0 {
+		item.PurgeAt = deletedAt.Add(s.retention)
+	}
+	return item
+}
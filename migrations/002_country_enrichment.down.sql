@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS country_enrichments;
+
+ALTER TABLE countries
+    DROP COLUMN IF EXISTS wikidata_id,
+    DROP COLUMN IF EXISTS population,
+    DROP COLUMN IF EXISTS area,
+    DROP COLUMN IF EXISTS flag_url;
@@ -0,0 +1,23 @@
+-- Поля стран, заполняемые из Wikidata
+ALTER TABLE countries
+    ADD COLUMN IF NOT EXISTS wikidata_id VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS population BIGINT,
+    ADD COLUMN IF NOT EXISTS area DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS flag_url VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Предложения по обогащению стран (и атрибуция примененных значений)
+CREATE TABLE IF NOT EXISTS country_enrichments (
+    id SERIAL PRIMARY KEY,
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    field VARCHAR(50) NOT NULL,
+    current_value TEXT NOT NULL DEFAULT '',
+    proposed_value TEXT NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    source_ref VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_country_enrichments_country_field ON country_enrichments(country_id, field);
+CREATE INDEX IF NOT EXISTS idx_country_enrichments_status ON country_enrichments(status);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS country_currencies;
+DROP TABLE IF EXISTS country_languages;
+
+DROP INDEX IF EXISTS idx_countries_iso3;
+DROP INDEX IF EXISTS idx_countries_iso2;
+
+ALTER TABLE countries
+    DROP COLUMN IF EXISTS iso2,
+    DROP COLUMN IF EXISTS iso3;
+
+DROP TABLE IF EXISTS iso_languages;
+DROP TABLE IF EXISTS iso_currencies;
+DROP TABLE IF EXISTS iso_countries;
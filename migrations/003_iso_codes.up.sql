@@ -0,0 +1,1011 @@
+-- Справочники ISO (совпадают со встроенными в pkg/iso/data)
+CREATE TABLE IF NOT EXISTS iso_countries (
+    alpha2 CHAR(2) PRIMARY KEY,
+    alpha3 CHAR(3) NOT NULL UNIQUE,
+    numeric_code CHAR(3) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    official_name VARCHAR(150) NOT NULL DEFAULT '',
+    common_name VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS iso_currencies (
+    code CHAR(3) PRIMARY KEY,
+    numeric_code CHAR(3) NOT NULL,
+    name VARCHAR(100) NOT NULL
+);
+
+-- code - ISO 639-1, если есть, иначе ISO 639-2/T
+CREATE TABLE IF NOT EXISTS iso_languages (
+    code VARCHAR(3) PRIMARY KEY,
+    alpha3 CHAR(3) NOT NULL UNIQUE,
+    bibliographic CHAR(3),
+    name VARCHAR(150) NOT NULL
+);
+
+INSERT INTO iso_countries (alpha2, alpha3, numeric_code, name, official_name, common_name) VALUES
+    ('AD', 'AND', '020', 'Andorra', 'Principality of Andorra', ''),
+    ('AE', 'ARE', '784', 'United Arab Emirates', '', ''),
+    ('AF', 'AFG', '004', 'Afghanistan', 'Islamic Republic of Afghanistan', ''),
+    ('AG', 'ATG', '028', 'Antigua and Barbuda', '', ''),
+    ('AI', 'AIA', '660', 'Anguilla', '', ''),
+    ('AL', 'ALB', '008', 'Albania', 'Republic of Albania', ''),
+    ('AM', 'ARM', '051', 'Armenia', 'Republic of Armenia', ''),
+    ('AO', 'AGO', '024', 'Angola', 'Republic of Angola', ''),
+    ('AQ', 'ATA', '010', 'Antarctica', '', ''),
+    ('AR', 'ARG', '032', 'Argentina', 'Argentine Republic', ''),
+    ('AS', 'ASM', '016', 'American Samoa', '', ''),
+    ('AT', 'AUT', '040', 'Austria', 'Republic of Austria', ''),
+    ('AU', 'AUS', '036', 'Australia', '', ''),
+    ('AW', 'ABW', '533', 'Aruba', '', ''),
+    ('AX', 'ALA', '248', 'Åland Islands', '', ''),
+    ('AZ', 'AZE', '031', 'Azerbaijan', 'Republic of Azerbaijan', ''),
+    ('BA', 'BIH', '070', 'Bosnia and Herzegovina', 'Republic of Bosnia and Herzegovina', ''),
+    ('BB', 'BRB', '052', 'Barbados', '', ''),
+    ('BD', 'BGD', '050', 'Bangladesh', 'People''s Republic of Bangladesh', ''),
+    ('BE', 'BEL', '056', 'Belgium', 'Kingdom of Belgium', ''),
+    ('BF', 'BFA', '854', 'Burkina Faso', '', ''),
+    ('BG', 'BGR', '100', 'Bulgaria', 'Republic of Bulgaria', ''),
+    ('BH', 'BHR', '048', 'Bahrain', 'Kingdom of Bahrain', ''),
+    ('BI', 'BDI', '108', 'Burundi', 'Republic of Burundi', ''),
+    ('BJ', 'BEN', '204', 'Benin', 'Republic of Benin', ''),
+    ('BL', 'BLM', '652', 'Saint Barthélemy', '', ''),
+    ('BM', 'BMU', '060', 'Bermuda', '', ''),
+    ('BN', 'BRN', '096', 'Brunei Darussalam', '', ''),
+    ('BO', 'BOL', '068', 'Bolivia, Plurinational State of', 'Plurinational State of Bolivia', 'Bolivia'),
+    ('BQ', 'BES', '535', 'Bonaire, Sint Eustatius and Saba', 'Bonaire, Sint Eustatius and Saba', ''),
+    ('BR', 'BRA', '076', 'Brazil', 'Federative Republic of Brazil', ''),
+    ('BS', 'BHS', '044', 'Bahamas', 'Commonwealth of the Bahamas', ''),
+    ('BT', 'BTN', '064', 'Bhutan', 'Kingdom of Bhutan', ''),
+    ('BV', 'BVT', '074', 'Bouvet Island', '', ''),
+    ('BW', 'BWA', '072', 'Botswana', 'Republic of Botswana', ''),
+    ('BY', 'BLR', '112', 'Belarus', 'Republic of Belarus', ''),
+    ('BZ', 'BLZ', '084', 'Belize', '', ''),
+    ('CA', 'CAN', '124', 'Canada', '', ''),
+    ('CC', 'CCK', '166', 'Cocos (Keeling) Islands', '', ''),
+    ('CD', 'COD', '180', 'Congo, The Democratic Republic of the', '', ''),
+    ('CF', 'CAF', '140', 'Central African Republic', '', ''),
+    ('CG', 'COG', '178', 'Congo', 'Republic of the Congo', ''),
+    ('CH', 'CHE', '756', 'Switzerland', 'Swiss Confederation', ''),
+    ('CI', 'CIV', '384', 'Côte d''Ivoire', 'Republic of Côte d''Ivoire', ''),
+    ('CK', 'COK', '184', 'Cook Islands', '', ''),
+    ('CL', 'CHL', '152', 'Chile', 'Republic of Chile', ''),
+    ('CM', 'CMR', '120', 'Cameroon', 'Republic of Cameroon', ''),
+    ('CN', 'CHN', '156', 'China', 'People''s Republic of China', ''),
+    ('CO', 'COL', '170', 'Colombia', 'Republic of Colombia', ''),
+    ('CR', 'CRI', '188', 'Costa Rica', 'Republic of Costa Rica', ''),
+    ('CU', 'CUB', '192', 'Cuba', 'Republic of Cuba', ''),
+    ('CV', 'CPV', '132', 'Cabo Verde', 'Republic of Cabo Verde', ''),
+    ('CW', 'CUW', '531', 'Curaçao', 'Curaçao', ''),
+    ('CX', 'CXR', '162', 'Christmas Island', '', ''),
+    ('CY', 'CYP', '196', 'Cyprus', 'Republic of Cyprus', ''),
+    ('CZ', 'CZE', '203', 'Czechia', 'Czech Republic', ''),
+    ('DE', 'DEU', '276', 'Germany', 'Federal Republic of Germany', ''),
+    ('DJ', 'DJI', '262', 'Djibouti', 'Republic of Djibouti', ''),
+    ('DK', 'DNK', '208', 'Denmark', 'Kingdom of Denmark', ''),
+    ('DM', 'DMA', '212', 'Dominica', 'Commonwealth of Dominica', ''),
+    ('DO', 'DOM', '214', 'Dominican Republic', '', ''),
+    ('DZ', 'DZA', '012', 'Algeria', 'People''s Democratic Republic of Algeria', ''),
+    ('EC', 'ECU', '218', 'Ecuador', 'Republic of Ecuador', ''),
+    ('EE', 'EST', '233', 'Estonia', 'Republic of Estonia', ''),
+    ('EG', 'EGY', '818', 'Egypt', 'Arab Republic of Egypt', ''),
+    ('EH', 'ESH', '732', 'Western Sahara', '', ''),
+    ('ER', 'ERI', '232', 'Eritrea', 'the State of Eritrea', ''),
+    ('ES', 'ESP', '724', 'Spain', 'Kingdom of Spain', ''),
+    ('ET', 'ETH', '231', 'Ethiopia', 'Federal Democratic Republic of Ethiopia', ''),
+    ('FI', 'FIN', '246', 'Finland', 'Republic of Finland', ''),
+    ('FJ', 'FJI', '242', 'Fiji', 'Republic of Fiji', ''),
+    ('FK', 'FLK', '238', 'Falkland Islands (Malvinas)', '', ''),
+    ('FM', 'FSM', '583', 'Micronesia, Federated States of', 'Federated States of Micronesia', ''),
+    ('FO', 'FRO', '234', 'Faroe Islands', '', ''),
+    ('FR', 'FRA', '250', 'France', 'French Republic', ''),
+    ('GA', 'GAB', '266', 'Gabon', 'Gabonese Republic', ''),
+    ('GB', 'GBR', '826', 'United Kingdom', 'United Kingdom of Great Britain and Northern Ireland', ''),
+    ('GD', 'GRD', '308', 'Grenada', '', ''),
+    ('GE', 'GEO', '268', 'Georgia', '', ''),
+    ('GF', 'GUF', '254', 'French Guiana', '', ''),
+    ('GG', 'GGY', '831', 'Guernsey', '', ''),
+    ('GH', 'GHA', '288', 'Ghana', 'Republic of Ghana', ''),
+    ('GI', 'GIB', '292', 'Gibraltar', '', ''),
+    ('GL', 'GRL', '304', 'Greenland', '', ''),
+    ('GM', 'GMB', '270', 'Gambia', 'Republic of the Gambia', ''),
+    ('GN', 'GIN', '324', 'Guinea', 'Republic of Guinea', ''),
+    ('GP', 'GLP', '312', 'Guadeloupe', '', ''),
+    ('GQ', 'GNQ', '226', 'Equatorial Guinea', 'Republic of Equatorial Guinea', ''),
+    ('GR', 'GRC', '300', 'Greece', 'Hellenic Republic', ''),
+    ('GS', 'SGS', '239', 'South Georgia and the South Sandwich Islands', '', ''),
+    ('GT', 'GTM', '320', 'Guatemala', 'Republic of Guatemala', ''),
+    ('GU', 'GUM', '316', 'Guam', '', ''),
+    ('GW', 'GNB', '624', 'Guinea-Bissau', 'Republic of Guinea-Bissau', ''),
+    ('GY', 'GUY', '328', 'Guyana', 'Republic of Guyana', ''),
+    ('HK', 'HKG', '344', 'Hong Kong', 'Hong Kong Special Administrative Region of China', ''),
+    ('HM', 'HMD', '334', 'Heard Island and McDonald Islands', '', ''),
+    ('HN', 'HND', '340', 'Honduras', 'Republic of Honduras', ''),
+    ('HR', 'HRV', '191', 'Croatia', 'Republic of Croatia', ''),
+    ('HT', 'HTI', '332', 'Haiti', 'Republic of Haiti', ''),
+    ('HU', 'HUN', '348', 'Hungary', 'Hungary', ''),
+    ('ID', 'IDN', '360', 'Indonesia', 'Republic of Indonesia', ''),
+    ('IE', 'IRL', '372', 'Ireland', '', ''),
+    ('IL', 'ISR', '376', 'Israel', 'State of Israel', ''),
+    ('IM', 'IMN', '833', 'Isle of Man', '', ''),
+    ('IN', 'IND', '356', 'India', 'Republic of India', ''),
+    ('IO', 'IOT', '086', 'British Indian Ocean Territory', '', ''),
+    ('IQ', 'IRQ', '368', 'Iraq', 'Republic of Iraq', ''),
+    ('IR', 'IRN', '364', 'Iran, Islamic Republic of', 'Islamic Republic of Iran', 'Iran'),
+    ('IS', 'ISL', '352', 'Iceland', 'Republic of Iceland', ''),
+    ('IT', 'ITA', '380', 'Italy', 'Italian Republic', ''),
+    ('JE', 'JEY', '832', 'Jersey', '', ''),
+    ('JM', 'JAM', '388', 'Jamaica', '', ''),
+    ('JO', 'JOR', '400', 'Jordan', 'Hashemite Kingdom of Jordan', ''),
+    ('JP', 'JPN', '392', 'Japan', '', ''),
+    ('KE', 'KEN', '404', 'Kenya', 'Republic of Kenya', ''),
+    ('KG', 'KGZ', '417', 'Kyrgyzstan', 'Kyrgyz Republic', ''),
+    ('KH', 'KHM', '116', 'Cambodia', 'Kingdom of Cambodia', ''),
+    ('KI', 'KIR', '296', 'Kiribati', 'Republic of Kiribati', ''),
+    ('KM', 'COM', '174', 'Comoros', 'Union of the Comoros', ''),
+    ('KN', 'KNA', '659', 'Saint Kitts and Nevis', '', ''),
+    ('KP', 'PRK', '408', 'Korea, Democratic People''s Republic of', 'Democratic People''s Republic of Korea', 'North Korea'),
+    ('KR', 'KOR', '410', 'Korea, Republic of', '', 'South Korea'),
+    ('KW', 'KWT', '414', 'Kuwait', 'State of Kuwait', ''),
+    ('KY', 'CYM', '136', 'Cayman Islands', '', ''),
+    ('KZ', 'KAZ', '398', 'Kazakhstan', 'Republic of Kazakhstan', ''),
+    ('LA', 'LAO', '418', 'Lao People''s Democratic Republic', '', 'Laos'),
+    ('LB', 'LBN', '422', 'Lebanon', 'Lebanese Republic', ''),
+    ('LC', 'LCA', '662', 'Saint Lucia', '', ''),
+    ('LI', 'LIE', '438', 'Liechtenstein', 'Principality of Liechtenstein', ''),
+    ('LK', 'LKA', '144', 'Sri Lanka', 'Democratic Socialist Republic of Sri Lanka', ''),
+    ('LR', 'LBR', '430', 'Liberia', 'Republic of Liberia', ''),
+    ('LS', 'LSO', '426', 'Lesotho', 'Kingdom of Lesotho', ''),
+    ('LT', 'LTU', '440', 'Lithuania', 'Republic of Lithuania', ''),
+    ('LU', 'LUX', '442', 'Luxembourg', 'Grand Duchy of Luxembourg', ''),
+    ('LV', 'LVA', '428', 'Latvia', 'Republic of Latvia', ''),
+    ('LY', 'LBY', '434', 'Libya', 'Libya', ''),
+    ('MA', 'MAR', '504', 'Morocco', 'Kingdom of Morocco', ''),
+    ('MC', 'MCO', '492', 'Monaco', 'Principality of Monaco', ''),
+    ('MD', 'MDA', '498', 'Moldova, Republic of', 'Republic of Moldova', 'Moldova'),
+    ('ME', 'MNE', '499', 'Montenegro', 'Montenegro', ''),
+    ('MF', 'MAF', '663', 'Saint Martin (French part)', '', ''),
+    ('MG', 'MDG', '450', 'Madagascar', 'Republic of Madagascar', ''),
+    ('MH', 'MHL', '584', 'Marshall Islands', 'Republic of the Marshall Islands', ''),
+    ('MK', 'MKD', '807', 'North Macedonia', 'Republic of North Macedonia', ''),
+    ('ML', 'MLI', '466', 'Mali', 'Republic of Mali', ''),
+    ('MM', 'MMR', '104', 'Myanmar', 'Republic of Myanmar', ''),
+    ('MN', 'MNG', '496', 'Mongolia', '', ''),
+    ('MO', 'MAC', '446', 'Macao', 'Macao Special Administrative Region of China', ''),
+    ('MP', 'MNP', '580', 'Northern Mariana Islands', 'Commonwealth of the Northern Mariana Islands', ''),
+    ('MQ', 'MTQ', '474', 'Martinique', '', ''),
+    ('MR', 'MRT', '478', 'Mauritania', 'Islamic Republic of Mauritania', ''),
+    ('MS', 'MSR', '500', 'Montserrat', '', ''),
+    ('MT', 'MLT', '470', 'Malta', 'Republic of Malta', ''),
+    ('MU', 'MUS', '480', 'Mauritius', 'Republic of Mauritius', ''),
+    ('MV', 'MDV', '462', 'Maldives', 'Republic of Maldives', ''),
+    ('MW', 'MWI', '454', 'Malawi', 'Republic of Malawi', ''),
+    ('MX', 'MEX', '484', 'Mexico', 'United Mexican States', ''),
+    ('MY', 'MYS', '458', 'Malaysia', '', ''),
+    ('MZ', 'MOZ', '508', 'Mozambique', 'Republic of Mozambique', ''),
+    ('NA', 'NAM', '516', 'Namibia', 'Republic of Namibia', ''),
+    ('NC', 'NCL', '540', 'New Caledonia', '', ''),
+    ('NE', 'NER', '562', 'Niger', 'Republic of the Niger', ''),
+    ('NF', 'NFK', '574', 'Norfolk Island', '', ''),
+    ('NG', 'NGA', '566', 'Nigeria', 'Federal Republic of Nigeria', ''),
+    ('NI', 'NIC', '558', 'Nicaragua', 'Republic of Nicaragua', ''),
+    ('NL', 'NLD', '528', 'Netherlands', 'Kingdom of the Netherlands', ''),
+    ('NO', 'NOR', '578', 'Norway', 'Kingdom of Norway', ''),
+    ('NP', 'NPL', '524', 'Nepal', 'Federal Democratic Republic of Nepal', ''),
+    ('NR', 'NRU', '520', 'Nauru', 'Republic of Nauru', ''),
+    ('NU', 'NIU', '570', 'Niue', 'Niue', ''),
+    ('NZ', 'NZL', '554', 'New Zealand', '', ''),
+    ('OM', 'OMN', '512', 'Oman', 'Sultanate of Oman', ''),
+    ('PA', 'PAN', '591', 'Panama', 'Republic of Panama', ''),
+    ('PE', 'PER', '604', 'Peru', 'Republic of Peru', ''),
+    ('PF', 'PYF', '258', 'French Polynesia', '', ''),
+    ('PG', 'PNG', '598', 'Papua New Guinea', 'Independent State of Papua New Guinea', ''),
+    ('PH', 'PHL', '608', 'Philippines', 'Republic of the Philippines', ''),
+    ('PK', 'PAK', '586', 'Pakistan', 'Islamic Republic of Pakistan', ''),
+    ('PL', 'POL', '616', 'Poland', 'Republic of Poland', ''),
+    ('PM', 'SPM', '666', 'Saint Pierre and Miquelon', '', ''),
+    ('PN', 'PCN', '612', 'Pitcairn', '', ''),
+    ('PR', 'PRI', '630', 'Puerto Rico', '', ''),
+    ('PS', 'PSE', '275', 'Palestine, State of', 'the State of Palestine', ''),
+    ('PT', 'PRT', '620', 'Portugal', 'Portuguese Republic', ''),
+    ('PW', 'PLW', '585', 'Palau', 'Republic of Palau', ''),
+    ('PY', 'PRY', '600', 'Paraguay', 'Republic of Paraguay', ''),
+    ('QA', 'QAT', '634', 'Qatar', 'State of Qatar', ''),
+    ('RE', 'REU', '638', 'Réunion', '', ''),
+    ('RO', 'ROU', '642', 'Romania', '', ''),
+    ('RS', 'SRB', '688', 'Serbia', 'Republic of Serbia', ''),
+    ('RU', 'RUS', '643', 'Russian Federation', '', ''),
+    ('RW', 'RWA', '646', 'Rwanda', 'Rwandese Republic', ''),
+    ('SA', 'SAU', '682', 'Saudi Arabia', 'Kingdom of Saudi Arabia', ''),
+    ('SB', 'SLB', '090', 'Solomon Islands', '', ''),
+    ('SC', 'SYC', '690', 'Seychelles', 'Republic of Seychelles', ''),
+    ('SD', 'SDN', '729', 'Sudan', 'Republic of the Sudan', ''),
+    ('SE', 'SWE', '752', 'Sweden', 'Kingdom of Sweden', ''),
+    ('SG', 'SGP', '702', 'Singapore', 'Republic of Singapore', ''),
+    ('SH', 'SHN', '654', 'Saint Helena, Ascension and Tristan da Cunha', '', ''),
+    ('SI', 'SVN', '705', 'Slovenia', 'Republic of Slovenia', ''),
+    ('SJ', 'SJM', '744', 'Svalbard and Jan Mayen', '', ''),
+    ('SK', 'SVK', '703', 'Slovakia', 'Slovak Republic', ''),
+    ('SL', 'SLE', '694', 'Sierra Leone', 'Republic of Sierra Leone', ''),
+    ('SM', 'SMR', '674', 'San Marino', 'Republic of San Marino', ''),
+    ('SN', 'SEN', '686', 'Senegal', 'Republic of Senegal', ''),
+    ('SO', 'SOM', '706', 'Somalia', 'Federal Republic of Somalia', ''),
+    ('SR', 'SUR', '740', 'Suriname', 'Republic of Suriname', ''),
+    ('SS', 'SSD', '728', 'South Sudan', 'Republic of South Sudan', ''),
+    ('ST', 'STP', '678', 'Sao Tome and Principe', 'Democratic Republic of Sao Tome and Principe', ''),
+    ('SV', 'SLV', '222', 'El Salvador', 'Republic of El Salvador', ''),
+    ('SX', 'SXM', '534', 'Sint Maarten (Dutch part)', 'Sint Maarten (Dutch part)', ''),
+    ('SY', 'SYR', '760', 'Syrian Arab Republic', '', 'Syria'),
+    ('SZ', 'SWZ', '748', 'Eswatini', 'Kingdom of Eswatini', ''),
+    ('TC', 'TCA', '796', 'Turks and Caicos Islands', '', ''),
+    ('TD', 'TCD', '148', 'Chad', 'Republic of Chad', ''),
+    ('TF', 'ATF', '260', 'French Southern Territories', '', ''),
+    ('TG', 'TGO', '768', 'Togo', 'Togolese Republic', ''),
+    ('TH', 'THA', '764', 'Thailand', 'Kingdom of Thailand', ''),
+    ('TJ', 'TJK', '762', 'Tajikistan', 'Republic of Tajikistan', ''),
+    ('TK', 'TKL', '772', 'Tokelau', '', ''),
+    ('TL', 'TLS', '626', 'Timor-Leste', 'Democratic Republic of Timor-Leste', ''),
+    ('TM', 'TKM', '795', 'Turkmenistan', '', ''),
+    ('TN', 'TUN', '788', 'Tunisia', 'Republic of Tunisia', ''),
+    ('TO', 'TON', '776', 'Tonga', 'Kingdom of Tonga', ''),
+    ('TR', 'TUR', '792', 'Türkiye', 'Republic of Türkiye', ''),
+    ('TT', 'TTO', '780', 'Trinidad and Tobago', 'Republic of Trinidad and Tobago', ''),
+    ('TV', 'TUV', '798', 'Tuvalu', '', ''),
+    ('TW', 'TWN', '158', 'Taiwan, Province of China', 'Taiwan, Province of China', 'Taiwan'),
+    ('TZ', 'TZA', '834', 'Tanzania, United Republic of', 'United Republic of Tanzania', 'Tanzania'),
+    ('UA', 'UKR', '804', 'Ukraine', '', ''),
+    ('UG', 'UGA', '800', 'Uganda', 'Republic of Uganda', ''),
+    ('UM', 'UMI', '581', 'United States Minor Outlying Islands', '', ''),
+    ('US', 'USA', '840', 'United States', 'United States of America', ''),
+    ('UY', 'URY', '858', 'Uruguay', 'Eastern Republic of Uruguay', ''),
+    ('UZ', 'UZB', '860', 'Uzbekistan', 'Republic of Uzbekistan', ''),
+    ('VA', 'VAT', '336', 'Holy See (Vatican City State)', '', ''),
+    ('VC', 'VCT', '670', 'Saint Vincent and the Grenadines', '', ''),
+    ('VE', 'VEN', '862', 'Venezuela, Bolivarian Republic of', 'Bolivarian Republic of Venezuela', 'Venezuela'),
+    ('VG', 'VGB', '092', 'Virgin Islands, British', 'British Virgin Islands', ''),
+    ('VI', 'VIR', '850', 'Virgin Islands, U.S.', 'Virgin Islands of the United States', ''),
+    ('VN', 'VNM', '704', 'Viet Nam', 'Socialist Republic of Viet Nam', 'Vietnam'),
+    ('VU', 'VUT', '548', 'Vanuatu', 'Republic of Vanuatu', ''),
+    ('WF', 'WLF', '876', 'Wallis and Futuna', '', ''),
+    ('WS', 'WSM', '882', 'Samoa', 'Independent State of Samoa', ''),
+    ('YE', 'YEM', '887', 'Yemen', 'Republic of Yemen', ''),
+    ('YT', 'MYT', '175', 'Mayotte', '', ''),
+    ('ZA', 'ZAF', '710', 'South Africa', 'Republic of South Africa', ''),
+    ('ZM', 'ZMB', '894', 'Zambia', 'Republic of Zambia', ''),
+    ('ZW', 'ZWE', '716', 'Zimbabwe', 'Republic of Zimbabwe', '')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO iso_currencies (code, numeric_code, name) VALUES
+    ('AED', '784', 'UAE Dirham'),
+    ('AFN', '971', 'Afghani'),
+    ('ALL', '008', 'Lek'),
+    ('AMD', '051', 'Armenian Dram'),
+    ('ANG', '532', 'Netherlands Antillean Guilder'),
+    ('AOA', '973', 'Kwanza'),
+    ('ARS', '032', 'Argentine Peso'),
+    ('AUD', '036', 'Australian Dollar'),
+    ('AWG', '533', 'Aruban Florin'),
+    ('AZN', '944', 'Azerbaijan Manat'),
+    ('BAM', '977', 'Convertible Mark'),
+    ('BBD', '052', 'Barbados Dollar'),
+    ('BDT', '050', 'Taka'),
+    ('BGN', '975', 'Bulgarian Lev'),
+    ('BHD', '048', 'Bahraini Dinar'),
+    ('BIF', '108', 'Burundi Franc'),
+    ('BMD', '060', 'Bermudian Dollar'),
+    ('BND', '096', 'Brunei Dollar'),
+    ('BOB', '068', 'Boliviano'),
+    ('BOV', '984', 'Mvdol'),
+    ('BRL', '986', 'Brazilian Real'),
+    ('BSD', '044', 'Bahamian Dollar'),
+    ('BTN', '064', 'Ngultrum'),
+    ('BWP', '072', 'Pula'),
+    ('BYN', '933', 'Belarusian Ruble'),
+    ('BZD', '084', 'Belize Dollar'),
+    ('CAD', '124', 'Canadian Dollar'),
+    ('CDF', '976', 'Congolese Franc'),
+    ('CHE', '947', 'WIR Euro'),
+    ('CHF', '756', 'Swiss Franc'),
+    ('CHW', '948', 'WIR Franc'),
+    ('CLF', '990', 'Unidad de Fomento'),
+    ('CLP', '152', 'Chilean Peso'),
+    ('CNY', '156', 'Yuan Renminbi'),
+    ('COP', '170', 'Colombian Peso'),
+    ('COU', '970', 'Unidad de Valor Real'),
+    ('CRC', '188', 'Costa Rican Colon'),
+    ('CUC', '931', 'Peso Convertible'),
+    ('CUP', '192', 'Cuban Peso'),
+    ('CVE', '132', 'Cabo Verde Escudo'),
+    ('CZK', '203', 'Czech Koruna'),
+    ('DJF', '262', 'Djibouti Franc'),
+    ('DKK', '208', 'Danish Krone'),
+    ('DOP', '214', 'Dominican Peso'),
+    ('DZD', '012', 'Algerian Dinar'),
+    ('EGP', '818', 'Egyptian Pound'),
+    ('ERN', '232', 'Nakfa'),
+    ('ETB', '230', 'Ethiopian Birr'),
+    ('EUR', '978', 'Euro'),
+    ('FJD', '242', 'Fiji Dollar'),
+    ('FKP', '238', 'Falkland Islands Pound'),
+    ('GBP', '826', 'Pound Sterling'),
+    ('GEL', '981', 'Lari'),
+    ('GHS', '936', 'Ghana Cedi'),
+    ('GIP', '292', 'Gibraltar Pound'),
+    ('GMD', '270', 'Dalasi'),
+    ('GNF', '324', 'Guinean Franc'),
+    ('GTQ', '320', 'Quetzal'),
+    ('GYD', '328', 'Guyana Dollar'),
+    ('HKD', '344', 'Hong Kong Dollar'),
+    ('HNL', '340', 'Lempira'),
+    ('HRK', '191', 'Kuna'),
+    ('HTG', '332', 'Gourde'),
+    ('HUF', '348', 'Forint'),
+    ('IDR', '360', 'Rupiah'),
+    ('ILS', '376', 'New Israeli Sheqel'),
+    ('INR', '356', 'Indian Rupee'),
+    ('IQD', '368', 'Iraqi Dinar'),
+    ('IRR', '364', 'Iranian Rial'),
+    ('ISK', '352', 'Iceland Krona'),
+    ('JMD', '388', 'Jamaican Dollar'),
+    ('JOD', '400', 'Jordanian Dinar'),
+    ('JPY', '392', 'Yen'),
+    ('KES', '404', 'Kenyan Shilling'),
+    ('KGS', '417', 'Som'),
+    ('KHR', '116', 'Riel'),
+    ('KMF', '174', 'Comorian Franc'),
+    ('KPW', '408', 'North Korean Won'),
+    ('KRW', '410', 'Won'),
+    ('KWD', '414', 'Kuwaiti Dinar'),
+    ('KYD', '136', 'Cayman Islands Dollar'),
+    ('KZT', '398', 'Tenge'),
+    ('LAK', '418', 'Lao Kip'),
+    ('LBP', '422', 'Lebanese Pound'),
+    ('LKR', '144', 'Sri Lanka Rupee'),
+    ('LRD', '430', 'Liberian Dollar'),
+    ('LSL', '426', 'Loti'),
+    ('LYD', '434', 'Libyan Dinar'),
+    ('MAD', '504', 'Moroccan Dirham'),
+    ('MDL', '498', 'Moldovan Leu'),
+    ('MGA', '969', 'Malagasy Ariary'),
+    ('MKD', '807', 'Denar'),
+    ('MMK', '104', 'Kyat'),
+    ('MNT', '496', 'Tugrik'),
+    ('MOP', '446', 'Pataca'),
+    ('MRU', '929', 'Ouguiya'),
+    ('MUR', '480', 'Mauritius Rupee'),
+    ('MVR', '462', 'Rufiyaa'),
+    ('MWK', '454', 'Malawi Kwacha'),
+    ('MXN', '484', 'Mexican Peso'),
+    ('MXV', '979', 'Mexican Unidad de Inversion (UDI)'),
+    ('MYR', '458', 'Malaysian Ringgit'),
+    ('MZN', '943', 'Mozambique Metical'),
+    ('NAD', '516', 'Namibia Dollar'),
+    ('NGN', '566', 'Naira'),
+    ('NIO', '558', 'Cordoba Oro'),
+    ('NOK', '578', 'Norwegian Krone'),
+    ('NPR', '524', 'Nepalese Rupee'),
+    ('NZD', '554', 'New Zealand Dollar'),
+    ('OMR', '512', 'Rial Omani'),
+    ('PAB', '590', 'Balboa'),
+    ('PEN', '604', 'Sol'),
+    ('PGK', '598', 'Kina'),
+    ('PHP', '608', 'Philippine Peso'),
+    ('PKR', '586', 'Pakistan Rupee'),
+    ('PLN', '985', 'Zloty'),
+    ('PYG', '600', 'Guarani'),
+    ('QAR', '634', 'Qatari Rial'),
+    ('RON', '946', 'Romanian Leu'),
+    ('RSD', '941', 'Serbian Dinar'),
+    ('RUB', '643', 'Russian Ruble'),
+    ('RWF', '646', 'Rwanda Franc'),
+    ('SAR', '682', 'Saudi Riyal'),
+    ('SBD', '090', 'Solomon Islands Dollar'),
+    ('SCR', '690', 'Seychelles Rupee'),
+    ('SDG', '938', 'Sudanese Pound'),
+    ('SEK', '752', 'Swedish Krona'),
+    ('SGD', '702', 'Singapore Dollar'),
+    ('SHP', '654', 'Saint Helena Pound'),
+    ('SLE', '925', 'Leone'),
+    ('SLL', '694', 'Leone'),
+    ('SOS', '706', 'Somali Shilling'),
+    ('SRD', '968', 'Surinam Dollar'),
+    ('SSP', '728', 'South Sudanese Pound'),
+    ('STN', '930', 'Dobra'),
+    ('SVC', '222', 'El Salvador Colon'),
+    ('SYP', '760', 'Syrian Pound'),
+    ('SZL', '748', 'Lilangeni'),
+    ('THB', '764', 'Baht'),
+    ('TJS', '972', 'Somoni'),
+    ('TMT', '934', 'Turkmenistan New Manat'),
+    ('TND', '788', 'Tunisian Dinar'),
+    ('TOP', '776', 'Pa’anga'),
+    ('TRY', '949', 'Turkish Lira'),
+    ('TTD', '780', 'Trinidad and Tobago Dollar'),
+    ('TWD', '901', 'New Taiwan Dollar'),
+    ('TZS', '834', 'Tanzanian Shilling'),
+    ('UAH', '980', 'Hryvnia'),
+    ('UGX', '800', 'Uganda Shilling'),
+    ('USD', '840', 'US Dollar'),
+    ('USN', '997', 'US Dollar (Next day)'),
+    ('UYI', '940', 'Uruguay Peso en Unidades Indexadas (UI)'),
+    ('UYU', '858', 'Peso Uruguayo'),
+    ('UYW', '927', 'Unidad Previsional'),
+    ('UZS', '860', 'Uzbekistan Sum'),
+    ('VED', '926', 'Bolívar Soberano'),
+    ('VES', '928', 'Bolívar Soberano'),
+    ('VND', '704', 'Dong'),
+    ('VUV', '548', 'Vatu'),
+    ('WST', '882', 'Tala'),
+    ('XAF', '950', 'CFA Franc BEAC'),
+    ('XAG', '961', 'Silver'),
+    ('XAU', '959', 'Gold'),
+    ('XBA', '955', 'Bond Markets Unit European Composite Unit (EURCO)'),
+    ('XBB', '956', 'Bond Markets Unit European Monetary Unit (E.M.U.-6)'),
+    ('XBC', '957', 'Bond Markets Unit European Unit of Account 9 (E.U.A.-9)'),
+    ('XBD', '958', 'Bond Markets Unit European Unit of Account 17 (E.U.A.-17)'),
+    ('XCD', '951', 'East Caribbean Dollar'),
+    ('XDR', '960', 'SDR (Special Drawing Right)'),
+    ('XOF', '952', 'CFA Franc BCEAO'),
+    ('XPD', '964', 'Palladium'),
+    ('XPF', '953', 'CFP Franc'),
+    ('XPT', '962', 'Platinum'),
+    ('XSU', '994', 'Sucre'),
+    ('XTS', '963', 'Codes specifically reserved for testing purposes'),
+    ('XUA', '965', 'ADB Unit of Account'),
+    ('XXX', '999', 'The codes assigned for transactions where no currency is involved'),
+    ('YER', '886', 'Yemeni Rial'),
+    ('ZAR', '710', 'Rand'),
+    ('ZMW', '967', 'Zambian Kwacha'),
+    ('ZWL', '932', 'Zimbabwe Dollar')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO iso_languages (code, alpha3, bibliographic, name) VALUES
+    ('aa', 'aar', NULL, 'Afar'),
+    ('ab', 'abk', NULL, 'Abkhazian'),
+    ('ace', 'ace', NULL, 'Achinese'),
+    ('ach', 'ach', NULL, 'Acoli'),
+    ('ada', 'ada', NULL, 'Adangme'),
+    ('ady', 'ady', NULL, 'Adyghe; Adygei'),
+    ('afa', 'afa', NULL, 'Afro-Asiatic languages'),
+    ('afh', 'afh', NULL, 'Afrihili'),
+    ('af', 'afr', NULL, 'Afrikaans'),
+    ('ain', 'ain', NULL, 'Ainu'),
+    ('ak', 'aka', NULL, 'Akan'),
+    ('akk', 'akk', NULL, 'Akkadian'),
+    ('ale', 'ale', NULL, 'Aleut'),
+    ('alg', 'alg', NULL, 'Algonquian languages'),
+    ('alt', 'alt', NULL, 'Southern Altai'),
+    ('am', 'amh', NULL, 'Amharic'),
+    ('ang', 'ang', NULL, 'English, Old (ca. 450-1100)'),
+    ('anp', 'anp', NULL, 'Angika'),
+    ('apa', 'apa', NULL, 'Apache languages'),
+    ('ar', 'ara', NULL, 'Arabic'),
+    ('arc', 'arc', NULL, 'Official Aramaic (700-300 BCE); Imperial Aramaic (700-300 BCE)'),
+    ('an', 'arg', NULL, 'Aragonese'),
+    ('arn', 'arn', NULL, 'Mapudungun; Mapuche'),
+    ('arp', 'arp', NULL, 'Arapaho'),
+    ('art', 'art', NULL, 'Artificial languages'),
+    ('arw', 'arw', NULL, 'Arawak'),
+    ('as', 'asm', NULL, 'Assamese'),
+    ('ast', 'ast', NULL, 'Asturian; Bable; Leonese; Asturleonese'),
+    ('ath', 'ath', NULL, 'Athapascan languages'),
+    ('aus', 'aus', NULL, 'Australian languages'),
+    ('av', 'ava', NULL, 'Avaric'),
+    ('ae', 'ave', NULL, 'Avestan'),
+    ('awa', 'awa', NULL, 'Awadhi'),
+    ('ay', 'aym', NULL, 'Aymara'),
+    ('az', 'aze', NULL, 'Azerbaijani'),
+    ('bad', 'bad', NULL, 'Banda languages'),
+    ('bai', 'bai', NULL, 'Bamileke languages'),
+    ('ba', 'bak', NULL, 'Bashkir'),
+    ('bal', 'bal', NULL, 'Baluchi'),
+    ('bm', 'bam', NULL, 'Bambara'),
+    ('ban', 'ban', NULL, 'Balinese'),
+    ('bas', 'bas', NULL, 'Basa'),
+    ('bat', 'bat', NULL, 'Baltic languages'),
+    ('bej', 'bej', NULL, 'Beja; Bedawiyet'),
+    ('be', 'bel', NULL, 'Belarusian'),
+    ('bem', 'bem', NULL, 'Bemba'),
+    ('bn', 'ben', NULL, 'Bengali'),
+    ('ber', 'ber', NULL, 'Berber languages'),
+    ('bho', 'bho', NULL, 'Bhojpuri'),
+    ('bh', 'bih', NULL, 'Bihari languages'),
+    ('bik', 'bik', NULL, 'Bikol'),
+    ('bin', 'bin', NULL, 'Bini; Edo'),
+    ('bi', 'bis', NULL, 'Bislama'),
+    ('bla', 'bla', NULL, 'Siksika'),
+    ('bnt', 'bnt', NULL, 'Bantu (Other)'),
+    ('bo', 'bod', 'tib', 'Tibetan'),
+    ('bs', 'bos', NULL, 'Bosnian'),
+    ('bra', 'bra', NULL, 'Braj'),
+    ('br', 'bre', NULL, 'Breton'),
+    ('btk', 'btk', NULL, 'Batak languages'),
+    ('bua', 'bua', NULL, 'Buriat'),
+    ('bug', 'bug', NULL, 'Buginese'),
+    ('bg', 'bul', NULL, 'Bulgarian'),
+    ('byn', 'byn', NULL, 'Blin; Bilin'),
+    ('cad', 'cad', NULL, 'Caddo'),
+    ('cai', 'cai', NULL, 'Central American Indian languages'),
+    ('car', 'car', NULL, 'Galibi Carib'),
+    ('ca', 'cat', NULL, 'Catalan; Valencian'),
+    ('cau', 'cau', NULL, 'Caucasian languages'),
+    ('ceb', 'ceb', NULL, 'Cebuano'),
+    ('cel', 'cel', NULL, 'Celtic languages'),
+    ('cs', 'ces', 'cze', 'Czech'),
+    ('ch', 'cha', NULL, 'Chamorro'),
+    ('chb', 'chb', NULL, 'Chibcha'),
+    ('ce', 'che', NULL, 'Chechen'),
+    ('chg', 'chg', NULL, 'Chagatai'),
+    ('chk', 'chk', NULL, 'Chuukese'),
+    ('chm', 'chm', NULL, 'Mari'),
+    ('chn', 'chn', NULL, 'Chinook jargon'),
+    ('cho', 'cho', NULL, 'Choctaw'),
+    ('chp', 'chp', NULL, 'Chipewyan; Dene Suline'),
+    ('chr', 'chr', NULL, 'Cherokee'),
+    ('cu', 'chu', NULL, 'Church Slavic; Old Slavonic; Church Slavonic; Old Bulgarian; Old Church Slavonic'),
+    ('cv', 'chv', NULL, 'Chuvash'),
+    ('chy', 'chy', NULL, 'Cheyenne'),
+    ('cmc', 'cmc', NULL, 'Chamic languages'),
+    ('cnr', 'cnr', NULL, 'Montenegrin'),
+    ('cop', 'cop', NULL, 'Coptic'),
+    ('kw', 'cor', NULL, 'Cornish'),
+    ('co', 'cos', NULL, 'Corsican'),
+    ('cpe', 'cpe', NULL, 'Creoles and pidgins, English based'),
+    ('cpf', 'cpf', NULL, 'Creoles and pidgins, French-based'),
+    ('cpp', 'cpp', NULL, 'Creoles and pidgins, Portuguese-based'),
+    ('cr', 'cre', NULL, 'Cree'),
+    ('crh', 'crh', NULL, 'Crimean Tatar; Crimean Turkish'),
+    ('crp', 'crp', NULL, 'Creoles and pidgins'),
+    ('csb', 'csb', NULL, 'Kashubian'),
+    ('cus', 'cus', NULL, 'Cushitic languages'),
+    ('cy', 'cym', 'wel', 'Welsh'),
+    ('dak', 'dak', NULL, 'Dakota'),
+    ('da', 'dan', NULL, 'Danish'),
+    ('dar', 'dar', NULL, 'Dargwa'),
+    ('day', 'day', NULL, 'Land Dayak languages'),
+    ('del', 'del', NULL, 'Delaware'),
+    ('den', 'den', NULL, 'Slave (Athapascan)'),
+    ('de', 'deu', 'ger', 'German'),
+    ('dgr', 'dgr', NULL, 'Dogrib'),
+    ('din', 'din', NULL, 'Dinka'),
+    ('dv', 'div', NULL, 'Divehi; Dhivehi; Maldivian'),
+    ('doi', 'doi', NULL, 'Dogri'),
+    ('dra', 'dra', NULL, 'Dravidian languages'),
+    ('dsb', 'dsb', NULL, 'Lower Sorbian'),
+    ('dua', 'dua', NULL, 'Duala'),
+    ('dum', 'dum', NULL, 'Dutch, Middle (ca. 1050-1350)'),
+    ('dyu', 'dyu', NULL, 'Dyula'),
+    ('dz', 'dzo', NULL, 'Dzongkha'),
+    ('efi', 'efi', NULL, 'Efik'),
+    ('egy', 'egy', NULL, 'Egyptian (Ancient)'),
+    ('eka', 'eka', NULL, 'Ekajuk'),
+    ('el', 'ell', 'gre', 'Greek, Modern (1453-)'),
+    ('elx', 'elx', NULL, 'Elamite'),
+    ('en', 'eng', NULL, 'English'),
+    ('enm', 'enm', NULL, 'English, Middle (1100-1500)'),
+    ('eo', 'epo', NULL, 'Esperanto'),
+    ('et', 'est', NULL, 'Estonian'),
+    ('eu', 'eus', 'baq', 'Basque'),
+    ('ee', 'ewe', NULL, 'Ewe'),
+    ('ewo', 'ewo', NULL, 'Ewondo'),
+    ('fan', 'fan', NULL, 'Fang'),
+    ('fo', 'fao', NULL, 'Faroese'),
+    ('fa', 'fas', 'per', 'Persian'),
+    ('fat', 'fat', NULL, 'Fanti'),
+    ('fj', 'fij', NULL, 'Fijian'),
+    ('fil', 'fil', NULL, 'Filipino; Pilipino'),
+    ('fi', 'fin', NULL, 'Finnish'),
+    ('fiu', 'fiu', NULL, 'Finno-Ugrian languages'),
+    ('fon', 'fon', NULL, 'Fon'),
+    ('fr', 'fra', 'fre', 'French'),
+    ('frm', 'frm', NULL, 'French, Middle (ca. 1400-1600)'),
+    ('fro', 'fro', NULL, 'French, Old (842-ca. 1400)'),
+    ('frr', 'frr', NULL, 'Northern Frisian'),
+    ('frs', 'frs', NULL, 'Eastern Frisian'),
+    ('fy', 'fry', NULL, 'Western Frisian'),
+    ('ff', 'ful', NULL, 'Fulah'),
+    ('fur', 'fur', NULL, 'Friulian'),
+    ('gaa', 'gaa', NULL, 'Ga'),
+    ('gay', 'gay', NULL, 'Gayo'),
+    ('gba', 'gba', NULL, 'Gbaya'),
+    ('gem', 'gem', NULL, 'Germanic languages'),
+    ('gez', 'gez', NULL, 'Geez'),
+    ('gil', 'gil', NULL, 'Gilbertese'),
+    ('gd', 'gla', NULL, 'Gaelic; Scottish Gaelic'),
+    ('ga', 'gle', NULL, 'Irish'),
+    ('gl', 'glg', NULL, 'Galician'),
+    ('gv', 'glv', NULL, 'Manx'),
+    ('gmh', 'gmh', NULL, 'German, Middle High (ca. 1050-1500)'),
+    ('goh', 'goh', NULL, 'German, Old High (ca. 750-1050)'),
+    ('gon', 'gon', NULL, 'Gondi'),
+    ('gor', 'gor', NULL, 'Gorontalo'),
+    ('got', 'got', NULL, 'Gothic'),
+    ('grb', 'grb', NULL, 'Grebo'),
+    ('grc', 'grc', NULL, 'Greek, Ancient (to 1453)'),
+    ('gn', 'grn', NULL, 'Guarani'),
+    ('gsw', 'gsw', NULL, 'Swiss German; Alemannic; Alsatian'),
+    ('gu', 'guj', NULL, 'Gujarati'),
+    ('gwi', 'gwi', NULL, 'Gwich''in'),
+    ('hai', 'hai', NULL, 'Haida'),
+    ('ht', 'hat', NULL, 'Haitian; Haitian Creole'),
+    ('ha', 'hau', NULL, 'Hausa'),
+    ('haw', 'haw', NULL, 'Hawaiian'),
+    ('he', 'heb', NULL, 'Hebrew'),
+    ('hz', 'her', NULL, 'Herero'),
+    ('hil', 'hil', NULL, 'Hiligaynon'),
+    ('him', 'him', NULL, 'Himachali languages; Western Pahari languages'),
+    ('hi', 'hin', NULL, 'Hindi'),
+    ('hit', 'hit', NULL, 'Hittite'),
+    ('hmn', 'hmn', NULL, 'Hmong; Mong'),
+    ('ho', 'hmo', NULL, 'Hiri Motu'),
+    ('hr', 'hrv', NULL, 'Croatian'),
+    ('hsb', 'hsb', NULL, 'Upper Sorbian'),
+    ('hu', 'hun', NULL, 'Hungarian'),
+    ('hup', 'hup', NULL, 'Hupa'),
+    ('hy', 'hye', 'arm', 'Armenian'),
+    ('iba', 'iba', NULL, 'Iban'),
+    ('ig', 'ibo', NULL, 'Igbo'),
+    ('io', 'ido', NULL, 'Ido'),
+    ('ii', 'iii', NULL, 'Sichuan Yi; Nuosu'),
+    ('ijo', 'ijo', NULL, 'Ijo languages'),
+    ('iu', 'iku', NULL, 'Inuktitut'),
+    ('ie', 'ile', NULL, 'Interlingue; Occidental'),
+    ('ilo', 'ilo', NULL, 'Iloko'),
+    ('ia', 'ina', NULL, 'Interlingua (International Auxiliary Language Association)'),
+    ('inc', 'inc', NULL, 'Indic languages'),
+    ('id', 'ind', NULL, 'Indonesian'),
+    ('ine', 'ine', NULL, 'Indo-European languages'),
+    ('inh', 'inh', NULL, 'Ingush'),
+    ('ik', 'ipk', NULL, 'Inupiaq'),
+    ('ira', 'ira', NULL, 'Iranian languages'),
+    ('iro', 'iro', NULL, 'Iroquoian languages'),
+    ('is', 'isl', 'ice', 'Icelandic'),
+    ('it', 'ita', NULL, 'Italian'),
+    ('jv', 'jav', NULL, 'Javanese'),
+    ('jbo', 'jbo', NULL, 'Lojban'),
+    ('ja', 'jpn', NULL, 'Japanese'),
+    ('jpr', 'jpr', NULL, 'Judeo-Persian'),
+    ('jrb', 'jrb', NULL, 'Judeo-Arabic'),
+    ('kaa', 'kaa', NULL, 'Kara-Kalpak'),
+    ('kab', 'kab', NULL, 'Kabyle'),
+    ('kac', 'kac', NULL, 'Kachin; Jingpho'),
+    ('kl', 'kal', NULL, 'Kalaallisut; Greenlandic'),
+    ('kam', 'kam', NULL, 'Kamba'),
+    ('kn', 'kan', NULL, 'Kannada'),
+    ('kar', 'kar', NULL, 'Karen languages'),
+    ('ks', 'kas', NULL, 'Kashmiri'),
+    ('ka', 'kat', 'geo', 'Georgian'),
+    ('kr', 'kau', NULL, 'Kanuri'),
+    ('kaw', 'kaw', NULL, 'Kawi'),
+    ('kk', 'kaz', NULL, 'Kazakh'),
+    ('kbd', 'kbd', NULL, 'Kabardian'),
+    ('kha', 'kha', NULL, 'Khasi'),
+    ('khi', 'khi', NULL, 'Khoisan languages'),
+    ('km', 'khm', NULL, 'Central Khmer'),
+    ('kho', 'kho', NULL, 'Khotanese; Sakan'),
+    ('ki', 'kik', NULL, 'Kikuyu; Gikuyu'),
+    ('rw', 'kin', NULL, 'Kinyarwanda'),
+    ('ky', 'kir', NULL, 'Kirghiz; Kyrgyz'),
+    ('kmb', 'kmb', NULL, 'Kimbundu'),
+    ('kok', 'kok', NULL, 'Konkani'),
+    ('kv', 'kom', NULL, 'Komi'),
+    ('kg', 'kon', NULL, 'Kongo'),
+    ('ko', 'kor', NULL, 'Korean'),
+    ('kos', 'kos', NULL, 'Kosraean'),
+    ('kpe', 'kpe', NULL, 'Kpelle'),
+    ('krc', 'krc', NULL, 'Karachay-Balkar'),
+    ('krl', 'krl', NULL, 'Karelian'),
+    ('kro', 'kro', NULL, 'Kru languages'),
+    ('kru', 'kru', NULL, 'Kurukh'),
+    ('kj', 'kua', NULL, 'Kuanyama; Kwanyama'),
+    ('kum', 'kum', NULL, 'Kumyk'),
+    ('ku', 'kur', NULL, 'Kurdish'),
+    ('kut', 'kut', NULL, 'Kutenai'),
+    ('lad', 'lad', NULL, 'Ladino'),
+    ('lah', 'lah', NULL, 'Lahnda'),
+    ('lam', 'lam', NULL, 'Lamba'),
+    ('lo', 'lao', NULL, 'Lao'),
+    ('la', 'lat', NULL, 'Latin'),
+    ('lv', 'lav', NULL, 'Latvian'),
+    ('lez', 'lez', NULL, 'Lezghian'),
+    ('li', 'lim', NULL, 'Limburgan; Limburger; Limburgish'),
+    ('ln', 'lin', NULL, 'Lingala'),
+    ('lt', 'lit', NULL, 'Lithuanian'),
+    ('lol', 'lol', NULL, 'Mongo'),
+    ('loz', 'loz', NULL, 'Lozi'),
+    ('lb', 'ltz', NULL, 'Luxembourgish; Letzeburgesch'),
+    ('lua', 'lua', NULL, 'Luba-Lulua'),
+    ('lu', 'lub', NULL, 'Luba-Katanga'),
+    ('lg', 'lug', NULL, 'Ganda'),
+    ('lui', 'lui', NULL, 'Luiseno'),
+    ('lun', 'lun', NULL, 'Lunda'),
+    ('luo', 'luo', NULL, 'Luo (Kenya and Tanzania)'),
+    ('lus', 'lus', NULL, 'Lushai'),
+    ('mad', 'mad', NULL, 'Madurese'),
+    ('mag', 'mag', NULL, 'Magahi'),
+    ('mh', 'mah', NULL, 'Marshallese'),
+    ('mai', 'mai', NULL, 'Maithili'),
+    ('mak', 'mak', NULL, 'Makasar'),
+    ('ml', 'mal', NULL, 'Malayalam'),
+    ('man', 'man', NULL, 'Mandingo'),
+    ('map', 'map', NULL, 'Austronesian languages'),
+    ('mr', 'mar', NULL, 'Marathi'),
+    ('mas', 'mas', NULL, 'Masai'),
+    ('mdf', 'mdf', NULL, 'Moksha'),
+    ('mdr', 'mdr', NULL, 'Mandar'),
+    ('men', 'men', NULL, 'Mende'),
+    ('mga', 'mga', NULL, 'Irish, Middle (900-1200)'),
+    ('mic', 'mic', NULL, 'Mi''kmaq; Micmac'),
+    ('min', 'min', NULL, 'Minangkabau'),
+    ('mk', 'mkd', 'mac', 'Macedonian'),
+    ('mkh', 'mkh', NULL, 'Mon-Khmer languages'),
+    ('mg', 'mlg', NULL, 'Malagasy'),
+    ('mt', 'mlt', NULL, 'Maltese'),
+    ('mnc', 'mnc', NULL, 'Manchu'),
+    ('mni', 'mni', NULL, 'Manipuri'),
+    ('mno', 'mno', NULL, 'Manobo languages'),
+    ('moh', 'moh', NULL, 'Mohawk'),
+    ('mn', 'mon', NULL, 'Mongolian'),
+    ('mos', 'mos', NULL, 'Mossi'),
+    ('mi', 'mri', 'mao', 'Maori'),
+    ('ms', 'msa', 'may', 'Malay'),
+    ('mun', 'mun', NULL, 'Munda languages'),
+    ('mus', 'mus', NULL, 'Creek'),
+    ('mwl', 'mwl', NULL, 'Mirandese'),
+    ('mwr', 'mwr', NULL, 'Marwari'),
+    ('my', 'mya', 'bur', 'Burmese'),
+    ('myn', 'myn', NULL, 'Mayan languages'),
+    ('myv', 'myv', NULL, 'Erzya'),
+    ('nah', 'nah', NULL, 'Nahuatl languages'),
+    ('nai', 'nai', NULL, 'North American Indian languages'),
+    ('nap', 'nap', NULL, 'Neapolitan'),
+    ('na', 'nau', NULL, 'Nauru'),
+    ('nv', 'nav', NULL, 'Navajo; Navaho'),
+    ('nr', 'nbl', NULL, 'Ndebele, South; South Ndebele'),
+    ('nd', 'nde', NULL, 'Ndebele, North; North Ndebele'),
+    ('ng', 'ndo', NULL, 'Ndonga'),
+    ('nds', 'nds', NULL, 'Low German; Low Saxon; German, Low; Saxon, Low'),
+    ('ne', 'nep', NULL, 'Nepali'),
+    ('new', 'new', NULL, 'Nepal Bhasa; Newari'),
+    ('nia', 'nia', NULL, 'Nias'),
+    ('nic', 'nic', NULL, 'Niger-Kordofanian languages'),
+    ('niu', 'niu', NULL, 'Niuean'),
+    ('nl', 'nld', 'dut', 'Dutch; Flemish'),
+    ('nn', 'nno', NULL, 'Norwegian Nynorsk; Nynorsk, Norwegian'),
+    ('nb', 'nob', NULL, 'Bokmål, Norwegian; Norwegian Bokmål'),
+    ('nog', 'nog', NULL, 'Nogai'),
+    ('non', 'non', NULL, 'Norse, Old'),
+    ('no', 'nor', NULL, 'Norwegian'),
+    ('nqo', 'nqo', NULL, 'N''Ko'),
+    ('nso', 'nso', NULL, 'Pedi; Sepedi; Northern Sotho'),
+    ('nub', 'nub', NULL, 'Nubian languages'),
+    ('nwc', 'nwc', NULL, 'Classical Newari; Old Newari; Classical Nepal Bhasa'),
+    ('ny', 'nya', NULL, 'Chichewa; Chewa; Nyanja'),
+    ('nym', 'nym', NULL, 'Nyamwezi'),
+    ('nyn', 'nyn', NULL, 'Nyankole'),
+    ('nyo', 'nyo', NULL, 'Nyoro'),
+    ('nzi', 'nzi', NULL, 'Nzima'),
+    ('oc', 'oci', NULL, 'Occitan (post 1500); Provençal'),
+    ('oj', 'oji', NULL, 'Ojibwa'),
+    ('or', 'ori', NULL, 'Oriya'),
+    ('om', 'orm', NULL, 'Oromo'),
+    ('osa', 'osa', NULL, 'Osage'),
+    ('os', 'oss', NULL, 'Ossetian; Ossetic'),
+    ('ota', 'ota', NULL, 'Turkish, Ottoman (1500-1928)'),
+    ('oto', 'oto', NULL, 'Otomian languages'),
+    ('paa', 'paa', NULL, 'Papuan languages'),
+    ('pag', 'pag', NULL, 'Pangasinan'),
+    ('pal', 'pal', NULL, 'Pahlavi'),
+    ('pam', 'pam', NULL, 'Pampanga; Kapampangan'),
+    ('pa', 'pan', NULL, 'Panjabi; Punjabi'),
+    ('pap', 'pap', NULL, 'Papiamento'),
+    ('pau', 'pau', NULL, 'Palauan'),
+    ('peo', 'peo', NULL, 'Persian, Old (ca. 600-400 B.C.)'),
+    ('phi', 'phi', NULL, 'Philippine languages'),
+    ('phn', 'phn', NULL, 'Phoenician'),
+    ('pi', 'pli', NULL, 'Pali'),
+    ('pl', 'pol', NULL, 'Polish'),
+    ('pon', 'pon', NULL, 'Pohnpeian'),
+    ('pt', 'por', NULL, 'Portuguese'),
+    ('pra', 'pra', NULL, 'Prakrit languages'),
+    ('pro', 'pro', NULL, 'Provençal, Old (to 1500)'),
+    ('ps', 'pus', NULL, 'Pushto; Pashto'),
+    ('qu', 'que', NULL, 'Quechua'),
+    ('raj', 'raj', NULL, 'Rajasthani'),
+    ('rap', 'rap', NULL, 'Rapanui'),
+    ('rar', 'rar', NULL, 'Rarotongan; Cook Islands Maori'),
+    ('roa', 'roa', NULL, 'Romance languages'),
+    ('rm', 'roh', NULL, 'Romansh'),
+    ('rom', 'rom', NULL, 'Romany'),
+    ('ro', 'ron', 'rum', 'Romanian; Moldavian; Moldovan'),
+    ('rn', 'run', NULL, 'Rundi'),
+    ('rup', 'rup', NULL, 'Aromanian; Arumanian; Macedo-Romanian'),
+    ('ru', 'rus', NULL, 'Russian'),
+    ('sad', 'sad', NULL, 'Sandawe'),
+    ('sg', 'sag', NULL, 'Sango'),
+    ('sah', 'sah', NULL, 'Yakut'),
+    ('sai', 'sai', NULL, 'South American Indian (Other)'),
+    ('sal', 'sal', NULL, 'Salishan languages'),
+    ('sam', 'sam', NULL, 'Samaritan Aramaic'),
+    ('sa', 'san', NULL, 'Sanskrit'),
+    ('sas', 'sas', NULL, 'Sasak'),
+    ('sat', 'sat', NULL, 'Santali'),
+    ('scn', 'scn', NULL, 'Sicilian'),
+    ('sco', 'sco', NULL, 'Scots'),
+    ('sel', 'sel', NULL, 'Selkup'),
+    ('sem', 'sem', NULL, 'Semitic languages'),
+    ('sga', 'sga', NULL, 'Irish, Old (to 900)'),
+    ('sgn', 'sgn', NULL, 'Sign Languages'),
+    ('shn', 'shn', NULL, 'Shan'),
+    ('sid', 'sid', NULL, 'Sidamo'),
+    ('si', 'sin', NULL, 'Sinhala; Sinhalese'),
+    ('sio', 'sio', NULL, 'Siouan languages'),
+    ('sit', 'sit', NULL, 'Sino-Tibetan languages'),
+    ('sla', 'sla', NULL, 'Slavic languages'),
+    ('sk', 'slk', 'slo', 'Slovak'),
+    ('sl', 'slv', NULL, 'Slovenian'),
+    ('sma', 'sma', NULL, 'Southern Sami'),
+    ('se', 'sme', NULL, 'Northern Sami'),
+    ('smi', 'smi', NULL, 'Sami languages'),
+    ('smj', 'smj', NULL, 'Lule Sami'),
+    ('smn', 'smn', NULL, 'Inari Sami'),
+    ('sm', 'smo', NULL, 'Samoan'),
+    ('sms', 'sms', NULL, 'Skolt Sami'),
+    ('sn', 'sna', NULL, 'Shona'),
+    ('sd', 'snd', NULL, 'Sindhi'),
+    ('snk', 'snk', NULL, 'Soninke'),
+    ('sog', 'sog', NULL, 'Sogdian'),
+    ('so', 'som', NULL, 'Somali'),
+    ('son', 'son', NULL, 'Songhai languages'),
+    ('st', 'sot', NULL, 'Sotho, Southern'),
+    ('es', 'spa', NULL, 'Spanish; Castilian'),
+    ('sq', 'sqi', 'alb', 'Albanian'),
+    ('sc', 'srd', NULL, 'Sardinian'),
+    ('srn', 'srn', NULL, 'Sranan Tongo'),
+    ('sr', 'srp', NULL, 'Serbian'),
+    ('srr', 'srr', NULL, 'Serer'),
+    ('ssa', 'ssa', NULL, 'Nilo-Saharan languages'),
+    ('ss', 'ssw', NULL, 'Swati'),
+    ('suk', 'suk', NULL, 'Sukuma'),
+    ('su', 'sun', NULL, 'Sundanese'),
+    ('sus', 'sus', NULL, 'Susu'),
+    ('sux', 'sux', NULL, 'Sumerian'),
+    ('sw', 'swa', NULL, 'Swahili'),
+    ('sv', 'swe', NULL, 'Swedish'),
+    ('syc', 'syc', NULL, 'Classical Syriac'),
+    ('syr', 'syr', NULL, 'Syriac'),
+    ('ty', 'tah', NULL, 'Tahitian'),
+    ('tai', 'tai', NULL, 'Tai languages'),
+    ('ta', 'tam', NULL, 'Tamil'),
+    ('tt', 'tat', NULL, 'Tatar'),
+    ('te', 'tel', NULL, 'Telugu'),
+    ('tem', 'tem', NULL, 'Timne'),
+    ('ter', 'ter', NULL, 'Tereno'),
+    ('tet', 'tet', NULL, 'Tetum'),
+    ('tg', 'tgk', NULL, 'Tajik'),
+    ('tl', 'tgl', NULL, 'Tagalog'),
+    ('th', 'tha', NULL, 'Thai'),
+    ('tig', 'tig', NULL, 'Tigre'),
+    ('ti', 'tir', NULL, 'Tigrinya'),
+    ('tiv', 'tiv', NULL, 'Tiv'),
+    ('tkl', 'tkl', NULL, 'Tokelau'),
+    ('tlh', 'tlh', NULL, 'Klingon; tlhIngan-Hol'),
+    ('tli', 'tli', NULL, 'Tlingit'),
+    ('tmh', 'tmh', NULL, 'Tamashek'),
+    ('tog', 'tog', NULL, 'Tonga (Nyasa)'),
+    ('to', 'ton', NULL, 'Tonga (Tonga Islands)'),
+    ('tpi', 'tpi', NULL, 'Tok Pisin'),
+    ('tsi', 'tsi', NULL, 'Tsimshian'),
+    ('tn', 'tsn', NULL, 'Tswana'),
+    ('ts', 'tso', NULL, 'Tsonga'),
+    ('tk', 'tuk', NULL, 'Turkmen'),
+    ('tum', 'tum', NULL, 'Tumbuka'),
+    ('tup', 'tup', NULL, 'Tupi languages'),
+    ('tr', 'tur', NULL, 'Turkish'),
+    ('tut', 'tut', NULL, 'Altaic languages'),
+    ('tvl', 'tvl', NULL, 'Tuvalu'),
+    ('tw', 'twi', NULL, 'Twi'),
+    ('tyv', 'tyv', NULL, 'Tuvinian'),
+    ('udm', 'udm', NULL, 'Udmurt'),
+    ('uga', 'uga', NULL, 'Ugaritic'),
+    ('ug', 'uig', NULL, 'Uighur; Uyghur'),
+    ('uk', 'ukr', NULL, 'Ukrainian'),
+    ('umb', 'umb', NULL, 'Umbundu'),
+    ('ur', 'urd', NULL, 'Urdu'),
+    ('uz', 'uzb', NULL, 'Uzbek'),
+    ('vai', 'vai', NULL, 'Vai'),
+    ('ve', 'ven', NULL, 'Venda'),
+    ('vi', 'vie', NULL, 'Vietnamese'),
+    ('vo', 'vol', NULL, 'Volapük'),
+    ('vot', 'vot', NULL, 'Votic'),
+    ('wak', 'wak', NULL, 'Wakashan languages'),
+    ('wal', 'wal', NULL, 'Walamo'),
+    ('war', 'war', NULL, 'Waray'),
+    ('was', 'was', NULL, 'Washo'),
+    ('wen', 'wen', NULL, 'Sorbian languages'),
+    ('wa', 'wln', NULL, 'Walloon'),
+    ('wo', 'wol', NULL, 'Wolof'),
+    ('xal', 'xal', NULL, 'Kalmyk; Oirat'),
+    ('xh', 'xho', NULL, 'Xhosa'),
+    ('yao', 'yao', NULL, 'Yao'),
+    ('yap', 'yap', NULL, 'Yapese'),
+    ('yi', 'yid', NULL, 'Yiddish'),
+    ('yo', 'yor', NULL, 'Yoruba'),
+    ('ypk', 'ypk', NULL, 'Yupik languages'),
+    ('zap', 'zap', NULL, 'Zapotec'),
+    ('zbl', 'zbl', NULL, 'Blissymbols; Blissymbolics; Bliss'),
+    ('zen', 'zen', NULL, 'Zenaga'),
+    ('zgh', 'zgh', NULL, 'Standard Moroccan Tamazight'),
+    ('za', 'zha', NULL, 'Zhuang; Chuang'),
+    ('zh', 'zho', 'chi', 'Chinese'),
+    ('znd', 'znd', NULL, 'Zande languages'),
+    ('zu', 'zul', NULL, 'Zulu'),
+    ('zun', 'zun', NULL, 'Zuni'),
+    ('zza', 'zza', NULL, 'Zaza; Dimili; Dimli; Kirdki; Kirmanjki; Zazaki')
+ON CONFLICT DO NOTHING;
+
+-- Коды ISO и многозначные языки/валюты стран
+ALTER TABLE countries
+    ADD COLUMN IF NOT EXISTS iso2 VARCHAR(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS iso3 VARCHAR(3) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_countries_iso2 ON countries(iso2) WHERE iso2 <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_countries_iso3 ON countries(iso3) WHERE iso3 <> '';
+
+CREATE TABLE IF NOT EXISTS country_languages (
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    language_code VARCHAR(3) NOT NULL REFERENCES iso_languages(code),
+    PRIMARY KEY (country_id, language_code)
+);
+
+CREATE TABLE IF NOT EXISTS country_currencies (
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    currency_code CHAR(3) NOT NULL REFERENCES iso_currencies(code),
+    PRIMARY KEY (country_id, currency_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_country_languages_code ON country_languages(language_code);
+CREATE INDEX IF NOT EXISTS idx_country_currencies_code ON country_currencies(currency_code);
+
+-- Сопоставление существующих строк по названию страны. Код получает одна
+-- строка (с меньшим id), даже если с ним совпало несколько, а строка,
+-- совпавшая с несколькими кодами, - первый из них: иначе UPDATE нарушил бы
+-- idx_countries_iso2.
+WITH candidates AS (
+    SELECT DISTINCT ON (ic.alpha2) c.id, ic.alpha2, ic.alpha3
+    FROM countries c
+    JOIN iso_countries ic
+      ON LOWER(TRIM(c.name)) IN (LOWER(ic.name), LOWER(ic.official_name), LOWER(ic.common_name))
+    WHERE c.iso2 = ''
+      AND NOT EXISTS (SELECT 1 FROM countries other WHERE other.iso2 = ic.alpha2)
+    ORDER BY ic.alpha2, c.id
+), matches AS (
+    SELECT DISTINCT ON (id) id, alpha2, alpha3
+    FROM candidates
+    ORDER BY id, alpha2
+)
+UPDATE countries c
+SET iso2 = m.alpha2, iso3 = m.alpha3
+FROM matches m
+WHERE c.id = m.id;
+
+-- Свободный текст языков и валют разбивается по запятым, точкам с запятой и слешам;
+-- каждая часть сравнивается с кодом или одним из названий справочника
+INSERT INTO country_languages (country_id, language_code)
+SELECT DISTINCT c.id, l.code
+FROM countries c
+CROSS JOIN LATERAL regexp_split_to_table(COALESCE(c.language, ''), '\s*[,;/]\s*') AS token
+JOIN iso_languages l
+  ON LOWER(TRIM(token)) IN (l.code, l.alpha3, COALESCE(l.bibliographic, ''))
+  OR LOWER(TRIM(token)) = ANY(string_to_array(LOWER(l.name), '; '))
+WHERE TRIM(token) <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO country_currencies (country_id, currency_code)
+SELECT DISTINCT c.id, cur.code
+FROM countries c
+CROSS JOIN LATERAL regexp_split_to_table(COALESCE(c.currency, ''), '\s*[,;/]\s*') AS token
+JOIN iso_currencies cur
+  ON UPPER(TRIM(token)) = cur.code
+  OR LOWER(TRIM(token)) = LOWER(cur.name)
+WHERE TRIM(token) <> ''
+ON CONFLICT DO NOTHING;
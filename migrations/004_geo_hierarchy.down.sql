@@ -0,0 +1,11 @@
+ALTER TABLE places
+    DROP COLUMN IF EXISTS city_id,
+    DROP COLUMN IF EXISTS region_id;
+
+DROP TABLE IF EXISTS cities;
+DROP TABLE IF EXISTS regions;
+
+DROP INDEX IF EXISTS idx_countries_continent_code;
+ALTER TABLE countries DROP COLUMN IF EXISTS continent_code;
+
+DROP TABLE IF EXISTS continents;
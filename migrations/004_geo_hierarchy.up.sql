@@ -0,0 +1,47 @@
+-- Континенты (коды как в UN M49 / GeoNames)
+CREATE TABLE IF NOT EXISTS continents (
+    code CHAR(2) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL
+);
+
+INSERT INTO continents (code, name) VALUES
+    ('AF', 'Africa'),
+    ('AN', 'Antarctica'),
+    ('AS', 'Asia'),
+    ('EU', 'Europe'),
+    ('NA', 'North America'),
+    ('OC', 'Oceania'),
+    ('SA', 'South America')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE countries
+    ADD COLUMN IF NOT EXISTS continent_code VARCHAR(2) NOT NULL DEFAULT '';
+
+-- Административные регионы страны
+CREATE TABLE IF NOT EXISTS regions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    code VARCHAR(10) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE
+);
+
+-- Города; регион необязателен (столичные города, микрогосударства)
+CREATE TABLE IF NOT EXISTS cities (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    region_id INTEGER REFERENCES regions(id) ON DELETE SET NULL
+);
+
+ALTER TABLE places
+    ADD COLUMN IF NOT EXISTS region_id INTEGER REFERENCES regions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS city_id INTEGER REFERENCES cities(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_countries_continent_code ON countries(continent_code);
+CREATE INDEX IF NOT EXISTS idx_regions_country_id ON regions(country_id);
+CREATE INDEX IF NOT EXISTS idx_cities_country_id ON cities(country_id);
+CREATE INDEX IF NOT EXISTS idx_cities_region_id ON cities(region_id);
+CREATE INDEX IF NOT EXISTS idx_places_region_id ON places(region_id);
+CREATE INDEX IF NOT EXISTS idx_places_city_id ON places(city_id);
@@ -0,0 +1,4 @@
+ALTER TABLE places
+    DROP CONSTRAINT IF EXISTS places_coordinates_pair,
+    DROP CONSTRAINT IF EXISTS places_longitude_range,
+    DROP CONSTRAINT IF EXISTS places_latitude_range;
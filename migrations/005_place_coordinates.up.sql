@@ -0,0 +1,9 @@
+-- (0, 0) подставлялось, когда координаты не передавали - считаем их незаданными
+UPDATE places SET latitude = NULL, longitude = NULL WHERE latitude = 0 AND longitude = 0;
+
+-- Проверки не валидируют существующие строки (NOT VALID), чтобы миграция не падала
+-- на уже сохраненных некорректных координатах; новые записи проверяются
+ALTER TABLE places
+    ADD CONSTRAINT places_latitude_range CHECK (latitude BETWEEN -90 AND 90) NOT VALID,
+    ADD CONSTRAINT places_longitude_range CHECK (longitude BETWEEN -180 AND 180) NOT VALID,
+    ADD CONSTRAINT places_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL)) NOT VALID;
@@ -0,0 +1,7 @@
+ALTER TABLE places
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version;
+
+ALTER TABLE countries
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,13 @@
+-- История изменений стран и мест: полный снимок после каждого изменения.
+-- Фото входят в снимок места.
+CREATE TABLE IF NOT EXISTS revisions (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (entity_type, entity_id, revision)
+);
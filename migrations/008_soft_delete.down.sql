@@ -0,0 +1,17 @@
+-- Записи из корзины удаляются окончательно
+DELETE FROM places WHERE deleted_at IS NOT NULL;
+DELETE FROM countries WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_countries_iso2;
+DROP INDEX IF EXISTS idx_countries_iso3;
+CREATE UNIQUE INDEX idx_countries_iso2 ON countries(iso2) WHERE iso2 <> '';
+CREATE UNIQUE INDEX idx_countries_iso3 ON countries(iso3) WHERE iso3 <> '';
+
+DROP INDEX IF EXISTS idx_places_deleted_at;
+DROP INDEX IF EXISTS idx_countries_deleted_at;
+
+ALTER TABLE places
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE countries
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,16 @@
+-- Мягкое удаление: удаленные страны и места остаются в корзине до очистки
+-- по сроку хранения. Места, удаленные вместе со страной, получают ее deleted_at.
+ALTER TABLE countries
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE places
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_countries_deleted_at ON countries(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_places_deleted_at ON places(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Коды ISO освобождаются сразу после удаления страны
+DROP INDEX IF EXISTS idx_countries_iso2;
+DROP INDEX IF EXISTS idx_countries_iso3;
+CREATE UNIQUE INDEX idx_countries_iso2 ON countries(iso2) WHERE iso2 <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_countries_iso3 ON countries(iso3) WHERE iso3 <> '' AND deleted_at IS NULL;
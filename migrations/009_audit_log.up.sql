@@ -0,0 +1,34 @@
+-- Журнал аудита изменяющих запросов. Записи только добавляются: изменение
+-- и удаление строк запрещены триггером.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    actor VARCHAR(255) NOT NULL,
+    ip VARCHAR(64) NOT NULL,
+    request_id VARCHAR(128) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    error_code VARCHAR(100) NOT NULL DEFAULT '',
+    entity_type VARCHAR(20) NOT NULL DEFAULT '',
+    entity_id INTEGER,
+    before JSONB,
+    after JSONB,
+    duration_ms INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
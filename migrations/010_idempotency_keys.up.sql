@@ -0,0 +1,15 @@
+-- Ключи идемпотентности POST-запросов: повтор с тем же ключом получает
+-- сохраненный ответ вместо повторного создания записи.
+-- status = 0 - запрос еще выполняется.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    actor VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    headers JSONB NOT NULL DEFAULT '{}',
+    body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (actor, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
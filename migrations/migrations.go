@@ -0,0 +1,58 @@
+// Package migrations встраивает миграции в бинарник. БД SQLite создается и
+// обновляется при запуске сервиса; миграции PostgreSQL (*.sql в этом
+// каталоге) применяются отдельно, в бинарнике они нужны только для проверки
+// версии схемы.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed sqlite/*.up.sql
+var sqlite embed.FS
+
+//go:embed *.up.sql
+var postgres embed.FS
+
+// SQLite возвращает миграции из sqlite/, по одной на каждую миграцию
+// PostgreSQL с тем же номером
+func SQLite() fs.FS {
+	migrations, err := fs.Sub(sqlite, "sqlite")
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
+
+// Postgres возвращает миграции PostgreSQL
+func Postgres() fs.FS {
+	return postgres
+}
+
+// Latest возвращает номер последней миграции *.up.sql в migrations - версию
+// схемы, которую ожидает сервис
+func Latest(migrations fs.FS) (int, error) {
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no migrations found")
+	}
+	return Version(files[len(files)-1])
+}
+
+// Version возвращает номер миграции по имени файла или версии:
+// "010_idempotency_keys.up.sql" и "010_idempotency_keys" - 10
+func Version(name string) (int, error) {
+	number, _, _ := strings.Cut(name, "_")
+	version, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid migration name %q", name)
+	}
+	return version, nil
+}
@@ -0,0 +1,44 @@
+-- Таблица стран
+CREATE TABLE countries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    capital TEXT NOT NULL,
+    language TEXT,
+    currency TEXT,
+    description TEXT,
+    photo_url TEXT,
+    -- Колонки из 006_versioning: SQLite не принимает функцию в DEFAULT
+    -- колонки, добавляемой к существующей таблице
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at DATETIME NOT NULL DEFAULT (now())
+);
+
+-- Таблица мест
+CREATE TABLE places (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT,
+    longitude REAL,
+    latitude REAL,
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at DATETIME NOT NULL DEFAULT (now()),
+    -- Проверки координат из 005_place_coordinates: SQLite не добавляет
+    -- ограничения к существующей таблице
+    CONSTRAINT places_latitude_range CHECK (latitude BETWEEN -90 AND 90),
+    CONSTRAINT places_longitude_range CHECK (longitude BETWEEN -180 AND 180),
+    CONSTRAINT places_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+-- Таблица фотографий мест (для хранения массива URL)
+CREATE TABLE place_photos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    place_id INTEGER NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    url TEXT NOT NULL
+);
+
+-- Индексы
+CREATE INDEX idx_countries_name ON countries(name);
+CREATE INDEX idx_places_name ON places(name);
+CREATE INDEX idx_place_photos_place_id ON place_photos(place_id);
+CREATE INDEX idx_places_country_id ON places(country_id);
@@ -0,0 +1,22 @@
+-- Поля стран, заполняемые из Wikidata
+ALTER TABLE countries ADD COLUMN wikidata_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE countries ADD COLUMN population INTEGER;
+ALTER TABLE countries ADD COLUMN area REAL;
+ALTER TABLE countries ADD COLUMN flag_url TEXT NOT NULL DEFAULT '';
+
+-- Предложения по обогащению стран (и атрибуция примененных значений)
+CREATE TABLE country_enrichments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    current_value TEXT NOT NULL DEFAULT '',
+    proposed_value TEXT NOT NULL,
+    source TEXT NOT NULL,
+    source_ref TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at DATETIME NOT NULL DEFAULT (now()),
+    reviewed_at DATETIME
+);
+
+CREATE INDEX idx_country_enrichments_country_field ON country_enrichments(country_id, field);
+CREATE INDEX idx_country_enrichments_status ON country_enrichments(status);
@@ -0,0 +1,45 @@
+-- Континенты (коды как в UN M49 / GeoNames)
+CREATE TABLE continents (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO continents (code, name) VALUES
+    ('AF', 'Africa'),
+    ('AN', 'Antarctica'),
+    ('AS', 'Asia'),
+    ('EU', 'Europe'),
+    ('NA', 'North America'),
+    ('OC', 'Oceania'),
+    ('SA', 'South America')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE countries ADD COLUMN continent_code TEXT NOT NULL DEFAULT '';
+
+-- Административные регионы страны
+CREATE TABLE regions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    code TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE
+);
+
+-- Города; регион необязателен (столичные города, микрогосударства)
+CREATE TABLE cities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    country_id INTEGER NOT NULL REFERENCES countries(id) ON DELETE CASCADE,
+    region_id INTEGER REFERENCES regions(id) ON DELETE SET NULL
+);
+
+ALTER TABLE places ADD COLUMN region_id INTEGER REFERENCES regions(id) ON DELETE SET NULL;
+ALTER TABLE places ADD COLUMN city_id INTEGER REFERENCES cities(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_countries_continent_code ON countries(continent_code);
+CREATE INDEX idx_regions_country_id ON regions(country_id);
+CREATE INDEX idx_cities_country_id ON cities(country_id);
+CREATE INDEX idx_cities_region_id ON cities(region_id);
+CREATE INDEX idx_places_region_id ON places(region_id);
+CREATE INDEX idx_places_city_id ON places(city_id);
@@ -0,0 +1,4 @@
+-- Ограничения координат мест заданы при создании таблицы в 001_init: SQLite
+-- не добавляет CHECK к существующей таблице. Нулевые координаты из старых
+-- версий в новой БД не встречаются.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Колонки version и updated_at стран и мест заданы при создании таблиц в
+-- 001_init: SQLite не принимает функцию в DEFAULT колонки, добавляемой к
+-- существующей таблице.
+SELECT 1;
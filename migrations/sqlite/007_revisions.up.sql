@@ -0,0 +1,13 @@
+-- История изменений стран и мест: полный снимок после каждого изменения.
+-- Фото входят в снимок места.
+CREATE TABLE revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type TEXT NOT NULL,
+    entity_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    snapshot BLOB NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (now()),
+    UNIQUE (entity_type, entity_id, revision)
+);
@@ -0,0 +1,15 @@
+-- Ключи идемпотентности POST-запросов: повтор с тем же ключом получает
+-- сохраненный ответ вместо повторного создания записи.
+-- status = 0 - запрос еще выполняется.
+CREATE TABLE idempotency_keys (
+    actor TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    headers TEXT NOT NULL DEFAULT '{}',
+    body BLOB,
+    created_at DATETIME NOT NULL DEFAULT (now()),
+    PRIMARY KEY (actor, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
@@ -0,0 +1,3 @@
+-- Имя из X-User запросов без токена администратора. Оно не проверяется,
+-- поэтому хранится отдельно от actor.
+ALTER TABLE audit_log ADD COLUMN claimed_actor TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,195 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Hemisphere - буква полушария из строки координаты; 0, если ее нет
+type Hemisphere byte
+
+const (
+	North Hemisphere = 'N'
+	South Hemisphere = 'S'
+	East  Hemisphere = 'E'
+	West  Hemisphere = 'W'
+)
+
+// IsLatitude сообщает, что буква полушария относится к широте (N/S)
+func (h Hemisphere) IsLatitude() bool {
+	return h == North || h == South
+}
+
+// IsLongitude сообщает, что буква полушария относится к долготе (E/W)
+func (h Hemisphere) IsLongitude() bool {
+	return h == East || h == West
+}
+
+// symbolReplacer заменяет знаки градусов, минут и секунд на пробелы
+var symbolReplacer = strings.NewReplacer(
+	"°", " ", "º", " ", "˚", " ",
+	"′", " ", "’", " ", "'", " ",
+	"″", " ", "”", " ", "\"", " ",
+)
+
+// ParseCoordinate разбирает одну координату: десятичные градусы ("41.7167",
+// "-44.78") или градусы, минуты и секунды с буквой полушария ("41°43′N",
+// "44°47'12.5\" E", "N 41 43 12"). Для S и W значение отрицательное.
+func ParseCoordinate(s string) (float64, Hemisphere, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, fmt.Errorf("empty coordinate")
+	}
+
+	var hemisphere Hemisphere
+	upper := strings.ToUpper(s)
+	if h := Hemisphere(upper[len(upper)-1]); isHemisphere(h) {
+		hemisphere, s = h, s[:len(s)-1]
+	} else if h := Hemisphere(upper[0]); isHemisphere(h) {
+		hemisphere, s = h, s[1:]
+	}
+
+	fields := strings.Fields(symbolReplacer.Replace(s))
+	if len(fields) == 0 || len(fields) > 3 {
+		return 0, 0, fmt.Errorf("unrecognized coordinate format %q", s)
+	}
+
+	negative := strings.HasPrefix(fields[0], "-")
+	if negative && hemisphere != 0 {
+		return 0, 0, fmt.Errorf("coordinate has both a minus sign and hemisphere %c", hemisphere)
+	}
+
+	var parts [3]float64
+	for i, f := range fields {
+		if !isDecimal(f) {
+			return 0, 0, fmt.Errorf("unrecognized coordinate format %q", s)
+		}
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("unrecognized coordinate format %q", s)
+		}
+		if i > 0 && (v < 0 || v >= 60) {
+			return 0, 0, fmt.Errorf("minutes and seconds must be in [0, 60)")
+		}
+		// Дробными могут быть только последние из заданных частей
+		if i < len(fields)-1 && v != math.Trunc(v) {
+			return 0, 0, fmt.Errorf("only the last component of a DMS coordinate may be fractional")
+		}
+		parts[i] = math.Abs(v)
+	}
+
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if negative || hemisphere == South || hemisphere == West {
+		value = -value
+	}
+	return value, hemisphere, nil
+}
+
+// ParsePair разбирает пару координат, например "41°43′N 44°47′E" или
+// "41.7167, 44.7833". Без букв полушарий первой считается широта; с буквами
+// порядок может быть любым.
+func ParsePair(s string) (lat, lon float64, err error) {
+	first, second, ok := splitPair(s)
+	if !ok {
+		return 0, 0, fmt.Errorf("expected two coordinates, e.g. \"41°43′N 44°47′E\"")
+	}
+
+	a, ha, err := ParseCoordinate(first)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, hb, err := ParseCoordinate(second)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case ha.IsLongitude() && (hb.IsLatitude() || hb == 0):
+		return b, a, nil
+	case ha.IsLatitude() && hb.IsLatitude(), ha.IsLongitude() && hb.IsLongitude():
+		return 0, 0, fmt.Errorf("both coordinates have %s hemispheres", axisName(ha))
+	case hb.IsLatitude() && ha == 0:
+		return b, a, nil
+	default:
+		return a, b, nil
+	}
+}
+
+// splitPair делит строку по запятой или по буквам полушарий: после первой
+// буквы ("41°43′N 44°47′E") или перед второй ("N41°43′ E44°47′"). E между
+// цифрами ("1e1") - не буква полушария, а экспонента; ParseCoordinate ее
+// отвергает.
+func splitPair(s string) (string, string, bool) {
+	s = strings.TrimSpace(s)
+	if parts := strings.Split(s, ","); len(parts) == 2 {
+		return parts[0], parts[1], true
+	}
+
+	prefixed := s != "" && isHemisphere(Hemisphere(unicode.ToUpper(rune(s[0]))))
+	for i, r := range s {
+		if i == 0 || !isHemisphere(Hemisphere(unicode.ToUpper(r))) || isExponent(s, i) {
+			continue
+		}
+		if prefixed {
+			return s[:i], s[i:], true
+		}
+		if rest := strings.TrimSpace(s[i+1:]); rest != "" {
+			return s[:i+1], rest, true
+		}
+	}
+
+	// Два десятичных числа через пробел
+	if fields := strings.Fields(s); len(fields) == 2 {
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// isExponent сообщает, что s[i] - e или E экспоненты: после цифры и перед
+// цифрой или знаком с цифрой
+func isExponent(s string, i int) bool {
+	if s[i] != 'e' && s[i] != 'E' || !isDigit(s[i-1]) {
+		return false
+	}
+	next := s[i+1:]
+	if next != "" && (next[0] == '+' || next[0] == '-') {
+		next = next[1:]
+	}
+	return next != "" && isDigit(next[0])
+}
+
+// isDecimal сообщает, что f - десятичное число со знаком или без: без
+// экспоненты, шестнадцатеричной записи, Inf и NaN
+func isDecimal(f string) bool {
+	f = strings.TrimPrefix(strings.TrimPrefix(f, "-"), "+")
+	digits, dot := 0, false
+	for i := 0; i < len(f); i++ {
+		switch {
+		case isDigit(f[i]):
+			digits++
+		case f[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHemisphere(h Hemisphere) bool {
+	return h == North || h == South || h == East || h == West
+}
+
+func axisName(h Hemisphere) string {
+	if h.IsLatitude() {
+		return "latitude"
+	}
+	return "longitude"
+}
@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		in         string
+		want       float64
+		hemisphere Hemisphere
+	}{
+		{"41.7167", 41.7167, 0},
+		{"-44.78", -44.78, 0},
+		{"41°43′N", 41 + 43.0/60, North},
+		{"44°47'12.5\" E", 44 + 47.0/60 + 12.5/3600, East},
+		{"N 41 43 12", 41 + 43.0/60 + 12.0/3600, North},
+		{"33°52′S", -(33 + 52.0/60), South},
+		{"w 70.5", -70.5, West},
+	}
+	for _, tt := range tests {
+		got, hemisphere, err := ParseCoordinate(tt.in)
+		if err != nil {
+			t.Errorf("ParseCoordinate(%q): %v", tt.in, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 || hemisphere != tt.hemisphere {
+			t.Errorf("ParseCoordinate(%q) = %v, %c; want %v, %c", tt.in, got, hemisphere, tt.want, tt.hemisphere)
+		}
+	}
+}
+
+func TestParseCoordinateErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"N",
+		"-41°43′S",
+		"41°60′N",
+		"41.5°30′N",
+		"1 2 3 4",
+		"1e1",
+		"0x1p4",
+		"Inf",
+		"NaN",
+		"abc",
+	} {
+		if got, _, err := ParseCoordinate(in); err == nil {
+			t.Errorf("ParseCoordinate(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestParsePair(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lon float64
+	}{
+		{"41.7167, 44.7833", 41.7167, 44.7833},
+		{"41.7167 44.7833", 41.7167, 44.7833},
+		{"41°43′N 44°47′E", 41 + 43.0/60, 44 + 47.0/60},
+		{"N41°43′ E44°47′", 41 + 43.0/60, 44 + 47.0/60},
+		// С буквами полушарий порядок любой
+		{"44°47′E 41°43′N", 41 + 43.0/60, 44 + 47.0/60},
+		{"44.5E, 41.5", 41.5, 44.5},
+		{"44.5, 41.5N", 41.5, 44.5},
+		{"33.9S 151.2E", -33.9, 151.2},
+		{"22.9 S 43.2 W", -22.9, -43.2},
+	}
+	for _, tt := range tests {
+		lat, lon, err := ParsePair(tt.in)
+		if err != nil {
+			t.Errorf("ParsePair(%q): %v", tt.in, err)
+			continue
+		}
+		if math.Abs(lat-tt.lat) > 1e-9 || math.Abs(lon-tt.lon) > 1e-9 {
+			t.Errorf("ParsePair(%q) = %v, %v; want %v, %v", tt.in, lat, lon, tt.lat, tt.lon)
+		}
+	}
+}
+
+func TestParsePairErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"41.7167",
+		"1 2 3",
+		"41N 44N",
+		"44E 41W",
+		"1e1 2e1",
+		"1E1, 2E1",
+		"1e+1 2e-1",
+	} {
+		if lat, lon, err := ParsePair(in); err == nil {
+			t.Errorf("ParsePair(%q) = %v, %v; want error", in, lat, lon)
+		}
+	}
+}
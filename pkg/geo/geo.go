@@ -0,0 +1,261 @@
+// Package geo загружает границы стран из GeoJSON и отвечает на вопрос,
+// в какой стране лежит точка.
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+const earthRadiusKm = 6371.0
+
+// cellSize - размер ячейки сетки пространственного индекса в градусах
+const cellSize = 5.0
+
+type Point struct {
+	Lon float64
+	Lat float64
+}
+
+type bbox struct {
+	minLon, minLat, maxLon, maxLat float64
+}
+
+func (b bbox) contains(p Point) bool {
+	return p.Lon >= b.minLon && p.Lon <= b.maxLon && p.Lat >= b.minLat && p.Lat <= b.maxLat
+}
+
+// polygon - внешний контур и дыры (анклавы) в порядке GeoJSON
+type polygon struct {
+	code  string
+	rings [][]Point
+	box   bbox
+}
+
+// Index - сетка ячеек cellSize×cellSize, в каждой - полигоны, чей bbox ее пересекает
+type Index struct {
+	polygons []*polygon
+	cells    map[[2]int][]int
+	codes    map[string]bool
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// codeProperties - свойства с кодом ISO 3166-1 alpha-2 в порядке приоритета.
+// В Natural Earth у части стран ISO_A2 равен "-99", тогда используется ISO_A2_EH.
+var codeProperties = []string{"ISO_A2", "ISO_A2_EH", "iso_a2", "iso2", "ISO3166-1-Alpha-2"}
+
+// LoadFile читает FeatureCollection с полигонами стран
+func LoadFile(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read boundaries: %w", err)
+	}
+	return Load(data)
+}
+
+func Load(data []byte) (*Index, error) {
+	var fc featureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse boundaries: %w", err)
+	}
+
+	idx := &Index{cells: make(map[[2]int][]int), codes: make(map[string]bool)}
+	for i, f := range fc.Features {
+		code := featureCode(f.Properties)
+		if code == "" {
+			continue
+		}
+
+		var polygons [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				return nil, fmt.Errorf("feature %d (%s): %w", i, code, err)
+			}
+			polygons = append(polygons, p)
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("feature %d (%s): %w", i, code, err)
+			}
+		default:
+			continue
+		}
+
+		for _, rings := range polygons {
+			idx.add(code, rings)
+		}
+	}
+
+	if len(idx.polygons) == 0 {
+		return nil, fmt.Errorf("boundaries contain no country polygons")
+	}
+	return idx, nil
+}
+
+// Has сообщает, есть ли в индексе границы страны
+func (idx *Index) Has(code string) bool {
+	return idx.codes[strings.ToUpper(code)]
+}
+
+// Locate возвращает коды стран, внутри которых лежит точка. Обычно это одна
+// страна; несколько - на спорных территориях с перекрывающимися границами.
+func (idx *Index) Locate(lat, lon float64) []string {
+	p := Point{Lon: lon, Lat: lat}
+	seen := make(map[string]bool)
+	var codes []string
+	for _, i := range idx.cells[cellOf(p)] {
+		poly := idx.polygons[i]
+		if seen[poly.code] || !poly.box.contains(p) || !poly.contains(p) {
+			continue
+		}
+		seen[poly.code] = true
+		codes = append(codes, poly.code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// DistanceKm возвращает расстояние от точки до страны в километрах
+// (0 - точка внутри). Для стран без границ в индексе возвращает ok = false.
+func (idx *Index) DistanceKm(code string, lat, lon float64) (float64, bool) {
+	code = strings.ToUpper(code)
+	if !idx.codes[code] {
+		return 0, false
+	}
+
+	p := Point{Lon: lon, Lat: lat}
+	best := math.Inf(1)
+	for _, poly := range idx.polygons {
+		if poly.code != code {
+			continue
+		}
+		if poly.box.contains(p) && poly.contains(p) {
+			return 0, true
+		}
+		best = math.Min(best, poly.distanceKm(p))
+	}
+	return best, true
+}
+
+func (idx *Index) add(code string, coords [][][2]float64) {
+	if len(coords) == 0 || len(coords[0]) < 3 {
+		return
+	}
+
+	poly := &polygon{code: code}
+	poly.box = bbox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, ring := range coords {
+		points := make([]Point, len(ring))
+		for i, c := range ring {
+			points[i] = Point{Lon: c[0], Lat: c[1]}
+		}
+		poly.rings = append(poly.rings, points)
+	}
+	for _, p := range poly.rings[0] {
+		poly.box.minLon = math.Min(poly.box.minLon, p.Lon)
+		poly.box.minLat = math.Min(poly.box.minLat, p.Lat)
+		poly.box.maxLon = math.Max(poly.box.maxLon, p.Lon)
+		poly.box.maxLat = math.Max(poly.box.maxLat, p.Lat)
+	}
+
+	id := len(idx.polygons)
+	idx.polygons = append(idx.polygons, poly)
+	idx.codes[code] = true
+
+	minCell := cellOf(Point{Lon: poly.box.minLon, Lat: poly.box.minLat})
+	maxCell := cellOf(Point{Lon: poly.box.maxLon, Lat: poly.box.maxLat})
+	for x := minCell[0]; x <= maxCell[0]; x++ {
+		for y := minCell[1]; y <= maxCell[1]; y++ {
+			idx.cells[[2]int{x, y}] = append(idx.cells[[2]int{x, y}], id)
+		}
+	}
+}
+
+// contains - правило четности (ray casting): точка внутри внешнего контура
+// и вне всех дыр
+func (poly *polygon) contains(p Point) bool {
+	if !ringContains(poly.rings[0], p) {
+		return false
+	}
+	for _, hole := range poly.rings[1:] {
+		if ringContains(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func ringContains(ring []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distanceKm - минимальное расстояние от точки до ребер полигона. Для коротких
+// ребер достаточно локальной равнопромежуточной проекции вокруг точки.
+func (poly *polygon) distanceKm(p Point) float64 {
+	kx := earthRadiusKm * math.Pi / 180 * math.Cos(p.Lat*math.Pi/180)
+	ky := earthRadiusKm * math.Pi / 180
+	project := func(q Point) (float64, float64) {
+		dLon := q.Lon - p.Lon
+		if dLon > 180 {
+			dLon -= 360
+		} else if dLon < -180 {
+			dLon += 360
+		}
+		return dLon * kx, (q.Lat - p.Lat) * ky
+	}
+
+	best := math.Inf(1)
+	for _, ring := range poly.rings {
+		for i := 0; i+1 < len(ring); i++ {
+			ax, ay := project(ring[i])
+			bx, by := project(ring[i+1])
+			best = math.Min(best, segmentDistance(ax, ay, bx, by))
+		}
+	}
+	return best
+}
+
+// segmentDistance - расстояние от начала координат до отрезка AB
+func segmentDistance(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+func cellOf(p Point) [2]int {
+	return [2]int{int(math.Floor(p.Lon / cellSize)), int(math.Floor(p.Lat / cellSize))}
+}
+
+func featureCode(props map[string]interface{}) string {
+	for _, name := range codeProperties {
+		if v, ok := props[name].(string); ok && len(v) == 2 {
+			return strings.ToUpper(v)
+		}
+	}
+	return ""
+}
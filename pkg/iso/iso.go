@@ -0,0 +1,165 @@
+// Package iso содержит встроенные справочники ISO 3166-1 (страны),
+// ISO 4217 (валюты) и ISO 639 (языки).
+package iso
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed data/*.csv
+var data embed.FS
+
+type Country struct {
+	Alpha2       string `json:"alpha2"`
+	Alpha3       string `json:"alpha3"`
+	Numeric      string `json:"numeric"`
+	Name         string `json:"name"`
+	OfficialName string `json:"official_name,omitempty"`
+	CommonName   string `json:"common_name,omitempty"`
+}
+
+type Currency struct {
+	Code    string `json:"code"`
+	Numeric string `json:"numeric"`
+	Name    string `json:"name"`
+}
+
+// Language - язык ISO 639. Code - код ISO 639-1, если он есть, иначе ISO 639-2/T.
+type Language struct {
+	Code          string `json:"code"`
+	Alpha2        string `json:"alpha2,omitempty"`
+	Alpha3        string `json:"alpha3"`
+	Bibliographic string `json:"bibliographic,omitempty"`
+	Name          string `json:"name"`
+}
+
+type tables struct {
+	countries  []Country
+	currencies []Currency
+	languages  []Language
+
+	countryByCode  map[string]Country
+	currencyByCode map[string]Currency
+	languageByCode map[string]Language
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *tables
+)
+
+// Countries возвращает все страны ISO 3166-1 в порядке кода alpha-2
+func Countries() []Country {
+	return load().countries
+}
+
+// Currencies возвращает все валюты ISO 4217 в порядке кода
+func Currencies() []Currency {
+	return load().currencies
+}
+
+// Languages возвращает все языки ISO 639 в порядке кода alpha-3
+func Languages() []Language {
+	return load().languages
+}
+
+// LookupCountry ищет страну по коду alpha-2 или alpha-3 без учета регистра
+func LookupCountry(code string) (Country, bool) {
+	c, ok := load().countryByCode[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// LookupCurrency ищет валюту по трехбуквенному коду без учета регистра
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := load().currencyByCode[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// LookupLanguage ищет язык по коду ISO 639-1, 639-2/T или 639-2/B без учета регистра
+func LookupLanguage(code string) (Language, bool) {
+	l, ok := load().languageByCode[strings.ToLower(strings.TrimSpace(code))]
+	return l, ok
+}
+
+func load() *tables {
+	loadOnce.Do(func() {
+		t, err := parse()
+		if err != nil {
+			// Справочники встроены в бинарник, ошибка возможна только при поломке сборки
+			panic(err)
+		}
+		loaded = t
+	})
+	return loaded
+}
+
+func parse() (*tables, error) {
+	t := &tables{
+		countryByCode:  make(map[string]Country),
+		currencyByCode: make(map[string]Currency),
+		languageByCode: make(map[string]Language),
+	}
+
+	countries, err := readCSV("data/iso3166-1.csv")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range countries {
+		c := Country{Alpha2: r[0], Alpha3: r[1], Numeric: r[2], Name: r[3], OfficialName: r[4], CommonName: r[5]}
+		t.countries = append(t.countries, c)
+		t.countryByCode[c.Alpha2] = c
+		t.countryByCode[c.Alpha3] = c
+	}
+
+	currencies, err := readCSV("data/iso4217.csv")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range currencies {
+		c := Currency{Code: r[0], Numeric: r[1], Name: r[2]}
+		t.currencies = append(t.currencies, c)
+		t.currencyByCode[c.Code] = c
+	}
+
+	languages, err := readCSV("data/iso639.csv")
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range languages {
+		l := Language{Alpha2: r[0], Alpha3: r[1], Bibliographic: r[2], Name: r[3]}
+		l.Code = l.Alpha3
+		if l.Alpha2 != "" {
+			l.Code = l.Alpha2
+		}
+		t.languages = append(t.languages, l)
+		for _, code := range []string{l.Alpha2, l.Alpha3, l.Bibliographic} {
+			if code != "" {
+				t.languageByCode[code] = l
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// readCSV читает встроенный CSV-файл без строки заголовка
+func readCSV(name string) ([][]string, error) {
+	f, err := data.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	return records[1:], nil
+}
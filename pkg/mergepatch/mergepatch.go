@@ -0,0 +1,79 @@
+// Package mergepatch применяет JSON Merge Patch (RFC 7396) к JSON-документам.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ContentType - MIME-тип тела merge patch
+const ContentType = "application/merge-patch+json"
+
+// ErrNotObject возвращается, если патч не является JSON-объектом. RFC 7396
+// допускает патч-скаляр, заменяющий документ целиком, но для ресурсов API
+// это бессмысленно.
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// Apply применяет patch к doc: ключи со значением null удаляются, вложенные
+// объекты объединяются рекурсивно, остальные значения заменяются.
+// Числа сохраняются без потери точности.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	p, err := Parse(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// Parse разбирает патч и проверяет, что это объект
+func Parse(patch []byte) (map[string]interface{}, error) {
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	obj, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObject
+	}
+	return obj, nil
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = merge(targetObj[key], value)
+	}
+	return targetObj
+}
+
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
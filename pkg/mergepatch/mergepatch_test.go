@@ -0,0 +1,74 @@
+package mergepatch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"replace value", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add key", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null removes key", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"null for missing key", `{"a":"b"}`, `{"c":null}`, `{"a":"b"}`},
+		{"array replaced", `{"a":["b"]}`, `{"a":["c","d"]}`, `{"a":["c","d"]}`},
+		{"nested merge", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"f"}}`, `{"a":{"b":"f","d":"e"}}`},
+		{"nested null", `{"a":{"b":"c","d":"e"}}`, `{"a":{"d":null}}`, `{"a":{"b":"c"}}`},
+		{"object replaces scalar", `{"a":"b"}`, `{"a":{"c":"d","e":null}}`, `{"a":{"c":"d"}}`},
+		{"scalar replaces object", `{"a":{"b":"c"}}`, `{"a":1}`, `{"a":1}`},
+		{"empty patch", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+		{"large number kept", `{"a":1}`, `{"b":12345678901234567890}`, `{"a":1,"b":12345678901234567890}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Fatalf("Apply(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, patch string
+		notObject        bool
+	}{
+		{"scalar patch", `{"a":"b"}`, `"c"`, true},
+		{"array patch", `{"a":"b"}`, `["c"]`, true},
+		{"null patch", `{"a":"b"}`, `null`, true},
+		{"invalid patch", `{"a":"b"}`, `{"a":`, false},
+		{"trailing data", `{"a":"b"}`, `{"a":"c"} {}`, false},
+		{"invalid document", `{"a":`, `{"a":"c"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatal("Apply succeeded, want error")
+			}
+			if errors.Is(err, ErrNotObject) != tt.notObject {
+				t.Fatalf("Apply error = %v, ErrNotObject = %v", err, tt.notObject)
+			}
+		})
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb interface{}
+	if err := decode(a, &va); err != nil {
+		t.Fatalf("decode %s: %v", a, err)
+	}
+	if err := decode(b, &vb); err != nil {
+		t.Fatalf("decode %s: %v", b, err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
@@ -1,32 +1,82 @@
 package postgres
 
 import (
-	"fmt"
+	"context"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
+// pingTimeout ограничивает проверку подключения при открытии БД
+const pingTimeout = 10 * time.Second
+
 type Config struct {
+	// DSN - строка подключения целиком (postgres://... или "host=... dbname=...").
+	// Если задана, Host, Port, Username, Password, DBName и SSLMode не используются.
+	DSN      string
 	Host     string
 	Port     string
 	Username string
 	Password string
 	DBName   string
 	SSLMode  string
+	Pool     Pool
+}
+
+// Pool - настройки пула соединений; нулевые значения оставляют настройки
+// database/sql по умолчанию
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// dsn возвращает строку подключения. Из отдельных полей собирается URL, в
+// котором экранированы пробелы и спецсимволы в пароле.
+func (c Config) dsn() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   net.JoinHostPort(c.Host, c.Port),
+		Path:   "/" + c.DBName,
+	}
+	if c.Password != "" {
+		u.User = url.UserPassword(c.Username, c.Password)
+	} else if c.Username != "" {
+		u.User = url.User(c.Username)
+	}
+	if c.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+	}
+	return u.String()
 }
 
 func NewDB(cfg Config) (*sqlx.DB, error) {
 	logrus.Info("Connecting to database...")
-	db, err := sqlx.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.Username, cfg.DBName, cfg.Password, cfg.SSLMode))
+	db, err := sqlx.Open("postgres", cfg.dsn())
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.Ping()
-	if err != nil {
+	db.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
+	if cfg.Pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(cfg.Pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Pool.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,136 @@
+// Package sqlite открывает БД SQLite для локальной разработки и автономных
+// сборок сервиса. Драйвер modernc.org/sqlite написан на Go и не требует cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"modernc.org/sqlite"
+)
+
+// DriverName - имя драйвера в database/sql и sqlx
+const DriverName = "sqlite"
+
+// TimeFormat - формат, в котором драйвер записывает время (_time_format=sqlite)
+// и возвращает его now(). Все время хранится в UTC, поэтому строки
+// сравниваются в хронологическом порядке.
+const TimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+func init() {
+	// Аналоги функций PostgreSQL, которые используют запросы репозиториев
+	sqlite.MustRegisterScalarFunction("now", 0, func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(TimeFormat), nil
+	})
+	// lower во встроенной реализации SQLite меняет регистр только у ASCII
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return strings.ToLower(s), nil
+	})
+}
+
+type Config struct {
+	// Path - путь к файлу БД; файл создается при первом запуске
+	Path string
+}
+
+func NewDB(cfg Config) (*sqlx.DB, error) {
+	logrus.Infof("Opening SQLite database %s...", cfg.Path)
+
+	// Внешние ключи в SQLite по умолчанию выключены; без них не работают
+	// каскадное удаление и проверка ссылок. _txlock=immediate берет блокировку
+	// записи в начале транзакции, а busy_timeout ждет ее вместо ошибки
+	// "database is locked".
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	db, err := sqlx.Open(DriverName, "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Migrate применяет еще не примененные миграции *.up.sql из migrations по
+// порядку имен, каждую в своей транзакции. Примененные миграции записываются
+// в таблицу schema_migrations.
+func Migrate(ctx context.Context, db *sqlx.DB, migrations fs.FS) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL DEFAULT (now())
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	// fs.Glob возвращает имена по возрастанию
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return err
+	}
+
+	var applied []string
+	if err := db.SelectContext(ctx, &applied, "SELECT version FROM schema_migrations"); err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	done := make(map[string]bool, len(applied))
+	for _, version := range applied {
+		done[version] = true
+	}
+
+	for _, file := range files {
+		version := strings.TrimSuffix(file, ".up.sql")
+		if done[version] {
+			continue
+		}
+
+		script, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, db, version, string(script)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+		logrus.Infof("Applied migration %s", version)
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sqlx.DB, version, script string) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,86 @@
+// Package tracing настраивает трассировку OpenTelemetry: экспорт спанов по
+// OTLP, распространение контекста трассы через заголовки traceparent и ID
+// трассы в логах.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName - имя трассировщика, которым создаются спаны сервиса
+const InstrumentationName = "github.com/ShekleinAleksey/top-places"
+
+type Config struct {
+	ServiceName string
+	// Endpoint - адрес OTLP/HTTP коллектора, например http://localhost:4318.
+	// Если не задан, спаны никуда не отправляются.
+	Endpoint string
+	// SampleRatio - доля трассируемых запросов от 0 до 1. Запросы, пришедшие
+	// с traceparent, трассируются по решению вызывающей стороны.
+	SampleRatio float64
+}
+
+// Setup устанавливает глобальный провайдер трассировки и возвращает функцию,
+// которая при остановке сервиса отправляет накопленные спаны
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := NewProvider(exporter, cfg)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider создает провайдер, отправляющий спаны в exporter. Отдельно от
+// Setup он нужен, чтобы подставить другой экспортер, например
+// tracetest.NewInMemoryExporter.
+func NewProvider(exporter sdktrace.SpanExporter, cfg Config) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+}
+
+// Start начинает дочерний спан спана из ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, opts...)
+}
+
+// LogHook добавляет ID трассы и спана в записи, созданные через
+// logrus.WithContext, чтобы по записи в логе можно было найти трассу
+type LogHook struct{}
+
+func (LogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (LogHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	span := trace.SpanContextFromContext(entry.Context)
+	if !span.IsValid() {
+		return nil
+	}
+	entry.Data["trace_id"] = span.TraceID().String()
+	entry.Data["span_id"] = span.SpanID().String()
+	return nil
+}
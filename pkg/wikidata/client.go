@@ -0,0 +1,146 @@
+package wikidata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultEndpoint = "https://www.wikidata.org"
+
+// maxIDsPerRequest - ограничение wbgetentities на количество сущностей в запросе
+const maxIDsPerRequest = 50
+
+// Client обращается к HTTP API Wikidata (или совместимому мок-серверу)
+type Client struct {
+	endpoint   string
+	lang       string
+	httpClient *http.Client
+}
+
+func NewClient(endpoint, lang string) *Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Client{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		lang:       lang,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) Name() string {
+	return "wikidata-api"
+}
+
+func (c *Client) Lookup(ctx context.Context, ref Ref) (*Entity, error) {
+	if ref.ID != "" {
+		entities, err := c.getEntities(ctx, []string{ref.ID}, "labels|descriptions|claims")
+		if err != nil {
+			return nil, err
+		}
+		if e, ok := entities[ref.ID]; ok {
+			return e, nil
+		}
+		return nil, ErrNotFound
+	}
+
+	ids, err := c.search(ctx, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrNotFound
+	}
+	entities, err := c.getEntities(ctx, ids, "labels|descriptions|claims")
+	if err != nil {
+		return nil, err
+	}
+	// Поиск возвращает кандидатов по релевантности - берем первую страну
+	for _, id := range ids {
+		if e, ok := entities[id]; ok && e.IsCountry() {
+			return e, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (c *Client) Labels(ctx context.Context, ids []string) (map[string]string, error) {
+	result := make(map[string]string, len(ids))
+	for start := 0; start < len(ids); start += maxIDsPerRequest {
+		end := min(start+maxIDsPerRequest, len(ids))
+		entities, err := c.getEntities(ctx, ids[start:end], "labels")
+		if err != nil {
+			return nil, err
+		}
+		for id, e := range entities {
+			result[id] = e.Label(c.lang)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) search(ctx context.Context, name string) ([]string, error) {
+	params := url.Values{
+		"action":   {"wbsearchentities"},
+		"search":   {name},
+		"language": {c.lang},
+		"type":     {"item"},
+		"limit":    {"10"},
+		"format":   {"json"},
+	}
+	var resp struct {
+		Search []struct {
+			ID string `json:"id"`
+		} `json:"search"`
+	}
+	if err := c.get(ctx, params, &resp); err != nil {
+		return nil, fmt.Errorf("failed to search wikidata: %w", err)
+	}
+
+	ids := make([]string, 0, len(resp.Search))
+	for _, s := range resp.Search {
+		ids = append(ids, s.ID)
+	}
+	return ids, nil
+}
+
+func (c *Client) getEntities(ctx context.Context, ids []string, props string) (map[string]*Entity, error) {
+	params := url.Values{
+		"action":    {"wbgetentities"},
+		"ids":       {strings.Join(ids, "|")},
+		"props":     {props},
+		"languages": {c.lang},
+		"format":    {"json"},
+	}
+	var resp struct {
+		Entities map[string]*Entity `json:"entities"`
+	}
+	if err := c.get(ctx, params, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get wikidata entities: %w", err)
+	}
+	return resp.Entities, nil
+}
+
+func (c *Client) get(ctx context.Context, params url.Values, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/w/api.php?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "top-places-enrichment/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -0,0 +1,146 @@
+package wikidata
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DumpSource читает локальный JSON-дамп Wikidata (формат latest-all.json[.gz]:
+// массив сущностей, по одной на строку). Дамп загружается один раз при первом обращении.
+type DumpSource struct {
+	path string
+	lang string
+
+	once    sync.Once
+	loadErr error
+	byID    map[string]*Entity
+	byName  map[string]*Entity
+	labels  map[string]string
+}
+
+func NewDumpSource(path, lang string) *DumpSource {
+	return &DumpSource{path: path, lang: lang}
+}
+
+func (s *DumpSource) Name() string {
+	return "wikidata-dump"
+}
+
+func (s *DumpSource) Lookup(ctx context.Context, ref Ref) (*Entity, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	if ref.ID != "" {
+		if e, ok := s.byID[ref.ID]; ok {
+			return e, nil
+		}
+	}
+	if e, ok := s.byName[normalize(ref.Name)]; ok {
+		return e, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *DumpSource) Labels(ctx context.Context, ids []string) (map[string]string, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if label, ok := s.labels[id]; ok {
+			result[id] = label
+		}
+	}
+	return result, nil
+}
+
+func (s *DumpSource) load(ctx context.Context) error {
+	s.once.Do(func() {
+		s.loadErr = s.index(ctx)
+	})
+	return s.loadErr
+}
+
+// index проходит дамп дважды: сначала собирает страны и ссылки на другие
+// сущности (столицы, языки, валюты), затем - метки этих сущностей
+func (s *DumpSource) index(ctx context.Context) error {
+	s.byID = make(map[string]*Entity)
+	s.byName = make(map[string]*Entity)
+	s.labels = make(map[string]string)
+	referenced := make(map[string]bool)
+
+	err := s.scan(ctx, func(e *Entity) {
+		if !e.IsCountry() {
+			return
+		}
+		s.byID[e.ID] = e
+		for _, name := range e.Names() {
+			if _, exists := s.byName[name]; !exists {
+				s.byName[name] = e
+			}
+		}
+		for _, prop := range []string{PropCapital, PropOfficialLanguage, PropCurrency} {
+			for _, id := range e.EntityIDs(prop) {
+				referenced[id] = true
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.scan(ctx, func(e *Entity) {
+		if referenced[e.ID] {
+			s.labels[e.ID] = e.Label(s.lang)
+		}
+	})
+}
+
+func (s *DumpSource) scan(ctx context.Context, fn func(e *Entity)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open wikidata dump: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(s.path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read wikidata dump: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 0; scanner.Scan(); line++ {
+		if line%10000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		data := bytes.TrimSuffix(bytes.TrimSpace(scanner.Bytes()), []byte(","))
+		if len(data) == 0 || bytes.Equal(data, []byte("[")) || bytes.Equal(data, []byte("]")) {
+			continue
+		}
+		var e Entity
+		if err := json.Unmarshal(data, &e); err != nil {
+			return fmt.Errorf("failed to parse wikidata dump line %d: %w", line+1, err)
+		}
+		fn(&e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read wikidata dump: %w", err)
+	}
+	return nil
+}
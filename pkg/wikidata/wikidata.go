@@ -0,0 +1,216 @@
+package wikidata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Свойства Wikidata, которые используются при обогащении стран
+const (
+	PropInstanceOf        = "P31"
+	PropCapital           = "P36"
+	PropOfficialLanguage  = "P37"
+	PropCurrency          = "P38"
+	PropFlagImage         = "P41"
+	PropPopulation        = "P1082"
+	PropArea              = "P2046"
+	PropPointInTime       = "P585"
+	commonsFilePathPrefix = "https://commons.wikimedia.org/wiki/Special:FilePath/"
+)
+
+// countryClasses - значения P31, по которым сущность считается страной
+var countryClasses = map[string]bool{
+	"Q6256":    true, // country
+	"Q3624078": true, // sovereign state
+}
+
+var ErrNotFound = errors.New("wikidata entity not found")
+
+// Ref описывает, как искать страну: по QID или по названию
+type Ref struct {
+	ID   string
+	Name string
+}
+
+// Source - источник сущностей Wikidata (локальный дамп или HTTP API)
+type Source interface {
+	Lookup(ctx context.Context, ref Ref) (*Entity, error)
+	Labels(ctx context.Context, ids []string) (map[string]string, error)
+	Name() string
+}
+
+type Entity struct {
+	ID           string                 `json:"id"`
+	Labels       map[string]LangValue   `json:"labels"`
+	Descriptions map[string]LangValue   `json:"descriptions"`
+	Aliases      map[string][]LangValue `json:"aliases"`
+	Claims       map[string][]Statement `json:"claims"`
+}
+
+type LangValue struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+type Statement struct {
+	Rank       string            `json:"rank"`
+	MainSnak   Snak              `json:"mainsnak"`
+	Qualifiers map[string][]Snak `json:"qualifiers"`
+}
+
+type Snak struct {
+	SnakType  string     `json:"snaktype"`
+	Property  string     `json:"property"`
+	DataValue *DataValue `json:"datavalue"`
+}
+
+type DataValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (e *Entity) Label(lang string) string {
+	return e.Labels[lang].Value
+}
+
+func (e *Entity) Description(lang string) string {
+	return e.Descriptions[lang].Value
+}
+
+// IsCountry сообщает, является ли сущность страной (по P31)
+func (e *Entity) IsCountry() bool {
+	for _, id := range e.EntityIDs(PropInstanceOf) {
+		if countryClasses[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// Names возвращает все метки и алиасы сущности в нормализованном виде
+func (e *Entity) Names() []string {
+	var names []string
+	for _, l := range e.Labels {
+		names = append(names, normalize(l.Value))
+	}
+	for _, aliases := range e.Aliases {
+		for _, a := range aliases {
+			names = append(names, normalize(a.Value))
+		}
+	}
+	return names
+}
+
+// EntityIDs возвращает QID значений свойства; устаревшие утверждения пропускаются
+func (e *Entity) EntityIDs(prop string) []string {
+	var ids []string
+	for _, st := range e.statements(prop) {
+		var v struct {
+			ID string `json:"id"`
+		}
+		if st.MainSnak.DataValue == nil || st.MainSnak.DataValue.Type != "wikibase-entityid" {
+			continue
+		}
+		if err := json.Unmarshal(st.MainSnak.DataValue.Value, &v); err == nil && v.ID != "" {
+			ids = append(ids, v.ID)
+		}
+	}
+	return ids
+}
+
+// Quantity возвращает актуальное числовое значение свойства
+func (e *Entity) Quantity(prop string) (float64, bool) {
+	st := e.current(prop)
+	if st == nil || st.MainSnak.DataValue == nil || st.MainSnak.DataValue.Type != "quantity" {
+		return 0, false
+	}
+	var v struct {
+		Amount string `json:"amount"`
+	}
+	if err := json.Unmarshal(st.MainSnak.DataValue.Value, &v); err != nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimPrefix(v.Amount, "+"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// String возвращает актуальное строковое значение свойства
+func (e *Entity) String(prop string) string {
+	st := e.current(prop)
+	if st == nil || st.MainSnak.DataValue == nil || st.MainSnak.DataValue.Type != "string" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(st.MainSnak.DataValue.Value, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+// CommonsFileURL строит ссылку на файл Wikimedia Commons по его имени
+func CommonsFileURL(name string) string {
+	if name == "" {
+		return ""
+	}
+	return commonsFilePathPrefix + url.PathEscape(strings.ReplaceAll(name, " ", "_"))
+}
+
+// EntityURL возвращает каноническую ссылку на сущность
+func EntityURL(id string) string {
+	return "https://www.wikidata.org/wiki/" + id
+}
+
+func (e *Entity) statements(prop string) []Statement {
+	var result []Statement
+	for _, st := range e.Claims[prop] {
+		if st.Rank == "deprecated" || st.MainSnak.SnakType != "value" {
+			continue
+		}
+		result = append(result, st)
+	}
+	return result
+}
+
+// current выбирает утверждение с рангом preferred, иначе самое свежее по P585
+func (e *Entity) current(prop string) *Statement {
+	statements := e.statements(prop)
+	var best *Statement
+	var bestTime string
+	for i := range statements {
+		st := &statements[i]
+		if st.Rank == "preferred" {
+			return st
+		}
+		t := st.pointInTime()
+		if best == nil || t > bestTime {
+			best, bestTime = st, t
+		}
+	}
+	return best
+}
+
+func (st *Statement) pointInTime() string {
+	for _, q := range st.Qualifiers[PropPointInTime] {
+		if q.DataValue == nil {
+			continue
+		}
+		var v struct {
+			Time string `json:"time"`
+		}
+		if err := json.Unmarshal(q.DataValue.Value, &v); err == nil {
+			return v.Time
+		}
+	}
+	return ""
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}